@@ -0,0 +1,233 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/golibrary/mylog"
+	"github.com/ddkwork/toolbox/collection/slice"
+)
+
+// DefaultAnimationFrameRate is the number of frames per second animations are advanced at when the refresh rate of the
+// display cannot be determined.
+const DefaultAnimationFrameRate = 60
+
+var (
+	runningAnimations     []*AnimationHandle
+	animationFrameWindows = make(map[*Window]bool)
+	animationTickPending  bool
+)
+
+// Animation is implemented by values that change over time, such as a Tween, Spring, or Timeline.
+type Animation interface {
+	// Advance the animation to the given amount of time since it was started. Returns true once the animation has
+	// finished.
+	Advance(elapsed time.Duration) bool
+}
+
+// TimedAnimation is an Animation that knows how long it will run for.
+type TimedAnimation interface {
+	Animation
+	// TotalDuration returns the total amount of time the animation will run for, or a negative value if it runs
+	// forever.
+	TotalDuration() time.Duration
+}
+
+// Animatable defines the types of values that can be animated.
+type Animatable interface {
+	float32 | Color | Point | Size | Rect
+}
+
+// AnimationHandle provides control over an animation started with Animate().
+type AnimationHandle struct {
+	panel        *Panel
+	animation    Animation
+	doneCallback func(finished bool)
+	start        time.Time
+	attached     bool
+	running      bool
+}
+
+// Animate starts running the animation, advancing it once per frame on the UI thread. If target is not nil, it will be
+// marked for redraw each time the animation advances, the animation will pause while the target's window is hidden or
+// minimized, and the animation will be stopped if the target is removed from the window it was in. doneCallback, if
+// not nil, is called when the animation ends, with finished set to true if it ran to completion and false if it was
+// stopped early. Must be called on the UI thread.
+func Animate(target Paneler, animation Animation, doneCallback func(finished bool)) *AnimationHandle {
+	h := &AnimationHandle{
+		animation:    animation,
+		doneCallback: doneCallback,
+		start:        time.Now(),
+		running:      true,
+	}
+	if target != nil {
+		h.panel = target.AsPanel()
+	}
+	runningAnimations = append(runningAnimations, h)
+	h.advance(h.start)
+	scheduleAnimationTick()
+	return h
+}
+
+// Running returns true if the animation is still running.
+func (h *AnimationHandle) Running() bool {
+	return h.running
+}
+
+// Animation returns the animation being run.
+func (h *AnimationHandle) Animation() Animation {
+	return h.animation
+}
+
+// Stop the animation, leaving its values wherever they currently are.
+func (h *AnimationHandle) Stop() {
+	h.finish(false)
+}
+
+func (h *AnimationHandle) advance(now time.Time) {
+	if !h.running {
+		return
+	}
+	if h.panel != nil {
+		if h.window() != nil {
+			h.attached = true
+		} else if h.attached {
+			h.finish(false)
+			return
+		}
+	}
+	done := false
+	mylog.Call(func() { done = h.animation.Advance(now.Sub(h.start)) })
+	if h.panel != nil {
+		h.panel.MarkForRedraw()
+	}
+	if done {
+		h.finish(true)
+	}
+}
+
+// window returns the valid window the target panel is in, if any.
+func (h *AnimationHandle) window() *Window {
+	if h.panel != nil {
+		if w := h.panel.Window(); w != nil && w.IsValid() {
+			return w
+		}
+	}
+	return nil
+}
+
+func (h *AnimationHandle) finish(finished bool) {
+	if !h.running {
+		return
+	}
+	h.running = false
+	for i, one := range runningAnimations {
+		if one == h {
+			runningAnimations = slice.ZeroedDelete(runningAnimations, i, i+1)
+			break
+		}
+	}
+	if h.doneCallback != nil {
+		mylog.Call(func() { h.doneCallback(finished) })
+	}
+}
+
+// scheduleAnimationTick arranges for running animations to be advanced. Animations whose target is in a window are
+// advanced by that window's frame callbacks, so that they run at the display's refresh rate and pause while the window
+// is hidden or minimized. All others are advanced by a timer.
+func scheduleAnimationTick() {
+	needTimer := false
+	for _, h := range runningAnimations {
+		if w := h.window(); w != nil {
+			if !animationFrameWindows[w] {
+				animationFrameWindows[w] = true
+				w.RequestAnimationFrame(func(frameTime time.Time) {
+					delete(animationFrameWindows, w)
+					advanceAnimations(w, frameTime)
+				})
+			}
+		} else {
+			needTimer = true
+		}
+	}
+	for w := range animationFrameWindows {
+		if !w.IsValid() {
+			delete(animationFrameWindows, w)
+		}
+	}
+	if needTimer && !animationTickPending {
+		animationTickPending = true
+		InvokeTaskAfter(func() {
+			animationTickPending = false
+			advanceAnimations(nil, time.Now())
+		}, time.Second/DefaultAnimationFrameRate)
+	}
+}
+
+// advanceAnimations advances the running animations whose target is in the window, or those whose target is not in a
+// window if wnd is nil.
+func advanceAnimations(wnd *Window, now time.Time) {
+	list := make([]*AnimationHandle, len(runningAnimations))
+	copy(list, runningAnimations)
+	for _, h := range list {
+		if h.window() == wnd {
+			h.advance(now)
+		}
+	}
+	scheduleAnimationTick()
+}
+
+// Lerp returns the value that is the fraction t of the way from 'from' to 'to'. Colors are interpolated per channel,
+// including alpha.
+func Lerp[T Animatable](from, to T, t float32) T {
+	a := animatableComponents(from)
+	b := animatableComponents(to)
+	for i := range a {
+		a[i] += (b[i] - a[i]) * t
+	}
+	return animatableFromComponents[T](a)
+}
+
+func animatableComponents[T Animatable](value T) [4]float32 {
+	switch v := any(value).(type) {
+	case float32:
+		return [4]float32{v}
+	case Color:
+		return [4]float32{v.AlphaIntensity(), v.RedIntensity(), v.GreenIntensity(), v.BlueIntensity()}
+	case Point:
+		return [4]float32{v.X, v.Y}
+	case Size:
+		return [4]float32{v.Width, v.Height}
+	case Rect:
+		return [4]float32{v.X, v.Y, v.Width, v.Height}
+	default:
+		return [4]float32{}
+	}
+}
+
+func animatableFromComponents[T Animatable](c [4]float32) T {
+	var result T
+	switch any(result).(type) {
+	case float32:
+		return any(c[0]).(T) //nolint:errcheck // Always succeeds
+	case Color:
+		return any(ARGBfloat(c[0], c[1], c[2], c[3])).(T) //nolint:errcheck // Always succeeds
+	case Point:
+		return any(Point{X: c[0], Y: c[1]}).(T) //nolint:errcheck // Always succeeds
+	case Size:
+		return any(Size{Width: c[0], Height: c[1]}).(T) //nolint:errcheck // Always succeeds
+	case Rect:
+		r := Rect{Point: Point{X: c[0], Y: c[1]}, Size: Size{Width: c[2], Height: c[3]}}
+		return any(r).(T) //nolint:errcheck // Always succeeds
+	default:
+		return result
+	}
+}
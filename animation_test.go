@@ -0,0 +1,72 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestLerp(t *testing.T) {
+	check.Equal(t, float32(5), unison.Lerp[float32](0, 10, 0.5))
+	check.Equal(t, unison.Point{X: 5, Y: -5}, unison.Lerp(unison.Point{}, unison.Point{X: 10, Y: -10}, 0.5))
+	r := unison.Lerp(unison.NewRect(0, 0, 10, 10), unison.NewRect(10, 20, 30, 40), 0.5)
+	check.Equal(t, unison.NewRect(5, 10, 20, 25), r)
+	check.Equal(t, unison.RGB(100, 0, 200), unison.Lerp(unison.RGB(100, 0, 200), unison.RGB(0, 0, 0), 0))
+	check.Equal(t, unison.RGB(0, 0, 0), unison.Lerp(unison.RGB(100, 0, 200), unison.RGB(0, 0, 0), 1))
+}
+
+func TestTween(t *testing.T) {
+	var last float32
+	tween := unison.NewTween[float32](0, 100, time.Second, nil, func(value float32) { last = value })
+	check.False(t, tween.Advance(0))
+	check.Equal(t, float32(0), last)
+	check.False(t, tween.Advance(time.Second/4))
+	check.Equal(t, float32(25), last)
+	check.True(t, tween.Advance(2*time.Second))
+	check.Equal(t, float32(100), last)
+
+	tween.Repeat = 1
+	tween.AutoReverse = true
+	check.Equal(t, 2*time.Second, tween.TotalDuration())
+	check.False(t, tween.Advance(time.Second+time.Second/4))
+	check.Equal(t, float32(75), last)
+	check.True(t, tween.Advance(3*time.Second))
+	check.Equal(t, float32(0), last)
+}
+
+func TestTimeline(t *testing.T) {
+	var first, second float32
+	timeline := unison.NewTimeline().
+		Append(unison.NewTween[float32](0, 1, time.Second, nil, func(value float32) { first = value })).
+		Append(unison.NewTween[float32](0, 1, time.Second, nil, func(value float32) { second = value }))
+	check.Equal(t, 2*time.Second, timeline.TotalDuration())
+	check.False(t, timeline.Advance(time.Second/2))
+	check.Equal(t, float32(0.5), first)
+	check.Equal(t, float32(0), second)
+	check.False(t, timeline.Advance(time.Second+time.Second/2))
+	check.Equal(t, float32(1), first)
+	check.Equal(t, float32(0.5), second)
+	check.True(t, timeline.Advance(3*time.Second))
+	check.Equal(t, float32(1), second)
+}
+
+func TestSpring(t *testing.T) {
+	spring := unison.NewSpring(unison.Point{}, unison.Point{X: 100, Y: 50}, nil)
+	done := false
+	for elapsed := time.Duration(0); !done && elapsed < 10*time.Second; elapsed += time.Second / 60 {
+		done = spring.Advance(elapsed)
+	}
+	check.True(t, done)
+	check.Equal(t, unison.Point{X: 100, Y: 50}, spring.Value())
+}
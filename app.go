@@ -163,6 +163,12 @@ func Start(options ...StartupOption) {
 func processEvents() {
 	glfw.WaitEvents()
 	processNextTask(uiTaskRecovery)
+	drawMarkedWindows()
+}
+
+// drawMarkedWindows draws the windows that have been marked for redraw. Hidden windows stay marked until they are
+// shown.
+func drawMarkedWindows() {
 	if len(redrawSet) > 0 {
 		set := redrawSet
 		redrawSet = make(map[*Window]struct{})
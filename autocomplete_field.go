@@ -0,0 +1,428 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ddkwork/toolbox/i18n"
+	"github.com/ddkwork/unison/enums/paintstyle"
+	"github.com/ddkwork/unison/enums/pathop"
+)
+
+var (
+	_ SuggestionProvider = SuggestionProviderFunc(nil)
+	_ SuggestionProvider = StaticSuggestions(nil)
+)
+
+// DefaultAutocompleteTheme holds the default AutocompleteTheme values for AutocompleteFields. Modifying this data will
+// not alter existing AutocompleteFields, but will alter any AutocompleteFields created in the future.
+var DefaultAutocompleteTheme = AutocompleteTheme{
+	BackgroundInk:   ContentColor,
+	OnBackgroundInk: OnContentColor,
+	SelectionInk:    SelectionColor,
+	OnSelectionInk:  OnSelectionColor,
+	MatchInk:        AccentColor,
+	EdgeInk:         ControlEdgeColor,
+	RowInsets:       Insets{Top: 2, Left: 4, Bottom: 2, Right: 4},
+	MaxVisibleRows:  8,
+	Delay:           150 * time.Millisecond,
+}
+
+// AutocompleteTheme holds theming data for the suggestion list of an AutocompleteField.
+type AutocompleteTheme struct {
+	BackgroundInk   Ink
+	OnBackgroundInk Ink
+	SelectionInk    Ink
+	OnSelectionInk  Ink
+	MatchInk        Ink
+	EdgeInk         Ink
+	RowInsets       Insets
+	MaxVisibleRows  int
+	// Delay is how long to wait after the last change to the text before asking the provider for suggestions. A change
+	// takes effect with the next change to the text.
+	Delay time.Duration
+}
+
+// SuggestionProvider supplies the suggestions for an AutocompleteField. Implementations may call deliver before
+// returning, or later from any goroutine if the suggestions take time to gather. Suggestions delivered for text that is
+// no longer current are ignored.
+type SuggestionProvider interface {
+	Suggest(text string, deliver func(suggestions []string))
+}
+
+// SuggestionProviderFunc adapts a function that returns suggestions immediately into a SuggestionProvider.
+type SuggestionProviderFunc func(text string) []string
+
+// Suggest implements SuggestionProvider.
+func (f SuggestionProviderFunc) Suggest(text string, deliver func(suggestions []string)) {
+	deliver(f(text))
+}
+
+// StaticSuggestions is a SuggestionProvider that offers those of a fixed set of choices that contain the text, ignoring
+// case.
+type StaticSuggestions []string
+
+// Suggest implements SuggestionProvider.
+func (s StaticSuggestions) Suggest(text string, deliver func(suggestions []string)) {
+	text = strings.ToLower(text)
+	suggestions := make([]string, 0, len(s))
+	for _, one := range s {
+		if strings.Contains(strings.ToLower(one), text) {
+			suggestions = append(suggestions, one)
+		}
+	}
+	deliver(suggestions)
+}
+
+// AutocompleteField is a Field that shows a list of suggestions below it while the user types. The up and down arrow
+// keys move through the list, return accepts the selected suggestion and escape dismisses the list. The down arrow key
+// also shows the list when it is hidden. Only changes made by the user ask for suggestions; call RequestSuggestions()
+// after changing the text with SetText() if they are wanted.
+type AutocompleteField struct {
+	*Field
+	AutocompleteTheme
+	// Provider supplies the suggestions.
+	Provider SuggestionProvider
+	// AcceptedCallback is called after the user accepts a suggestion.
+	AcceptedCallback func(suggestion string)
+	// MustMatch, if true, causes the field to be marked invalid unless it is empty or its text matches one of the
+	// suggestions exactly.
+	MustMatch   bool
+	popup       *autocompletePopup
+	debouncer   *Debouncer
+	suggestions []string
+	accepted    string
+	suggestedOn string
+	sequence    int
+	selected    int
+	top         int
+	wanted      bool
+}
+
+type autocompletePopup struct {
+	Panel
+	field *AutocompleteField
+}
+
+// NewAutocompleteField creates a new, empty, AutocompleteField that gets its suggestions from the provider.
+func NewAutocompleteField(provider SuggestionProvider) *AutocompleteField {
+	f := &AutocompleteField{
+		Field:             NewField(),
+		AutocompleteTheme: DefaultAutocompleteTheme,
+		Provider:          provider,
+		selected:          -1,
+	}
+	f.Self = f
+	f.debouncer = NewDebouncer(f.Delay, f.RequestSuggestions)
+	f.modifiedHook = f.textModified
+	f.LostFocusCallback = f.DefaultFocusLost
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.ValidateCallback = f.DefaultValidate
+	return f
+}
+
+// Suggestions returns the most recently delivered suggestions.
+func (f *AutocompleteField) Suggestions() []string {
+	return f.suggestions
+}
+
+// SuggestionsVisible returns true if the suggestion list is being shown.
+func (f *AutocompleteField) SuggestionsVisible() bool {
+	return f.popup != nil
+}
+
+// RequestSuggestions asks the provider for suggestions for the current text right away, showing them once they arrive
+// if the field has the focus.
+func (f *AutocompleteField) RequestSuggestions() {
+	f.debouncer.Cancel()
+	if f.Provider == nil {
+		return
+	}
+	f.sequence++
+	sequence := f.sequence
+	text := f.Text()
+	f.Provider.Suggest(text, func(suggestions []string) {
+		InvokeTask(func() { f.receiveSuggestions(sequence, text, suggestions) })
+	})
+}
+
+// HideSuggestions hides the suggestion list.
+func (f *AutocompleteField) HideSuggestions() {
+	f.wanted = false
+	f.debouncer.Cancel()
+	f.closePopup()
+}
+
+// Accept sets the field's text to the suggestion and hides the suggestion list, as if the user had chosen it.
+func (f *AutocompleteField) Accept(suggestion string) {
+	f.HideSuggestions()
+	f.accepted = suggestion
+	f.SetText(suggestion)
+	f.Validate()
+	if f.AcceptedCallback != nil {
+		f.AcceptedCallback(suggestion)
+	}
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (f *AutocompleteField) DefaultFocusLost() {
+	f.HideSuggestions()
+	f.Field.DefaultFocusLost()
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *AutocompleteField) DefaultKeyDown(keyCode KeyCode, mod Modifiers, repeat bool) bool {
+	if f.popup == nil {
+		if keyCode == KeyDown && mod&NonStickyModifiers == 0 {
+			f.wanted = true
+			f.RequestSuggestions()
+			return true
+		}
+		return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	switch keyCode {
+	case KeyDown:
+		f.selectSuggestion(f.selected + 1)
+	case KeyUp:
+		f.selectSuggestion(f.selected - 1)
+	case KeyPageDown:
+		f.selectSuggestion(f.selected + f.visibleRows())
+	case KeyPageUp:
+		f.selectSuggestion(max(f.selected-f.visibleRows(), 0))
+	case KeyReturn, KeyNumPadEnter:
+		if f.selected < 0 {
+			f.HideSuggestions()
+			return false
+		}
+		f.Accept(f.suggestions[f.selected])
+	case KeyEscape:
+		f.HideSuggestions()
+	case KeyTab:
+		f.HideSuggestions()
+		return false
+	default:
+		return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	return true
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (f *AutocompleteField) DefaultValidate() bool {
+	if f.MustMatch {
+		if text := f.Text(); text != "" && text != f.accepted && !slices.Contains(f.suggestions, text) {
+			f.Tooltip = NewTooltipWithText(i18n.Text("Must match one of the suggestions"))
+			return false
+		}
+	}
+	f.Tooltip = nil
+	return true
+}
+
+func (f *AutocompleteField) textModified() {
+	f.wanted = true
+	if f.Delay > 0 {
+		if f.debouncer.delay != f.Delay {
+			f.debouncer.Cancel()
+			f.debouncer = NewDebouncer(f.Delay, f.RequestSuggestions)
+		}
+		f.debouncer.Trigger()
+	} else {
+		f.RequestSuggestions()
+	}
+}
+
+func (f *AutocompleteField) receiveSuggestions(sequence int, text string, suggestions []string) {
+	if sequence != f.sequence {
+		return
+	}
+	f.suggestions = suggestions
+	f.suggestedOn = text
+	f.selected = -1
+	f.top = 0
+	f.Validate()
+	if f.wanted && len(suggestions) != 0 && f.Focused() && f.Window() != nil {
+		f.showPopup()
+	} else {
+		f.closePopup()
+	}
+}
+
+func (f *AutocompleteField) showPopup() {
+	if f.popup == nil {
+		f.popup = &autocompletePopup{field: f}
+		f.popup.Self = f.popup
+		f.popup.SetBorder(NewLineBorder(f.EdgeInk, 0, NewUniformInsets(1), false))
+		f.popup.DrawCallback = f.popup.draw
+		f.popup.MouseMoveCallback = f.popup.mouseMove
+		f.popup.MouseDownCallback = f.popup.mouseDown
+		f.popup.MouseWheelCallback = f.popup.mouseWheel
+		f.Window().root.setOverlay(f.popup.AsPanel())
+	}
+	insets := f.popup.Border().Insets()
+	fieldRect := f.RectToRoot(f.ContentRect(true))
+	r := Rect{
+		Point: Point{X: fieldRect.X, Y: fieldRect.Bottom()},
+		Size: Size{
+			Width:  fieldRect.Width,
+			Height: float32(f.visibleRows())*f.rowHeight() + insets.Height(),
+		},
+	}
+	for _, one := range f.suggestions {
+		r.Width = max(r.Width, NewText(one, &TextDecoration{Font: f.Font}).Width()+f.RowInsets.Width()+insets.Width())
+	}
+	viewSize := f.Window().root.ContentRect(true).Size
+	r.Width = min(r.Width, viewSize.Width)
+	if r.Right() > viewSize.Width {
+		r.X = max(viewSize.Width-r.Width, 0)
+	}
+	if r.Bottom() > viewSize.Height && fieldRect.Y-r.Height >= 0 {
+		r.Y = fieldRect.Y - r.Height
+	}
+	f.popup.SetFrameRect(r)
+	f.popup.MarkForRedraw()
+}
+
+func (f *AutocompleteField) closePopup() {
+	if f.popup != nil {
+		if wnd := f.popup.Window(); wnd != nil && wnd.root.overlayPanel == f.popup.AsPanel() {
+			wnd.root.setOverlay(nil)
+		}
+		f.popup = nil
+	}
+}
+
+func (f *AutocompleteField) rowHeight() float32 {
+	return f.Font.LineHeight() + f.RowInsets.Height()
+}
+
+func (f *AutocompleteField) visibleRows() int {
+	return max(min(len(f.suggestions), f.MaxVisibleRows), 1)
+}
+
+func (f *AutocompleteField) selectSuggestion(index int) {
+	if len(f.suggestions) == 0 {
+		return
+	}
+	index = min(max(index, 0), len(f.suggestions)-1)
+	if index != f.selected {
+		f.selected = index
+		f.scrollToSelection()
+		if f.popup != nil {
+			f.popup.MarkForRedraw()
+		}
+	}
+}
+
+func (f *AutocompleteField) scrollToSelection() {
+	rows := f.visibleRows()
+	if f.selected < f.top {
+		f.top = f.selected
+	} else if f.selected >= f.top+rows {
+		f.top = f.selected - rows + 1
+	}
+}
+
+// matchText returns the suggestion as text, with the portions that match the text it was suggested for highlighted.
+func (f *AutocompleteField) matchText(suggestion string, fg Ink, selected bool) *Text {
+	plain := &TextDecoration{Font: f.Font, Foreground: fg}
+	matched := &TextDecoration{Font: f.Font, Foreground: f.MatchInk, Underline: true}
+	if selected {
+		matched.Foreground = fg
+	}
+	text := NewText("", plain)
+	needle := []rune(strings.ToLower(f.suggestedOn))
+	runes := []rune(suggestion)
+	lower := []rune(strings.ToLower(suggestion))
+	if len(needle) == 0 || len(lower) != len(runes) {
+		text.AddRunes(runes, plain)
+		return text
+	}
+	start := 0
+	for i := 0; i+len(needle) <= len(lower); {
+		if slices.Equal(lower[i:i+len(needle)], needle) {
+			if start < i {
+				text.AddRunes(runes[start:i], plain)
+			}
+			text.AddRunes(runes[i:i+len(needle)], matched)
+			i += len(needle)
+			start = i
+		} else {
+			i++
+		}
+	}
+	if start < len(runes) {
+		text.AddRunes(runes[start:], plain)
+	}
+	return text
+}
+
+func (p *autocompletePopup) draw(gc *Canvas, _ Rect) {
+	f := p.field
+	r := p.ContentRect(true)
+	gc.DrawRect(r, f.BackgroundInk.Paint(gc, r, paintstyle.Fill))
+	r = p.ContentRect(false)
+	gc.ClipRect(r, pathop.Intersect, false)
+	rowHeight := f.rowHeight()
+	y := r.Y
+	for i := f.top; i < len(f.suggestions) && y < r.Bottom(); i++ {
+		row := NewRect(r.X, y, r.Width, rowHeight)
+		fg := f.OnBackgroundInk
+		selected := i == f.selected
+		if selected {
+			gc.DrawRect(row, f.SelectionInk.Paint(gc, row, paintstyle.Fill))
+			fg = f.OnSelectionInk
+		}
+		text := f.matchText(f.suggestions[i], fg, selected)
+		text.Draw(gc, row.X+f.RowInsets.Left, row.Y+f.RowInsets.Top+text.Baseline())
+		y += rowHeight
+	}
+}
+
+func (p *autocompletePopup) rowAt(where Point) int {
+	r := p.ContentRect(false)
+	if !r.ContainsPoint(where) {
+		return -1
+	}
+	index := p.field.top + int((where.Y-r.Y)/p.field.rowHeight())
+	if index >= len(p.field.suggestions) {
+		return -1
+	}
+	return index
+}
+
+func (p *autocompletePopup) mouseMove(where Point, _ Modifiers) bool {
+	if index := p.rowAt(where); index != -1 {
+		p.field.selectSuggestion(index)
+	}
+	return true
+}
+
+func (p *autocompletePopup) mouseDown(where Point, _, _ int, _ Modifiers) bool {
+	if index := p.rowAt(where); index != -1 {
+		p.field.Accept(p.field.suggestions[index])
+	}
+	return true
+}
+
+func (p *autocompletePopup) mouseWheel(_, delta Point, _ Modifiers) bool {
+	f := p.field
+	maxTop := max(len(f.suggestions)-f.visibleRows(), 0)
+	switch {
+	case delta.Y < 0:
+		f.top = min(f.top+1, maxTop)
+	case delta.Y > 0:
+		f.top = max(f.top-1, 0)
+	}
+	p.MarkForRedraw()
+	return true
+}
@@ -0,0 +1,116 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestStaticSuggestions(t *testing.T) {
+	var got []string
+	provider := unison.StaticSuggestions{"Apple", "Banana", "Pineapple", "Cherry"}
+	provider.Suggest("APP", func(suggestions []string) { got = suggestions })
+	check.Equal(t, []string{"Apple", "Pineapple"}, got)
+	provider.Suggest("", func(suggestions []string) { got = suggestions })
+	check.Equal(t, 4, len(got))
+	provider.Suggest("kiwi", func(suggestions []string) { got = suggestions })
+	check.Equal(t, 0, len(got))
+}
+
+type recordingProvider struct {
+	texts    []string
+	delivers []func(suggestions []string)
+}
+
+func (p *recordingProvider) Suggest(text string, deliver func(suggestions []string)) {
+	p.texts = append(p.texts, text)
+	p.delivers = append(p.delivers, deliver)
+}
+
+// typeText replaces the text in the field as if the user had typed it.
+func typeText(f *unison.AutocompleteField, text string) {
+	f.SelectAll()
+	if text == "" {
+		f.Delete()
+		return
+	}
+	for _, ch := range text {
+		f.DefaultRuneTyped(ch)
+	}
+}
+
+func TestAutocompleteFieldIgnoresStaleSuggestions(t *testing.T) {
+	p := &recordingProvider{}
+	f := unison.NewAutocompleteField(p)
+	f.Delay = 0
+	typeText(f, "ab")
+	check.Equal(t, []string{"a", "ab"}, p.texts)
+
+	p.delivers[1]([]string{"abc"})
+	unison.RunPendingTasks()
+	check.Equal(t, []string{"abc"}, f.Suggestions())
+	p.delivers[0]([]string{"apple"})
+	unison.RunPendingTasks()
+	check.Equal(t, []string{"abc"}, f.Suggestions(), "suggestions for text that is no longer current are ignored")
+
+	f.RequestSuggestions()
+	p.delivers[1]([]string{"abcd"})
+	unison.RunPendingTasks()
+	check.Equal(t, []string{"abc"}, f.Suggestions(), "a newer request makes the earlier ones stale")
+	p.delivers[2]([]string{"abcd"})
+	unison.RunPendingTasks()
+	check.Equal(t, []string{"abcd"}, f.Suggestions())
+}
+
+func TestAutocompleteFieldAccept(t *testing.T) {
+	p := &recordingProvider{}
+	f := unison.NewAutocompleteField(p)
+	f.Delay = 0
+	var accepted []string
+	f.AcceptedCallback = func(suggestion string) { accepted = append(accepted, suggestion) }
+	f.SetText("ap")
+	check.Equal(t, 0, len(p.texts), "setting the text programmatically must not ask for suggestions")
+	f.DefaultRuneTyped('p')
+	p.delivers[0]([]string{"Apple", "Pineapple"})
+	unison.RunPendingTasks()
+
+	f.Accept("Pineapple")
+	unison.RunPendingTasks()
+	check.Equal(t, "Pineapple", f.Text())
+	check.Equal(t, []string{"Pineapple"}, accepted)
+	check.Equal(t, []string{"app"}, p.texts, "accepting a suggestion must not ask for more suggestions")
+	check.False(t, f.SuggestionsVisible())
+}
+
+func TestAutocompleteFieldMustMatch(t *testing.T) {
+	f := unison.NewAutocompleteField(unison.StaticSuggestions{"Apple", "Pineapple"})
+	f.Delay = 0
+	f.MustMatch = true
+	typeText(f, "Apple")
+	unison.RunPendingTasks()
+	check.False(t, f.Invalid(), "the text matches a suggestion")
+	typeText(f, "Appl")
+	unison.RunPendingTasks()
+	check.True(t, f.Invalid(), "the text only partially matches the suggestions")
+	check.NotNil(t, f.Tooltip)
+	typeText(f, "")
+	check.False(t, f.Invalid(), "an empty field is always valid")
+	check.Nil(t, f.Tooltip)
+	f.Accept("Banana")
+	check.False(t, f.Invalid(), "an accepted suggestion is valid, even when the provider no longer offers it")
+
+	f.MustMatch = false
+	typeText(f, "Kiwi")
+	unison.RunPendingTasks()
+	check.False(t, f.Invalid())
+}
@@ -0,0 +1,564 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ddkwork/toolbox/i18n"
+	"github.com/ddkwork/unison/enums/paintstyle"
+)
+
+const (
+	calendarColumns = 7
+	calendarRows    = 6
+)
+
+// DefaultCalendarTheme holds the default CalendarTheme values for Calendars. Modifying this data will not alter existing
+// Calendars, but will alter any Calendars created in the future.
+var DefaultCalendarTheme = CalendarTheme{
+	HeaderFont:             EmphasizedSystemFont,
+	WeekdayFont:            SmallSystemFont,
+	DayFont:                LabelFont,
+	BackgroundInk:          ContentColor,
+	OnBackgroundInk:        OnContentColor,
+	WeekdayInk:             ControlEdgeColor,
+	WeekNumberInk:          AccentColor,
+	OtherMonthInk:          DividerColor,
+	DisabledInk:            DividerColor,
+	TodayInk:               AccentColor,
+	SelectionInk:           SelectionColor,
+	OnSelectionInk:         OnSelectionColor,
+	InactiveSelectionInk:   InactiveSelectionColor,
+	OnInactiveSelectionInk: OnInactiveSelectionColor,
+	ArrowInk:               OnContentColor,
+	CellInsets:             Insets{Top: 3, Left: 5, Bottom: 3, Right: 5},
+	HeaderGap:              4,
+}
+
+// CalendarTheme holds theming data for a Calendar.
+type CalendarTheme struct {
+	HeaderFont             Font
+	WeekdayFont            Font
+	DayFont                Font
+	BackgroundInk          Ink
+	OnBackgroundInk        Ink
+	WeekdayInk             Ink
+	WeekNumberInk          Ink
+	OtherMonthInk          Ink
+	DisabledInk            Ink
+	TodayInk               Ink
+	SelectionInk           Ink
+	OnSelectionInk         Ink
+	InactiveSelectionInk   Ink
+	OnInactiveSelectionInk Ink
+	ArrowInk               Ink
+	CellInsets             Insets
+	HeaderGap              float32
+}
+
+// Calendar shows a month of days and allows one of them to be selected. The arrow keys move the selection by a day or a
+// week, page up and page down move it by a month (a year with the shift key down), and home and end move it to the
+// first and last days of the month. Return chooses the selected date, as does double-clicking a day.
+type Calendar struct {
+	Panel
+	CalendarTheme
+	// SelectionChangedCallback is called after the selected date changes.
+	SelectionChangedCallback func()
+	// ChosenCallback is called when the user chooses the selected date with the return key or a double-click.
+	ChosenCallback func()
+	// DateDisabled, if set, is consulted to determine whether a date may be selected.
+	DateDisabled    func(date time.Time) bool
+	selected        time.Time
+	displayed       time.Time
+	minimum         time.Time
+	maximum         time.Time
+	firstDayOfWeek  time.Weekday
+	showWeekNumbers bool
+	chooseOnClick   bool
+	showActive      bool
+}
+
+type calendarLayout struct {
+	header  Rect
+	prev    Rect
+	next    Rect
+	weekday Rect
+	grid    Rect
+	cell    Size
+	weekCol float32
+}
+
+// NewCalendar creates a new Calendar with the date selected. A zero date selects nothing and displays the current
+// month.
+func NewCalendar(date time.Time) *Calendar {
+	c := &Calendar{
+		CalendarTheme:  DefaultCalendarTheme,
+		firstDayOfWeek: DefaultDateConventions.FirstDayOfWeek,
+	}
+	c.Self = c
+	if !date.IsZero() {
+		c.selected = DateOnly(date)
+		c.displayed = firstOfMonth(c.selected)
+	} else {
+		c.displayed = firstOfMonth(DateOnly(time.Now()))
+	}
+	c.SetFocusable(true)
+	c.SetSizer(c.DefaultSizes)
+	c.DrawCallback = c.DefaultDraw
+	c.MouseDownCallback = c.DefaultMouseDown
+	c.MouseWheelCallback = c.DefaultMouseWheel
+	c.KeyDownCallback = c.DefaultKeyDown
+	c.GainedFocusCallback = c.MarkForRedraw
+	c.LostFocusCallback = c.MarkForRedraw
+	return c
+}
+
+// Selected returns the selected date, or a zero time if nothing is selected.
+func (c *Calendar) Selected() time.Time {
+	return c.selected
+}
+
+// SetSelected selects the date and displays its month. A zero date clears the selection. Dates that are not
+// selectable are ignored.
+func (c *Calendar) SetSelected(date time.Time) {
+	if !date.IsZero() {
+		date = DateOnly(date)
+		if !c.IsSelectable(date) {
+			return
+		}
+		c.displayed = firstOfMonth(date)
+	}
+	if !date.Equal(c.selected) {
+		c.selected = date
+		c.MarkForRedraw()
+		if c.SelectionChangedCallback != nil {
+			c.SelectionChangedCallback()
+		}
+	} else {
+		c.MarkForRedraw()
+	}
+}
+
+// DisplayedMonth returns the first day of the month being displayed.
+func (c *Calendar) DisplayedMonth() time.Time {
+	return c.displayed
+}
+
+// SetDisplayedMonth displays the month containing the date, without altering the selection.
+func (c *Calendar) SetDisplayedMonth(date time.Time) {
+	if date = firstOfMonth(DateOnly(date)); !date.Equal(c.displayed) {
+		c.displayed = date
+		c.MarkForRedraw()
+	}
+}
+
+// Min returns the earliest date that may be selected. A zero time means there is no limit.
+func (c *Calendar) Min() time.Time {
+	return c.minimum
+}
+
+// Max returns the latest date that may be selected. A zero time means there is no limit.
+func (c *Calendar) Max() time.Time {
+	return c.maximum
+}
+
+// SetMinMax sets the earliest and latest dates that may be selected. A zero time means there is no limit. A selection
+// outside of the range is cleared.
+func (c *Calendar) SetMinMax(minimum, maximum time.Time) {
+	if !minimum.IsZero() {
+		minimum = DateOnly(minimum)
+	}
+	if !maximum.IsZero() {
+		maximum = DateOnly(maximum)
+		if maximum.Before(minimum) {
+			maximum = minimum
+		}
+	}
+	c.minimum = minimum
+	c.maximum = maximum
+	if !c.selected.IsZero() && !c.inRange(c.selected) {
+		c.SetSelected(time.Time{})
+	}
+	c.MarkForRedraw()
+}
+
+// FirstDayOfWeek returns the day of the week shown in the first column.
+func (c *Calendar) FirstDayOfWeek() time.Weekday {
+	return c.firstDayOfWeek
+}
+
+// SetFirstDayOfWeek sets the day of the week shown in the first column.
+func (c *Calendar) SetFirstDayOfWeek(day time.Weekday) {
+	if c.firstDayOfWeek != day {
+		c.firstDayOfWeek = day
+		c.MarkForRedraw()
+	}
+}
+
+// ShowWeekNumbers returns true if ISO 8601 week numbers are shown.
+func (c *Calendar) ShowWeekNumbers() bool {
+	return c.showWeekNumbers
+}
+
+// SetShowWeekNumbers sets whether ISO 8601 week numbers are shown in a column before the days.
+func (c *Calendar) SetShowWeekNumbers(show bool) {
+	if c.showWeekNumbers != show {
+		c.showWeekNumbers = show
+		c.MarkForLayoutAndRedraw()
+	}
+}
+
+// IsSelectable returns true if the date is within the minimum and maximum and has not been disabled.
+func (c *Calendar) IsSelectable(date time.Time) bool {
+	date = DateOnly(date)
+	return c.inRange(date) && (c.DateDisabled == nil || !c.DateDisabled(date))
+}
+
+// MoveSelection moves the selection by the number of days, skipping over dates that are not selectable. If nothing is
+// selected, today, or the nearest selectable date to it, is selected instead.
+func (c *Calendar) MoveSelection(days int) {
+	if c.selected.IsZero() {
+		c.selectNear(DateOnly(time.Now()), 1)
+		return
+	}
+	dir := 1
+	if days < 0 {
+		dir = -1
+	}
+	date := c.selected.AddDate(0, 0, days)
+	for i := 0; i < 366; i++ {
+		if !c.inRange(date) {
+			return
+		}
+		if c.IsSelectable(date) {
+			c.SetSelected(date)
+			return
+		}
+		date = date.AddDate(0, 0, dir)
+	}
+}
+
+// MoveSelectionByMonths moves the selection to the same day of the month the given number of months away, or the last
+// day of that month if it is shorter, skipping over dates that are not selectable.
+func (c *Calendar) MoveSelectionByMonths(months int) {
+	from := c.selected
+	if from.IsZero() {
+		from = DateOnly(time.Now())
+	}
+	target := firstOfMonth(from).AddDate(0, months, 0)
+	day := min(from.Day(), daysInMonth(target))
+	date := target.AddDate(0, 0, day-1)
+	dir := 1
+	if months < 0 {
+		dir = -1
+	}
+	if !c.selectNear(date, dir) {
+		c.SetDisplayedMonth(target)
+	}
+}
+
+// DefaultSizes provides the default sizing.
+func (c *Calendar) DefaultSizes(_ Size) (minSize, prefSize, maxSize Size) {
+	cell := c.cellSize()
+	width := cell.Width * float32(calendarColumns)
+	if c.showWeekNumbers {
+		width += cell.Width
+	}
+	headerHeight := c.headerHeight()
+	widest := NewText(i18n.Text(time.September.String())+" 0000", &TextDecoration{Font: c.HeaderFont}).Width()
+	prefSize.Width = max(width, widest+headerHeight*2)
+	prefSize.Height = headerHeight + c.HeaderGap + c.WeekdayFont.LineHeight() + c.CellInsets.Height() +
+		cell.Height*float32(calendarRows)
+	if border := c.Border(); border != nil {
+		prefSize.AddInsets(border.Insets())
+	}
+	prefSize.GrowToInteger()
+	return prefSize, prefSize, prefSize
+}
+
+// DefaultDraw provides the default drawing.
+func (c *Calendar) DefaultDraw(gc *Canvas, _ Rect) {
+	r := c.ContentRect(true)
+	gc.DrawRect(r, c.BackgroundInk.Paint(gc, r, paintstyle.Fill))
+	l := c.layout()
+
+	c.drawCentered(gc, l.header, fmt.Sprintf("%s %d", i18n.Text(c.displayed.Month().String()), c.displayed.Year()),
+		c.HeaderFont, c.OnBackgroundInk)
+	c.drawArrow(gc, l.prev, -1, c.canDisplay(c.displayed.AddDate(0, -1, 0)))
+	c.drawArrow(gc, l.next, 1, c.canDisplay(c.displayed.AddDate(0, 1, 0)))
+
+	if c.showWeekNumbers {
+		c.drawCentered(gc, NewRect(l.weekday.X, l.weekday.Y, l.weekCol, l.weekday.Height), i18n.Text("Wk"),
+			c.WeekdayFont, c.WeekNumberInk)
+	}
+	for i := 0; i < calendarColumns; i++ {
+		day := (c.firstDayOfWeek + time.Weekday(i)) % 7
+		c.drawCentered(gc, NewRect(l.grid.X+float32(i)*l.cell.Width, l.weekday.Y, l.cell.Width, l.weekday.Height),
+			weekdayAbbreviation(day), c.WeekdayFont, c.WeekdayInk)
+	}
+
+	today := DateOnly(time.Now())
+	active := c.showActive || c.Focused()
+	date := c.firstVisibleDate()
+	for row := 0; row < calendarRows; row++ {
+		y := l.grid.Y + float32(row)*l.cell.Height
+		if c.showWeekNumbers {
+			c.drawCentered(gc, NewRect(l.grid.X-l.weekCol, y, l.weekCol, l.cell.Height),
+				strconv.Itoa(isoWeekOfRow(date)), c.WeekdayFont, c.WeekNumberInk)
+		}
+		for col := 0; col < calendarColumns; col++ {
+			cell := NewRect(l.grid.X+float32(col)*l.cell.Width, y, l.cell.Width, l.cell.Height)
+			ink := c.OnBackgroundInk
+			switch {
+			case !c.IsSelectable(date):
+				ink = c.DisabledInk
+			case date.Month() != c.displayed.Month():
+				ink = c.OtherMonthInk
+			}
+			if date.Equal(c.selected) {
+				bg := c.InactiveSelectionInk
+				ink = c.OnInactiveSelectionInk
+				if active {
+					bg = c.SelectionInk
+					ink = c.OnSelectionInk
+				}
+				r := cell
+				r.Inset(NewUniformInsets(1))
+				gc.DrawRoundedRect(r, 3, 3, c.ink(bg).Paint(gc, r, paintstyle.Fill))
+			}
+			if date.Equal(today) {
+				r := cell
+				r.Inset(NewUniformInsets(1.5))
+				gc.DrawRoundedRect(r, 3, 3, c.ink(c.TodayInk).Paint(gc, r, paintstyle.Stroke))
+			}
+			c.drawCentered(gc, cell, strconv.Itoa(date.Day()), c.DayFont, ink)
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// DefaultMouseDown is the default implementation for the MouseDownCallback.
+func (c *Calendar) DefaultMouseDown(where Point, button, clickCount int, _ Modifiers) bool {
+	if c.Focusable() {
+		c.RequestFocus()
+	}
+	if button != ButtonLeft || !c.Enabled() {
+		return true
+	}
+	l := c.layout()
+	switch {
+	case l.prev.ContainsPoint(where):
+		c.changeDisplayedMonth(-1)
+	case l.next.ContainsPoint(where):
+		c.changeDisplayedMonth(1)
+	case l.grid.ContainsPoint(where):
+		col := min(int((where.X-l.grid.X)/l.cell.Width), calendarColumns-1)
+		row := min(int((where.Y-l.grid.Y)/l.cell.Height), calendarRows-1)
+		date := c.firstVisibleDate().AddDate(0, 0, row*calendarColumns+col)
+		if c.IsSelectable(date) {
+			c.SetSelected(date)
+			if (c.chooseOnClick || clickCount == 2) && c.ChosenCallback != nil {
+				c.ChosenCallback()
+			}
+		}
+	}
+	return true
+}
+
+// DefaultMouseWheel is the default implementation for the MouseWheelCallback.
+func (c *Calendar) DefaultMouseWheel(_, delta Point, _ Modifiers) bool {
+	switch {
+	case delta.Y > 0:
+		c.changeDisplayedMonth(-1)
+	case delta.Y < 0:
+		c.changeDisplayedMonth(1)
+	default:
+		return false
+	}
+	return true
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (c *Calendar) DefaultKeyDown(keyCode KeyCode, mod Modifiers, _ bool) bool {
+	if !c.Enabled() || mod.OSMenuCmdModifierDown() {
+		return false
+	}
+	switch keyCode {
+	case KeyLeft:
+		c.MoveSelection(-1)
+	case KeyRight:
+		c.MoveSelection(1)
+	case KeyUp:
+		c.MoveSelection(-calendarColumns)
+	case KeyDown:
+		c.MoveSelection(calendarColumns)
+	case KeyPageUp:
+		if mod.ShiftDown() {
+			c.MoveSelectionByMonths(-12)
+		} else {
+			c.MoveSelectionByMonths(-1)
+		}
+	case KeyPageDown:
+		if mod.ShiftDown() {
+			c.MoveSelectionByMonths(12)
+		} else {
+			c.MoveSelectionByMonths(1)
+		}
+	case KeyHome:
+		c.selectNear(c.displayed, 1)
+	case KeyEnd:
+		c.selectNear(c.displayed.AddDate(0, 0, daysInMonth(c.displayed)-1), -1)
+	case KeyReturn, KeyNumPadEnter:
+		if c.selected.IsZero() || c.ChosenCallback == nil {
+			return false
+		}
+		c.ChosenCallback()
+	default:
+		if IsControlAction(keyCode, mod) && !c.selected.IsZero() && c.ChosenCallback != nil {
+			c.ChosenCallback()
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// selectNear selects the date, or the nearest selectable date in the direction dir within the same month, returning
+// true if a date was selected.
+func (c *Calendar) selectNear(date time.Time, dir int) bool {
+	month := date.Month()
+	for date.Month() == month {
+		if c.IsSelectable(date) {
+			c.SetSelected(date)
+			return true
+		}
+		date = date.AddDate(0, 0, dir)
+	}
+	return false
+}
+
+func (c *Calendar) changeDisplayedMonth(months int) {
+	if target := c.displayed.AddDate(0, months, 0); c.canDisplay(target) {
+		c.SetDisplayedMonth(target)
+	}
+}
+
+// canDisplay returns true if the month containing the date overlaps the range of selectable dates.
+func (c *Calendar) canDisplay(date time.Time) bool {
+	first := firstOfMonth(date)
+	last := first.AddDate(0, 0, daysInMonth(first)-1)
+	return (c.minimum.IsZero() || !last.Before(c.minimum)) && (c.maximum.IsZero() || !first.After(c.maximum))
+}
+
+func (c *Calendar) inRange(date time.Time) bool {
+	return (c.minimum.IsZero() || !date.Before(c.minimum)) && (c.maximum.IsZero() || !date.After(c.maximum))
+}
+
+func (c *Calendar) firstVisibleDate() time.Time {
+	offset := (int(c.displayed.Weekday()) - int(c.firstDayOfWeek) + 7) % 7
+	return c.displayed.AddDate(0, 0, -offset)
+}
+
+func (c *Calendar) cellSize() Size {
+	width := NewText("88", &TextDecoration{Font: c.DayFont}).Width()
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		width = max(width, NewText(weekdayAbbreviation(day), &TextDecoration{Font: c.WeekdayFont}).Width())
+	}
+	return Size{Width: width + c.CellInsets.Width(), Height: c.DayFont.LineHeight() + c.CellInsets.Height()}
+}
+
+func (c *Calendar) headerHeight() float32 {
+	return c.HeaderFont.LineHeight() + c.CellInsets.Height()
+}
+
+func (c *Calendar) layout() calendarLayout {
+	r := c.ContentRect(false)
+	var l calendarLayout
+	columns := float32(calendarColumns)
+	if c.showWeekNumbers {
+		columns++
+	}
+	l.cell = c.cellSize()
+	l.cell.Width = max(l.cell.Width, r.Width/columns)
+	if c.showWeekNumbers {
+		l.weekCol = l.cell.Width
+	}
+	headerHeight := c.headerHeight()
+	l.header = NewRect(r.X, r.Y, r.Width, headerHeight)
+	l.prev = NewRect(r.X, r.Y, headerHeight, headerHeight)
+	l.next = NewRect(r.Right()-headerHeight, r.Y, headerHeight, headerHeight)
+	l.weekday = NewRect(r.X, l.header.Bottom()+c.HeaderGap, r.Width, c.WeekdayFont.LineHeight()+c.CellInsets.Height())
+	l.grid = NewRect(r.X+l.weekCol, l.weekday.Bottom(), l.cell.Width*calendarColumns, l.cell.Height*calendarRows)
+	return l
+}
+
+func (c *Calendar) drawCentered(gc *Canvas, r Rect, str string, font Font, ink Ink) {
+	text := NewText(str, &TextDecoration{Font: font, Foreground: c.ink(ink)})
+	text.Draw(gc, r.X+(r.Width-text.Width())/2, r.Y+(r.Height-text.Height())/2+text.Baseline())
+}
+
+func (c *Calendar) drawArrow(gc *Canvas, r Rect, dir int, enabled bool) {
+	ink := c.ink(c.ArrowInk)
+	if !enabled {
+		ink = &ColorFilteredInk{
+			OriginalInk: ink,
+			ColorFilter: Grayscale30Filter(),
+		}
+	}
+	size := r.Height / 4
+	cx := r.X + r.Width/2
+	cy := r.Y + r.Height/2
+	path := NewPath()
+	if dir < 0 {
+		path.MoveTo(cx+size/2, cy-size)
+		path.LineTo(cx+size/2, cy+size)
+		path.LineTo(cx-size/2, cy)
+	} else {
+		path.MoveTo(cx-size/2, cy-size)
+		path.LineTo(cx-size/2, cy+size)
+		path.LineTo(cx+size/2, cy)
+	}
+	path.Close()
+	gc.DrawPath(path, ink.Paint(gc, r, paintstyle.Fill))
+}
+
+func (c *Calendar) ink(ink Ink) Ink {
+	if c.Enabled() {
+		return ink
+	}
+	return &ColorFilteredInk{
+		OriginalInk: ink,
+		ColorFilter: Grayscale30Filter(),
+	}
+}
+
+func firstOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+}
+
+func daysInMonth(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location()).Day()
+}
+
+func weekdayAbbreviation(day time.Weekday) string {
+	name := []rune(i18n.Text(day.String()))
+	return string(name[:min(len(name), 2)])
+}
+
+// isoWeekOfRow returns the ISO 8601 week number of the calendar row starting with the date, which is the week that
+// contains the row's Thursday.
+func isoWeekOfRow(start time.Time) int {
+	offset := (int(time.Thursday) - int(start.Weekday()) + 7) % 7
+	_, week := start.AddDate(0, 0, offset).ISOWeek()
+	return week
+}
@@ -0,0 +1,95 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestDateConventions(t *testing.T) {
+	us := unison.DateConventionsForLocale("en_US.UTF-8")
+	check.Equal(t, time.Sunday, us.FirstDayOfWeek)
+	check.Equal(t, "03/04/2024", us.FormatDate(date(2024, time.March, 4)))
+	check.Equal(t, "1:30 PM", us.FormatTime(13*time.Hour+30*time.Minute, false))
+	d, err := us.ParseDate("3/4/24")
+	check.NoError(t, err)
+	check.Equal(t, date(2024, time.March, 4), d)
+	d, err = us.ParseDate("2024-03-04")
+	check.NoError(t, err)
+	check.Equal(t, date(2024, time.March, 4), d)
+	_, err = us.ParseDate("13/13/2024")
+	check.Error(t, err)
+
+	de := unison.DateConventionsForLocale("de-DE")
+	check.Equal(t, time.Monday, de.FirstDayOfWeek)
+	check.Equal(t, "04.03.2024", de.FormatDate(date(2024, time.March, 4)))
+	check.Equal(t, "13:30:05", de.FormatTime(13*time.Hour+30*time.Minute+5*time.Second, true))
+	d, err = de.ParseDate("4.3.2024")
+	check.NoError(t, err)
+	check.Equal(t, date(2024, time.March, 4), d)
+
+	for text, expected := range map[string]time.Duration{
+		"13:30":      13*time.Hour + 30*time.Minute,
+		"1:30 pm":    13*time.Hour + 30*time.Minute,
+		"12:15AM":    15 * time.Minute,
+		"9:05:07":    9*time.Hour + 5*time.Minute + 7*time.Second,
+		"7 am":       7 * time.Hour,
+		"23:59:59":   unison.LastTimeOfDay,
+		" 12:00 PM ": 12 * time.Hour,
+	} {
+		v, err := de.ParseTime(text)
+		check.NoError(t, err, text)
+		check.Equal(t, expected, v, text)
+	}
+	_, err = de.ParseTime("25:00")
+	check.Error(t, err)
+}
+
+func TestCalendar(t *testing.T) {
+	c := unison.NewCalendar(date(2024, time.January, 31))
+	changes := 0
+	c.SelectionChangedCallback = func() { changes++ }
+	check.True(t, c.DefaultKeyDown(unison.KeyPageDown, 0, false))
+	check.Equal(t, date(2024, time.February, 29), c.Selected())
+	check.Equal(t, date(2024, time.February, 1), c.DisplayedMonth())
+	check.True(t, c.DefaultKeyDown(unison.KeyPageUp, unison.ShiftModifier, false))
+	check.Equal(t, date(2023, time.February, 28), c.Selected())
+	check.Equal(t, 2, changes)
+
+	c.SetSelected(date(2024, time.May, 10))
+	c.DateDisabled = func(d time.Time) bool { return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday }
+	check.True(t, c.DefaultKeyDown(unison.KeyRight, 0, false))
+	check.Equal(t, date(2024, time.May, 13), c.Selected(), "weekend should be skipped")
+	check.True(t, c.DefaultKeyDown(unison.KeyEnd, 0, false))
+	check.Equal(t, date(2024, time.May, 31), c.Selected())
+	check.True(t, c.DefaultKeyDown(unison.KeyHome, 0, false))
+	check.Equal(t, date(2024, time.May, 1), c.Selected())
+
+	c.SetMinMax(date(2024, time.May, 1), date(2024, time.May, 20))
+	check.True(t, c.DefaultKeyDown(unison.KeyLeft, 0, false))
+	check.Equal(t, date(2024, time.May, 1), c.Selected(), "selection must not move before the minimum")
+	c.SetSelected(date(2024, time.June, 3))
+	check.Equal(t, date(2024, time.May, 1), c.Selected(), "dates beyond the maximum cannot be selected")
+	check.False(t, c.IsSelectable(date(2024, time.May, 4)))
+	check.True(t, c.IsSelectable(date(2024, time.May, 20)))
+
+	chosen := 0
+	c.ChosenCallback = func() { chosen++ }
+	check.True(t, c.DefaultKeyDown(unison.KeyReturn, 0, false))
+	check.Equal(t, 1, chosen)
+}
@@ -0,0 +1,612 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison/enums/anchor"
+	"github.com/ddkwork/unison/enums/relation"
+)
+
+// Constraint priorities. Any priority less than or equal to zero, or greater than or equal to
+// ConstraintPriorityRequired, is treated as required.
+const (
+	ConstraintPriorityRequired float32 = 1000
+	ConstraintPriorityHigh     float32 = 750
+	ConstraintPriorityMedium   float32 = 500
+	ConstraintPriorityLow      float32 = 250
+)
+
+// Priorities used internally by ConstraintLayout. The size of the target is suggested just below required, so that it
+// yields only to required constraints. When determining the minimum and preferred sizes, the target is instead pulled
+// toward zero at priorities that sit between those of the children's intrinsic size constraints.
+const (
+	constraintPriorityTargetSize   float32 = ConstraintPriorityRequired - 1
+	constraintPriorityMinimizeMin  float32 = ConstraintPriorityMedium
+	constraintPriorityMinimizePref float32 = 1
+)
+
+var _ Layout = &ConstraintLayout{}
+
+// Constraint defines a linear relationship between an attribute of a panel and, optionally, an attribute of another
+// panel, of the form:
+//
+//	Item.Attribute Relation Multiplier × Other.OtherAttribute + Constant @ Priority
+//
+// Item and Other must be either the panel the ConstraintLayout is installed on, in which case the attributes refer to
+// its content area, or one of its direct children. If Other is nil, the right-hand side is just the constant.
+type Constraint struct {
+	Item           *Panel
+	Other          *Panel
+	Attribute      anchor.Enum
+	OtherAttribute anchor.Enum
+	Relation       relation.Enum
+	// Multiplier is applied to the other panel's attribute. Zero is treated as 1.
+	Multiplier float32
+	Constant   float32
+	// Priority determines which constraints are given up first when not all of them can be satisfied. See the
+	// ConstraintPriority* constants.
+	Priority float32
+}
+
+// NewConstraint creates a new required constraint of the form: item.attr relation other.otherAttr + constant.
+func NewConstraint(item *Panel, attr anchor.Enum, rel relation.Enum, other *Panel, otherAttr anchor.Enum, constant float32) *Constraint {
+	return &Constraint{
+		Item:           item,
+		Other:          other,
+		Attribute:      attr,
+		OtherAttribute: otherAttr,
+		Relation:       rel,
+		Constant:       constant,
+	}
+}
+
+// Required returns true if the constraint must be satisfied.
+func (c *Constraint) Required() bool {
+	return c.Priority <= 0 || c.Priority >= ConstraintPriorityRequired
+}
+
+func (c *Constraint) String() string {
+	var buffer strings.Builder
+	buffer.WriteString(constraintPanelName(c.Item))
+	buffer.WriteByte('.')
+	buffer.WriteString(c.Attribute.Key())
+	switch c.Relation {
+	case relation.LessOrEqual:
+		buffer.WriteString(" <= ")
+	case relation.GreaterOrEqual:
+		buffer.WriteString(" >= ")
+	default:
+		buffer.WriteString(" = ")
+	}
+	constant := c.Constant
+	if c.Other != nil {
+		if c.Multiplier != 0 && c.Multiplier != 1 {
+			fmt.Fprintf(&buffer, "%v * ", c.Multiplier)
+		}
+		buffer.WriteString(constraintPanelName(c.Other))
+		buffer.WriteByte('.')
+		buffer.WriteString(c.OtherAttribute.Key())
+		switch {
+		case constant < 0:
+			fmt.Fprintf(&buffer, " - %v", -constant)
+		case constant > 0:
+			fmt.Fprintf(&buffer, " + %v", constant)
+		default:
+		}
+	} else {
+		fmt.Fprintf(&buffer, "%v", constant)
+	}
+	if c.Required() {
+		buffer.WriteString(" @required")
+	} else {
+		fmt.Fprintf(&buffer, " @%v", c.Priority)
+	}
+	return buffer.String()
+}
+
+func constraintPanelName(p *Panel) string {
+	switch {
+	case p == nil:
+		return "nil"
+	case p.RefKey != "":
+		return p.RefKey
+	default:
+		return p.String()
+	}
+}
+
+// ParseConstraint creates a constraint from a textual description of the form:
+//
+//	name.attribute relation [multiplier *] name.attribute [+|- constant] [@priority]
+//
+// or, when the right-hand side is just a constant:
+//
+//	name.attribute relation constant [@priority]
+//
+// The name "parent" refers to the target panel, while other names refer to the direct child of the target with that
+// RefKey. Attributes are the keys of the anchor enum (left, right, top, bottom, width, height, center-x and center-y).
+// Relations are "=", "==", "<=" and ">=". The priority may be a number or one of required, high, medium or low, and
+// defaults to required. For example: "ok.right = parent.right - 8 @required". A "-" followed by a letter is taken to be
+// part of a name or attribute, so a subtraction must be followed by a space or a number.
+func ParseConstraint(target *Panel, text string) (*Constraint, error) {
+	p := &constraintParser{target: target, tokens: tokenizeConstraint(text)}
+	c, err := p.parse()
+	if err != nil {
+		return nil, errs.NewWithCausef(err, "unable to parse constraint: %s", text)
+	}
+	return c, nil
+}
+
+type constraintParser struct {
+	target *Panel
+	tokens []string
+	pos    int
+}
+
+func tokenizeConstraint(text string) []string {
+	var tokens []string
+	for i := 0; i < len(text); {
+		ch := text[i]
+		switch {
+		case ch == ' ' || ch == '\t':
+			i++
+		case strings.HasPrefix(text[i:], "<=") || strings.HasPrefix(text[i:], ">=") ||
+			strings.HasPrefix(text[i:], "=="):
+			tokens = append(tokens, text[i:i+2])
+			i += 2
+		case strings.IndexByte("=*+-@", ch) != -1:
+			tokens = append(tokens, text[i:i+1])
+			i++
+		default:
+			start := i
+			for i < len(text) {
+				if text[i] == '-' && i+1 < len(text) && isConstraintNameByte(text[i+1]) {
+					// Part of a name or attribute, such as center-x, rather than a subtraction
+					i++
+					continue
+				}
+				if strings.IndexByte(" \t=<>*+-@", text[i]) != -1 {
+					break
+				}
+				i++
+			}
+			if i == start {
+				// A lone '<' or '>'
+				i++
+			}
+			tokens = append(tokens, text[start:i])
+		}
+	}
+	return tokens
+}
+
+func isConstraintNameByte(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func (p *constraintParser) next() string {
+	if p.pos < len(p.tokens) {
+		p.pos++
+		return p.tokens[p.pos-1]
+	}
+	return ""
+}
+
+func (p *constraintParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *constraintParser) parse() (*Constraint, error) {
+	var c Constraint
+	var err error
+	if c.Item, c.Attribute, err = p.reference(p.next()); err != nil {
+		return nil, err
+	}
+	switch p.next() {
+	case "=", "==":
+		c.Relation = relation.Equal
+	case "<=":
+		c.Relation = relation.LessOrEqual
+	case ">=":
+		c.Relation = relation.GreaterOrEqual
+	default:
+		return nil, errs.New("expected a relation")
+	}
+	token := p.next()
+	if token == "-" {
+		token += p.next()
+	}
+	if value, parseErr := strconv.ParseFloat(token, 32); parseErr == nil {
+		if p.peek() != "*" {
+			c.Constant = float32(value)
+			return &c, p.priority(&c)
+		}
+		p.next()
+		c.Multiplier = float32(value)
+		token = p.next()
+	}
+	if c.Other, c.OtherAttribute, err = p.reference(token); err != nil {
+		return nil, err
+	}
+	if p.peek() == "*" {
+		p.next()
+		value, parseErr := strconv.ParseFloat(p.next(), 32)
+		if parseErr != nil {
+			return nil, errs.New("expected a multiplier")
+		}
+		c.Multiplier = float32(value)
+	}
+	if sign := p.peek(); sign == "+" || sign == "-" {
+		p.next()
+		value, parseErr := strconv.ParseFloat(p.next(), 32)
+		if parseErr != nil {
+			return nil, errs.New("expected a constant")
+		}
+		c.Constant = float32(value)
+		if sign == "-" {
+			c.Constant = -c.Constant
+		}
+	}
+	return &c, p.priority(&c)
+}
+
+func (p *constraintParser) priority(c *Constraint) error {
+	switch p.next() {
+	case "":
+		return nil
+	case "@":
+	default:
+		return errs.New("unexpected trailing text")
+	}
+	token := p.next()
+	switch strings.ToLower(token) {
+	case "required":
+		c.Priority = ConstraintPriorityRequired
+	case "high":
+		c.Priority = ConstraintPriorityHigh
+	case "medium":
+		c.Priority = ConstraintPriorityMedium
+	case "low":
+		c.Priority = ConstraintPriorityLow
+	default:
+		value, err := strconv.ParseFloat(token, 32)
+		if err != nil {
+			return errs.New("expected a priority")
+		}
+		c.Priority = float32(value)
+	}
+	if p.peek() != "" {
+		return errs.New("unexpected trailing text")
+	}
+	return nil
+}
+
+func (p *constraintParser) reference(token string) (*Panel, anchor.Enum, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 1 {
+		return nil, 0, errs.Newf("expected name.attribute, found '%s'", token)
+	}
+	name := token[:i]
+	attrName := token[i+1:]
+	attr := anchor.Left
+	found := false
+	for _, one := range anchor.All {
+		if strings.EqualFold(one.Key(), attrName) || strings.EqualFold(strings.ReplaceAll(one.Key(), "-", ""), attrName) {
+			attr = one
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, 0, errs.Newf("unknown attribute '%s'", attrName)
+	}
+	if name == "parent" {
+		return p.target, attr, nil
+	}
+	for _, child := range p.target.Children() {
+		if child.RefKey == name {
+			return child, attr, nil
+		}
+	}
+	return nil, 0, errs.Newf("no child with the RefKey '%s'", name)
+}
+
+// ConstraintLayout is a Layout that positions and sizes children by solving a set of linear constraints between
+// their edges, centers, widths and heights. Each child is also given constraints derived from its own sizes: it resists
+// being made smaller than its minimum size or larger than its maximum size at ConstraintPriorityHigh, and is drawn
+// toward its preferred size at ConstraintPriorityLow. Required constraints that conflict with those already in effect
+// are dropped, logged, and made available via Conflicts().
+//
+// The solver is incremental: it is only rebuilt when the constraints or the target's children change, and resizing the
+// target only updates the solution.
+type ConstraintLayout struct {
+	constraints []*Constraint
+	conflicts   []*Constraint
+	solver      *csSolver
+	target      *Panel
+	children    []*Panel
+	items       map[*Panel]*constraintItem
+	width       csVariable
+	height      csVariable
+	editRole    float32
+	dirty       bool
+}
+
+type constraintItem struct {
+	left      csVariable
+	top       csVariable
+	width     csVariable
+	height    csVariable
+	intrinsic [6]*csConstraint
+	values    [6]float32
+}
+
+// Add constraints to the layout. Constraints should not be modified once added; remove them, modify them, then add them
+// again instead.
+func (c *ConstraintLayout) Add(constraints ...*Constraint) {
+	c.constraints = append(c.constraints, constraints...)
+	c.dirty = true
+}
+
+// AddParsed parses each of the textual constraints with ParseConstraint() and adds them to the layout. Nothing is added
+// if any of them fail to parse.
+func (c *ConstraintLayout) AddParsed(target *Panel, text ...string) error {
+	list := make([]*Constraint, 0, len(text))
+	for _, one := range text {
+		constraint, err := ParseConstraint(target, one)
+		if err != nil {
+			return err
+		}
+		list = append(list, constraint)
+	}
+	c.Add(list...)
+	return nil
+}
+
+// Remove constraints from the layout.
+func (c *ConstraintLayout) Remove(constraints ...*Constraint) {
+	c.constraints = slices.DeleteFunc(c.constraints, func(one *Constraint) bool { return slices.Contains(constraints, one) })
+	c.dirty = true
+}
+
+// Constraints returns the constraints in the layout.
+func (c *ConstraintLayout) Constraints() []*Constraint {
+	return slices.Clone(c.constraints)
+}
+
+// Conflicts returns the constraints that could not be satisfied the last time the layout was performed and which have
+// therefore been ignored. These are either required constraints that conflict with other required constraints added
+// before them, or constraints that refer to a panel that is neither the target nor one of its children.
+func (c *ConstraintLayout) Conflicts() []*Constraint {
+	return slices.Clone(c.conflicts)
+}
+
+// LayoutSizes implements Layout.
+func (c *ConstraintLayout) LayoutSizes(target *Panel, _ Size) (minSize, prefSize, maxSize Size) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	c.prepare(target)
+	minSize = c.solveForSize(constraintPriorityMinimizeMin)
+	prefSize = c.solveForSize(constraintPriorityMinimizePref)
+	prefSize.Width = max(prefSize.Width, minSize.Width)
+	prefSize.Height = max(prefSize.Height, minSize.Height)
+	minSize.AddInsets(insets)
+	prefSize.AddInsets(insets)
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (c *ConstraintLayout) PerformLayout(target *Panel) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	c.prepare(target)
+	size := target.ContentRect(true).Size
+	size.Width = max(size.Width-insets.Width(), 0)
+	size.Height = max(size.Height-insets.Height(), 0)
+	c.suggestSize(constraintPriorityTargetSize, size)
+	for _, child := range c.children {
+		item := c.items[child]
+		child.SetFrameRect(Rect{
+			Point: Point{X: insets.Left + float32(item.left.value), Y: insets.Top + float32(item.top.value)},
+			Size:  Size{Width: max(float32(item.width.value), 0), Height: max(float32(item.height.value), 0)},
+		})
+	}
+}
+
+func (c *ConstraintLayout) solveForSize(priority float32) Size {
+	c.suggestSize(priority, Size{})
+	return Size{Width: max(float32(c.width.value), 0), Height: max(float32(c.height.value), 0)}
+}
+
+func (c *ConstraintLayout) suggestSize(priority float32, size Size) {
+	if c.editRole != priority {
+		if c.editRole != 0 {
+			logConstraintError(c.solver.removeEditVariable(&c.width))
+			logConstraintError(c.solver.removeEditVariable(&c.height))
+		}
+		c.editRole = priority
+		strength := constraintStrength(priority)
+		logConstraintError(c.solver.addEditVariable(&c.width, strength))
+		logConstraintError(c.solver.addEditVariable(&c.height, strength))
+	}
+	logConstraintError(c.solver.suggestValue(&c.width, float64(size.Width)))
+	logConstraintError(c.solver.suggestValue(&c.height, float64(size.Height)))
+	c.solver.updateVariables()
+}
+
+// prepare ensures the solver reflects the current constraints, children and children's sizes.
+func (c *ConstraintLayout) prepare(target *Panel) {
+	if c.dirty || c.solver == nil || c.target != target || !slices.Equal(c.children, target.Children()) {
+		c.rebuild(target)
+	}
+	for _, child := range c.children {
+		c.updateIntrinsic(child, c.items[child])
+	}
+}
+
+func (c *ConstraintLayout) rebuild(target *Panel) {
+	c.target = target
+	c.children = slices.Clone(target.Children())
+	c.dirty = false
+	c.conflicts = nil
+	var rejected []*Constraint
+	for _, one := range c.constraints {
+		if !c.isValidPanel(one.Item) || (one.Other != nil && !c.isValidPanel(one.Other)) {
+			rejected = append(rejected, one)
+			errs.Log(errs.Newf("constraint refers to a panel that is neither the target nor one of its children: %s", one))
+		}
+	}
+	for {
+		c.solver = newCSSolver()
+		c.editRole = 0
+		c.items = make(map[*Panel]*constraintItem, len(c.children))
+		for _, child := range c.children {
+			c.items[child] = &constraintItem{}
+		}
+		var conflict *Constraint
+		for _, one := range c.constraints {
+			if slices.Contains(rejected, one) {
+				continue
+			}
+			if err := c.solver.addConstraint(c.convert(one)); err != nil {
+				conflict = one
+				errs.Log(errs.NewWithCausef(err, "conflicting constraint ignored: %s", one))
+				break
+			}
+		}
+		if conflict == nil {
+			break
+		}
+		// The solver's state is undefined after a failed addition, so start again without the offending constraint
+		rejected = append(rejected, conflict)
+	}
+	c.conflicts = rejected
+}
+
+func (c *ConstraintLayout) isValidPanel(p *Panel) bool {
+	return p != nil && (p == c.target || slices.Contains(c.children, p))
+}
+
+func (c *ConstraintLayout) convert(one *Constraint) *csConstraint {
+	multiplier := one.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	expr := c.expression(one.Item, one.Attribute)
+	if one.Other != nil {
+		expr = expr.plus(c.expression(one.Other, one.OtherAttribute), -float64(multiplier))
+	}
+	expr.constant -= float64(one.Constant)
+	strength := csRequired
+	if !one.Required() {
+		strength = constraintStrength(one.Priority)
+	}
+	return &csConstraint{expression: expr, relation: one.Relation, strength: strength}
+}
+
+// expression returns the expression for the attribute of the panel, which must be either the target or one of its
+// children.
+func (c *ConstraintLayout) expression(p *Panel, attr anchor.Enum) csExpression {
+	var left, top csExpression
+	var width, height csExpression
+	if p == c.target {
+		width = csExpression{terms: []csTerm{{variable: &c.width, coefficient: 1}}}
+		height = csExpression{terms: []csTerm{{variable: &c.height, coefficient: 1}}}
+	} else {
+		item := c.items[p]
+		left = csExpression{terms: []csTerm{{variable: &item.left, coefficient: 1}}}
+		top = csExpression{terms: []csTerm{{variable: &item.top, coefficient: 1}}}
+		width = csExpression{terms: []csTerm{{variable: &item.width, coefficient: 1}}}
+		height = csExpression{terms: []csTerm{{variable: &item.height, coefficient: 1}}}
+	}
+	switch attr {
+	case anchor.Right:
+		return left.plus(width, 1)
+	case anchor.Top:
+		return top
+	case anchor.Bottom:
+		return top.plus(height, 1)
+	case anchor.Width:
+		return width
+	case anchor.Height:
+		return height
+	case anchor.CenterX:
+		return left.plus(width, 0.5)
+	case anchor.CenterY:
+		return top.plus(height, 0.5)
+	default:
+		return left
+	}
+}
+
+// updateIntrinsic brings the constraints derived from the child's sizes up to date, replacing only those whose values
+// have changed.
+func (c *ConstraintLayout) updateIntrinsic(child *Panel, item *constraintItem) {
+	minSize, prefSize, maxSize := child.Sizes(Size{})
+	values := [6]float32{minSize.Width, minSize.Height, prefSize.Width, prefSize.Height, maxSize.Width, maxSize.Height}
+	for i, value := range values {
+		if item.intrinsic[i] != nil && item.values[i] == value {
+			continue
+		}
+		if item.intrinsic[i] != nil {
+			logConstraintError(c.solver.removeConstraint(item.intrinsic[i]))
+		}
+		v := &item.width
+		if i%2 == 1 {
+			v = &item.height
+		}
+		rel := relation.GreaterOrEqual
+		priority := ConstraintPriorityHigh
+		switch i / 2 {
+		case 1:
+			rel = relation.Equal
+			priority = ConstraintPriorityLow
+		case 2:
+			rel = relation.LessOrEqual
+		default:
+		}
+		item.intrinsic[i] = &csConstraint{
+			expression: csExpression{
+				terms:    []csTerm{{variable: v, coefficient: 1}},
+				constant: -float64(value),
+			},
+			relation: rel,
+			strength: constraintStrength(priority),
+		}
+		item.values[i] = value
+		logConstraintError(c.solver.addConstraint(item.intrinsic[i]))
+	}
+}
+
+func logConstraintError(err error) {
+	if err != nil {
+		errs.Log(err)
+	}
+}
+
+// constraintStrength converts a non-required priority into a solver strength. Strengths grow exponentially with
+// priority so that a constraint is rarely outweighed by a number of constraints with a lower priority.
+func constraintStrength(priority float32) float64 {
+	return math.Pow(10, float64(priority)/125)
+}
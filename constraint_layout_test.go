@@ -0,0 +1,62 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/anchor"
+	"github.com/ddkwork/unison/enums/relation"
+)
+
+func TestConstraintLayout(t *testing.T) {
+	parent := unison.NewPanel()
+	layout := &unison.ConstraintLayout{}
+	parent.SetLayout(layout)
+	a := newLayoutChild(parent, nil)
+	a.RefKey = "a"
+	b := newLayoutChild(parent, nil)
+	b.RefKey = "b"
+	check.NoError(t, layout.AddParsed(parent,
+		"a.left = parent.left + 8",
+		"b.left = a.right + 8",
+		"b.right = parent.right - 8",
+		"a.width = b.width",
+		"a.top = 4",
+		"b.center-y = a.centerY",
+		"parent.bottom >= a.bottom + 4 @high",
+	))
+	minSize, prefSize, _ := parent.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 44, Height: 18}, minSize)
+	check.Equal(t, unison.Size{Width: 124, Height: 28}, prefSize)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 300, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 8, Y: 4}, Size: unison.Size{Width: 138, Height: 20}}, a.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 154, Y: 4}, Size: unison.Size{Width: 138, Height: 20}}, b.FrameRect())
+	check.Equal(t, 0, len(layout.Conflicts()))
+
+	conflict := unison.NewConstraint(a, anchor.Left, relation.Equal, nil, anchor.Left, 20)
+	layout.Add(conflict)
+	parent.MarkForLayoutRecursively()
+	parent.ValidateLayout()
+	check.Equal(t, []*unison.Constraint{conflict}, layout.Conflicts())
+	check.Equal(t, float32(8), a.FrameRect().X)
+	check.Equal(t, "a.left = 20 @required", conflict.String())
+
+	c, err := unison.ParseConstraint(parent, "b.center-x = a.right-8")
+	check.NoError(t, err)
+	check.Equal(t, "b.center-x = a.right - 8 @required", c.String())
+
+	_, err = unison.ParseConstraint(parent, "a.left = missing.right")
+	check.Error(t, err)
+}
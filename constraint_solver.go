@@ -0,0 +1,569 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison/enums/relation"
+)
+
+// This file contains an implementation of the Cassowary incremental linear constraint solving algorithm, as described
+// in "The Cassowary Linear Arithmetic Constraint Solving Algorithm" by Badros, Borning & Stuckey. Its structure follows
+// that of the Kiwi solver.
+
+const csRequired = math.MaxFloat64
+
+var (
+	errCSDuplicate     = errs.New("duplicate constraint")
+	errCSUnsatisfiable = errs.New("unsatisfiable constraint")
+	errCSUnbounded     = errs.New("objective is unbounded")
+	errCSUnknown       = errs.New("unknown constraint or edit variable")
+	errCSInternal      = errs.New("constraint solver failed")
+)
+
+type csSymbolKind byte
+
+const (
+	csExternal csSymbolKind = iota
+	csSlack
+	csError
+	csDummy
+)
+
+type csSymbol struct {
+	id   uint64
+	kind csSymbolKind
+}
+
+type csVariable struct {
+	value float64
+}
+
+type csTerm struct {
+	variable    *csVariable
+	coefficient float64
+}
+
+// csExpression is a linear expression of the form: sum(terms) + constant.
+type csExpression struct {
+	terms    []csTerm
+	constant float64
+}
+
+func (e csExpression) plus(other csExpression, scale float64) csExpression {
+	result := csExpression{
+		terms:    make([]csTerm, 0, len(e.terms)+len(other.terms)),
+		constant: e.constant + other.constant*scale,
+	}
+	result.terms = append(result.terms, e.terms...)
+	for _, t := range other.terms {
+		result.terms = append(result.terms, csTerm{variable: t.variable, coefficient: t.coefficient * scale})
+	}
+	return result
+}
+
+// csConstraint represents the relationship: expression (relation) 0.
+type csConstraint struct {
+	expression csExpression
+	relation   relation.Enum
+	strength   float64
+}
+
+type csRow struct {
+	cells    map[*csSymbol]float64
+	constant float64
+}
+
+func newCSRow(constant float64) *csRow {
+	return &csRow{
+		cells:    make(map[*csSymbol]float64),
+		constant: constant,
+	}
+}
+
+func (r *csRow) clone() *csRow {
+	other := newCSRow(r.constant)
+	for sym, c := range r.cells {
+		other.cells[sym] = c
+	}
+	return other
+}
+
+func (r *csRow) add(value float64) float64 {
+	r.constant += value
+	return r.constant
+}
+
+func (r *csRow) insertSymbol(sym *csSymbol, coefficient float64) {
+	c := r.cells[sym] + coefficient
+	if csNearZero(c) {
+		delete(r.cells, sym)
+	} else {
+		r.cells[sym] = c
+	}
+}
+
+func (r *csRow) insertRow(other *csRow, coefficient float64) {
+	r.constant += other.constant * coefficient
+	for sym, c := range other.cells {
+		r.insertSymbol(sym, c*coefficient)
+	}
+}
+
+func (r *csRow) reverseSign() {
+	r.constant = -r.constant
+	for sym, c := range r.cells {
+		r.cells[sym] = -c
+	}
+}
+
+// solveFor rearranges the row so that it solves for the symbol, which is then removed from the row.
+func (r *csRow) solveFor(sym *csSymbol) {
+	coefficient := -1 / r.cells[sym]
+	delete(r.cells, sym)
+	r.constant *= coefficient
+	for s, c := range r.cells {
+		r.cells[s] = c * coefficient
+	}
+}
+
+// solveForPair rearranges the row, which currently solves for lhs, so that it solves for rhs instead.
+func (r *csRow) solveForPair(lhs, rhs *csSymbol) {
+	r.insertSymbol(lhs, -1)
+	r.solveFor(rhs)
+}
+
+func (r *csRow) substitute(sym *csSymbol, row *csRow) {
+	if c, ok := r.cells[sym]; ok {
+		delete(r.cells, sym)
+		r.insertRow(row, c)
+	}
+}
+
+type csTag struct {
+	marker *csSymbol
+	other  *csSymbol
+}
+
+type csEdit struct {
+	constraint *csConstraint
+	tag        csTag
+	constant   float64
+}
+
+type csSolver struct {
+	constraints map[*csConstraint]csTag
+	rows        map[*csSymbol]*csRow
+	variables   map[*csVariable]*csSymbol
+	edits       map[*csVariable]*csEdit
+	objective   *csRow
+	artificial  *csRow
+	infeasible  []*csSymbol
+	lastID      uint64
+}
+
+func newCSSolver() *csSolver {
+	return &csSolver{
+		constraints: make(map[*csConstraint]csTag),
+		rows:        make(map[*csSymbol]*csRow),
+		variables:   make(map[*csVariable]*csSymbol),
+		edits:       make(map[*csVariable]*csEdit),
+		objective:   newCSRow(0),
+	}
+}
+
+func csNearZero(value float64) bool {
+	return math.Abs(value) < 1e-8
+}
+
+func (s *csSolver) newSymbol(kind csSymbolKind) *csSymbol {
+	s.lastID++
+	return &csSymbol{id: s.lastID, kind: kind}
+}
+
+func (s *csSolver) addConstraint(c *csConstraint) error {
+	if _, exists := s.constraints[c]; exists {
+		return errCSDuplicate
+	}
+	var tag csTag
+	row := s.createRow(c, &tag)
+	subject := s.chooseSubject(row, tag)
+	if subject == nil && csAllDummies(row) {
+		if !csNearZero(row.constant) {
+			return errCSUnsatisfiable
+		}
+		subject = tag.marker
+	}
+	if subject == nil {
+		ok, err := s.addWithArtificialVariable(row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errCSUnsatisfiable
+		}
+	} else {
+		row.solveFor(subject)
+		s.substitute(subject, row)
+		s.rows[subject] = row
+	}
+	s.constraints[c] = tag
+	return s.optimize(s.objective)
+}
+
+func (s *csSolver) removeConstraint(c *csConstraint) error {
+	tag, exists := s.constraints[c]
+	if !exists {
+		return errCSUnknown
+	}
+	delete(s.constraints, c)
+	s.removeMarkerEffects(tag.marker, c.strength)
+	if tag.other != nil {
+		s.removeMarkerEffects(tag.other, c.strength)
+	}
+	if _, ok := s.rows[tag.marker]; ok {
+		delete(s.rows, tag.marker)
+	} else {
+		leaving := s.markerLeavingSymbol(tag.marker)
+		if leaving == nil {
+			return errCSInternal
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, tag.marker)
+		s.substitute(tag.marker, row)
+	}
+	return s.optimize(s.objective)
+}
+
+func (s *csSolver) hasConstraint(c *csConstraint) bool {
+	_, exists := s.constraints[c]
+	return exists
+}
+
+func (s *csSolver) addEditVariable(v *csVariable, strength float64) error {
+	if _, exists := s.edits[v]; exists {
+		return errCSDuplicate
+	}
+	if strength >= csRequired {
+		return errs.New("edit variables may not be required")
+	}
+	c := &csConstraint{
+		expression: csExpression{terms: []csTerm{{variable: v, coefficient: 1}}},
+		relation:   relation.Equal,
+		strength:   strength,
+	}
+	if err := s.addConstraint(c); err != nil {
+		return err
+	}
+	s.edits[v] = &csEdit{
+		constraint: c,
+		tag:        s.constraints[c],
+	}
+	return nil
+}
+
+func (s *csSolver) removeEditVariable(v *csVariable) error {
+	edit, exists := s.edits[v]
+	if !exists {
+		return errCSUnknown
+	}
+	delete(s.edits, v)
+	return s.removeConstraint(edit.constraint)
+}
+
+func (s *csSolver) suggestValue(v *csVariable, value float64) error {
+	edit, exists := s.edits[v]
+	if !exists {
+		return errCSUnknown
+	}
+	delta := value - edit.constant
+	edit.constant = value
+	if row, ok := s.rows[edit.tag.marker]; ok {
+		if row.add(-delta) < 0 {
+			s.infeasible = append(s.infeasible, edit.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+	if row, ok := s.rows[edit.tag.other]; ok {
+		if row.add(delta) < 0 {
+			s.infeasible = append(s.infeasible, edit.tag.other)
+		}
+		return s.dualOptimize()
+	}
+	for sym, row := range s.rows {
+		if c := row.cells[edit.tag.marker]; c != 0 && row.add(delta*c) < 0 && sym.kind != csExternal {
+			s.infeasible = append(s.infeasible, sym)
+		}
+	}
+	return s.dualOptimize()
+}
+
+func (s *csSolver) updateVariables() {
+	for v, sym := range s.variables {
+		if row, ok := s.rows[sym]; ok {
+			v.value = row.constant
+		} else {
+			v.value = 0
+		}
+	}
+}
+
+func (s *csSolver) createRow(c *csConstraint, tag *csTag) *csRow {
+	row := newCSRow(c.expression.constant)
+	for _, term := range c.expression.terms {
+		if csNearZero(term.coefficient) {
+			continue
+		}
+		sym, ok := s.variables[term.variable]
+		if !ok {
+			sym = s.newSymbol(csExternal)
+			s.variables[term.variable] = sym
+		}
+		if basic, isBasic := s.rows[sym]; isBasic {
+			row.insertRow(basic, term.coefficient)
+		} else {
+			row.insertSymbol(sym, term.coefficient)
+		}
+	}
+	switch c.relation {
+	case relation.LessOrEqual, relation.GreaterOrEqual:
+		coefficient := 1.0
+		if c.relation == relation.GreaterOrEqual {
+			coefficient = -1
+		}
+		tag.marker = s.newSymbol(csSlack)
+		row.insertSymbol(tag.marker, coefficient)
+		if c.strength < csRequired {
+			tag.other = s.newSymbol(csError)
+			row.insertSymbol(tag.other, -coefficient)
+			s.objective.insertSymbol(tag.other, c.strength)
+		}
+	default:
+		if c.strength < csRequired {
+			tag.marker = s.newSymbol(csError)
+			tag.other = s.newSymbol(csError)
+			row.insertSymbol(tag.marker, -1)
+			row.insertSymbol(tag.other, 1)
+			s.objective.insertSymbol(tag.marker, c.strength)
+			s.objective.insertSymbol(tag.other, c.strength)
+		} else {
+			tag.marker = s.newSymbol(csDummy)
+			row.insertSymbol(tag.marker, 1)
+		}
+	}
+	if row.constant < 0 {
+		row.reverseSign()
+	}
+	return row
+}
+
+// chooseSubject returns the symbol the new row should be solved for, or nil if there is no suitable choice, in which
+// case an artificial variable must be used.
+func (s *csSolver) chooseSubject(row *csRow, tag csTag) *csSymbol {
+	var subject *csSymbol
+	for sym := range row.cells {
+		if sym.kind == csExternal && (subject == nil || sym.id < subject.id) {
+			subject = sym
+		}
+	}
+	if subject != nil {
+		return subject
+	}
+	if (tag.marker.kind == csSlack || tag.marker.kind == csError) && row.cells[tag.marker] < 0 {
+		return tag.marker
+	}
+	if tag.other != nil && (tag.other.kind == csSlack || tag.other.kind == csError) && row.cells[tag.other] < 0 {
+		return tag.other
+	}
+	return nil
+}
+
+func csAllDummies(row *csRow) bool {
+	for sym := range row.cells {
+		if sym.kind != csDummy {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *csSolver) addWithArtificialVariable(row *csRow) (bool, error) {
+	art := s.newSymbol(csSlack)
+	s.rows[art] = row.clone()
+	s.artificial = row.clone()
+	if err := s.optimize(s.artificial); err != nil {
+		s.artificial = nil
+		return false, err
+	}
+	success := csNearZero(s.artificial.constant)
+	s.artificial = nil
+	if artRow, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+		if len(artRow.cells) == 0 {
+			return success, nil
+		}
+		entering := csAnyPivotableSymbol(artRow)
+		if entering == nil {
+			return false, nil
+		}
+		artRow.solveForPair(art, entering)
+		s.substitute(entering, artRow)
+		s.rows[entering] = artRow
+	}
+	for _, r := range s.rows {
+		delete(r.cells, art)
+	}
+	delete(s.objective.cells, art)
+	return success, nil
+}
+
+func csAnyPivotableSymbol(row *csRow) *csSymbol {
+	var result *csSymbol
+	for sym := range row.cells {
+		if (sym.kind == csSlack || sym.kind == csError) && (result == nil || sym.id < result.id) {
+			result = sym
+		}
+	}
+	return result
+}
+
+func (s *csSolver) substitute(sym *csSymbol, row *csRow) {
+	for key, r := range s.rows {
+		r.substitute(sym, row)
+		if key.kind != csExternal && r.constant < 0 {
+			s.infeasible = append(s.infeasible, key)
+		}
+	}
+	s.objective.substitute(sym, row)
+	if s.artificial != nil {
+		s.artificial.substitute(sym, row)
+	}
+}
+
+// optimize performs the primal simplex method on the objective. Ties are broken by choosing the symbol with the lowest
+// id, which both prevents cycling and makes the results independent of map iteration order.
+func (s *csSolver) optimize(objective *csRow) error {
+	for {
+		var entering *csSymbol
+		for sym, c := range objective.cells {
+			if sym.kind != csDummy && c < 0 && (entering == nil || sym.id < entering.id) {
+				entering = sym
+			}
+		}
+		if entering == nil {
+			return nil
+		}
+		var leaving *csSymbol
+		ratio := math.MaxFloat64
+		for sym, row := range s.rows {
+			if sym.kind == csExternal {
+				continue
+			}
+			if c := row.cells[entering]; c < 0 {
+				r := -row.constant / c
+				if leaving == nil || r < ratio || (r == ratio && sym.id < leaving.id) {
+					ratio = r
+					leaving = sym
+				}
+			}
+		}
+		if leaving == nil {
+			return errCSUnbounded
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+}
+
+// dualOptimize performs the dual simplex method, restoring feasibility after edit values have changed.
+func (s *csSolver) dualOptimize() error {
+	for len(s.infeasible) != 0 {
+		leaving := s.infeasible[len(s.infeasible)-1]
+		s.infeasible = s.infeasible[:len(s.infeasible)-1]
+		row, ok := s.rows[leaving]
+		if !ok || csNearZero(row.constant) || row.constant >= 0 {
+			continue
+		}
+		var entering *csSymbol
+		ratio := math.MaxFloat64
+		for sym, c := range row.cells {
+			if c > 0 && sym.kind != csDummy {
+				r := s.objective.cells[sym] / c
+				if entering == nil || r < ratio || (r == ratio && sym.id < entering.id) {
+					ratio = r
+					entering = sym
+				}
+			}
+		}
+		if entering == nil {
+			return errCSInternal
+		}
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+	return nil
+}
+
+func (s *csSolver) removeMarkerEffects(marker *csSymbol, strength float64) {
+	if marker.kind != csError {
+		return
+	}
+	if row, ok := s.rows[marker]; ok {
+		s.objective.insertRow(row, -strength)
+	} else {
+		s.objective.insertSymbol(marker, -strength)
+	}
+}
+
+// markerLeavingSymbol returns the basic symbol whose row should be pivoted on in order to remove a constraint's marker
+// that is not itself basic.
+func (s *csSolver) markerLeavingSymbol(marker *csSymbol) *csSymbol {
+	r1 := math.MaxFloat64
+	r2 := math.MaxFloat64
+	var first, second, third *csSymbol
+	for sym, row := range s.rows {
+		c := row.cells[marker]
+		if c == 0 {
+			continue
+		}
+		switch {
+		case sym.kind == csExternal:
+			if third == nil || sym.id < third.id {
+				third = sym
+			}
+		case c < 0:
+			if r := -row.constant / c; first == nil || r < r1 || (r == r1 && sym.id < first.id) {
+				r1 = r
+				first = sym
+			}
+		default:
+			if r := row.constant / c; second == nil || r < r2 || (r == r2 && sym.id < second.id) {
+				r2 = r
+				second = sym
+			}
+		}
+	}
+	switch {
+	case first != nil:
+		return first
+	case second != nil:
+		return second
+	default:
+		return third
+	}
+}
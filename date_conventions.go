@@ -0,0 +1,145 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// DefaultDateConventions holds the DateConventions for the current locale. Modifying this data will not alter existing
+// date and time widgets, but will alter any created in the future.
+var DefaultDateConventions = DateConventionsForLocale(i18n.Locale())
+
+// DateConventions holds the conventions used to present and parse dates and times.
+type DateConventions struct {
+	// DateLayout is the layout, as used by the time package, for formatting dates, e.g. "01/02/2006".
+	DateLayout string
+	// TimeLayout is the layout for formatting times without seconds, e.g. "3:04 PM".
+	TimeLayout string
+	// TimeWithSecondsLayout is the layout for formatting times with seconds, e.g. "3:04:05 PM".
+	TimeWithSecondsLayout string
+	// FirstDayOfWeek is the day shown in the first column of a Calendar.
+	FirstDayOfWeek time.Weekday
+}
+
+// DateConventionsForLocale returns the DateConventions for a locale, such as "en_US.UTF-8" or "de-DE". Unknown locales
+// receive day-month-year ordering with a 24-hour clock.
+func DateConventionsForLocale(locale string) DateConventions {
+	if i := strings.IndexAny(locale, ".@"); i != -1 {
+		locale = locale[:i]
+	}
+	language, region, _ := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	language = strings.ToLower(language)
+	region = strings.ToUpper(region)
+	dc := DateConventions{
+		DateLayout:            "02/01/2006",
+		TimeLayout:            "15:04",
+		TimeWithSecondsLayout: "15:04:05",
+		FirstDayOfWeek:        time.Monday,
+	}
+	switch {
+	case language == "en" && (region == "US" || region == "PH" || region == ""):
+		dc.DateLayout = "01/02/2006"
+		dc.TimeLayout = "3:04 PM"
+		dc.TimeWithSecondsLayout = "3:04:05 PM"
+		dc.FirstDayOfWeek = time.Sunday
+	case language == "en" && (region == "AU" || region == "NZ" || region == "IN"):
+		dc.TimeLayout = "3:04 PM"
+		dc.TimeWithSecondsLayout = "3:04:05 PM"
+	case language == "en" && region == "CA":
+		dc.DateLayout = "2006-01-02"
+		dc.TimeLayout = "3:04 PM"
+		dc.TimeWithSecondsLayout = "3:04:05 PM"
+		dc.FirstDayOfWeek = time.Sunday
+	case language == "ja" || language == "zh" || language == "ko":
+		dc.DateLayout = "2006/01/02"
+		if language != "zh" {
+			dc.FirstDayOfWeek = time.Sunday
+		}
+	case language == "sv" || language == "lt" || language == "hu":
+		dc.DateLayout = "2006-01-02"
+	case language == "nl":
+		dc.DateLayout = "02-01-2006"
+	case language == "de" || language == "ru" || language == "pl" || language == "cs" || language == "fi" ||
+		language == "nb" || language == "no" || language == "da" || language == "tr" || language == "uk":
+		dc.DateLayout = "02.01.2006"
+	}
+	return dc
+}
+
+// FormatDate returns the date as text. A zero time returns an empty string.
+func (dc *DateConventions) FormatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(dc.DateLayout)
+}
+
+// ParseDate parses text into a date at midnight, local time. In addition to the DateLayout, single-digit days and
+// months, two-digit years, ISO 8601 dates and the words "today", "tomorrow" and "yesterday" are accepted.
+func (dc *DateConventions) ParseDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	today := DateOnly(time.Now())
+	switch strings.ToLower(text) {
+	case strings.ToLower(i18n.Text("today")):
+		return today, nil
+	case strings.ToLower(i18n.Text("tomorrow")):
+		return today.AddDate(0, 0, 1), nil
+	case strings.ToLower(i18n.Text("yesterday")):
+		return today.AddDate(0, 0, -1), nil
+	}
+	relaxed := strings.NewReplacer("01", "1", "02", "2").Replace(dc.DateLayout)
+	for _, layout := range []string{
+		dc.DateLayout,
+		relaxed,
+		strings.Replace(relaxed, "2006", "06", 1),
+		"2006-1-2",
+	} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errs.Newf(i18n.Text("Invalid date; expected a date such as %s"), dc.FormatDate(today))
+}
+
+// FormatTime returns the time of day, expressed as the time since midnight, as text.
+func (dc *DateConventions) FormatTime(timeOfDay time.Duration, withSeconds bool) string {
+	t := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(timeOfDay)
+	if withSeconds {
+		return t.Format(dc.TimeWithSecondsLayout)
+	}
+	return t.Format(dc.TimeLayout)
+}
+
+// ParseTime parses text into a time of day, expressed as the time since midnight. Both 12-hour and 24-hour clock times
+// are accepted, with or without minutes and seconds.
+func (dc *DateConventions) ParseTime(text string) (time.Duration, error) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	for _, layout := range []string{
+		"15:04", "15:04:05", "3:04 PM", "3:04:05 PM", "3:04PM", "3:04:05PM", "3 PM", "3PM", "15",
+	} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, errs.Newf(i18n.Text("Invalid time; expected a time such as %s"), dc.FormatTime(13*time.Hour+30*time.Minute,
+		false))
+}
+
+// DateOnly returns the date portion of the time, at midnight in the local time zone.
+func DateOnly(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,337 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ddkwork/toolbox/i18n"
+	"github.com/ddkwork/unison/enums/paintstyle"
+)
+
+// DefaultDateFieldTheme holds the default DateFieldTheme values for DateFields. Modifying this data will not alter
+// existing DateFields, but will alter any DateFields created in the future.
+var DefaultDateFieldTheme = DateFieldTheme{
+	ButtonInk:     OnEditableColor,
+	ButtonEdgeInk: ControlEdgeColor,
+	PopupEdgeInk:  ControlEdgeColor,
+	ButtonWidth:   16,
+}
+
+// DateFieldTheme holds theming data for the calendar button and popup of a DateField.
+type DateFieldTheme struct {
+	ButtonInk     Ink
+	ButtonEdgeInk Ink
+	PopupEdgeInk  Ink
+	ButtonWidth   float32
+}
+
+// DateField holds a date that can be edited, either by typing it or by picking it from a calendar popup. The popup is
+// shown by clicking the button at the end of the field or pressing the down arrow key. While it is shown, the arrow,
+// page up, page down, home and end keys move through its dates, return accepts the selected date and escape dismisses
+// it.
+type DateField struct {
+	*Field
+	DateFieldTheme
+	CalendarTheme
+	// Conventions controls how dates are presented and parsed. Call SetValue() after changing it to update the text.
+	Conventions DateConventions
+	// DateDisabled, if set, is consulted to determine whether a date may be chosen.
+	DateDisabled func(date time.Time) bool
+	// ShowWeekNumbers causes the calendar popup to show ISO 8601 week numbers.
+	ShowWeekNumbers bool
+	minimum         time.Time
+	maximum         time.Time
+	popup           *Calendar
+}
+
+// NewDateField creates a new DateField holding the date. A zero date leaves the field empty.
+func NewDateField(date time.Time) *DateField {
+	f := &DateField{
+		Field:          NewField(),
+		DateFieldTheme: DefaultDateFieldTheme,
+		CalendarTheme:  DefaultCalendarTheme,
+		Conventions:    DefaultDateConventions,
+	}
+	f.Self = f
+	room := NewEmptyBorder(Insets{Right: f.ButtonWidth})
+	f.FocusedBorder = NewCompoundBorder(f.FocusedBorder, room)
+	f.UnfocusedBorder = NewCompoundBorder(f.UnfocusedBorder, room)
+	f.SetBorder(f.UnfocusedBorder)
+	f.modifiedHook = f.syncCalendar
+	f.DrawCallback = f.DefaultDraw
+	f.LostFocusCallback = f.DefaultFocusLost
+	f.MouseDownCallback = f.DefaultMouseDown
+	f.UpdateCursorCallback = f.DefaultUpdateCursor
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.ValidateCallback = f.DefaultValidate
+	f.SetMinimumTextWidthUsing(f.Conventions.FormatDate(time.Date(2088, 12, 28, 0, 0, 0, 0, time.Local)))
+	f.SetValue(date)
+	return f
+}
+
+// Value returns the date in the field, or a zero time if the field is empty or does not hold a valid date.
+func (f *DateField) Value() time.Time {
+	text := strings.TrimSpace(f.Text())
+	if text == "" {
+		return time.Time{}
+	}
+	date, err := f.Conventions.ParseDate(text)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// SetValue sets the date in the field. A zero date empties the field.
+func (f *DateField) SetValue(date time.Time) {
+	var text string
+	if !date.IsZero() {
+		text = f.Conventions.FormatDate(DateOnly(date))
+	}
+	if text != f.Text() {
+		f.SetText(text)
+		f.syncCalendar()
+	}
+}
+
+// Min returns the earliest date allowed. A zero time means there is no limit.
+func (f *DateField) Min() time.Time {
+	return f.minimum
+}
+
+// Max returns the latest date allowed. A zero time means there is no limit.
+func (f *DateField) Max() time.Time {
+	return f.maximum
+}
+
+// SetMinMax sets the earliest and latest dates allowed. A zero time means there is no limit.
+func (f *DateField) SetMinMax(minimum, maximum time.Time) {
+	if !minimum.IsZero() {
+		minimum = DateOnly(minimum)
+	}
+	if !maximum.IsZero() {
+		maximum = DateOnly(maximum)
+	}
+	f.minimum = minimum
+	f.maximum = maximum
+	if f.popup != nil {
+		f.popup.SetMinMax(minimum, maximum)
+	}
+	f.Validate()
+}
+
+// CalendarVisible returns true if the calendar popup is being shown.
+func (f *DateField) CalendarVisible() bool {
+	return f.popup != nil
+}
+
+// ShowCalendar shows the calendar popup below the field.
+func (f *DateField) ShowCalendar() {
+	wnd := f.Window()
+	if f.popup != nil || wnd == nil {
+		return
+	}
+	c := NewCalendar(time.Time{})
+	c.CalendarTheme = f.CalendarTheme
+	c.SetFocusable(false)
+	c.chooseOnClick = true
+	c.showActive = true
+	c.SetFirstDayOfWeek(f.Conventions.FirstDayOfWeek)
+	c.SetShowWeekNumbers(f.ShowWeekNumbers)
+	c.SetMinMax(f.minimum, f.maximum)
+	c.DateDisabled = f.DateDisabled
+	if date := f.Value(); !date.IsZero() {
+		c.SetSelected(date)
+		c.SetDisplayedMonth(date)
+	}
+	c.SetBorder(NewLineBorder(f.PopupEdgeInk, 0, NewUniformInsets(1), false))
+	c.ChosenCallback = f.choose
+	f.popup = c
+	wnd.root.setOverlay(c.AsPanel())
+	_, pref, _ := c.Sizes(Size{})
+	fieldRect := f.RectToRoot(f.ContentRect(true))
+	r := Rect{Point: Point{X: fieldRect.Right() - pref.Width, Y: fieldRect.Bottom()}, Size: pref}
+	viewSize := wnd.root.ContentRect(true).Size
+	r.X = max(min(r.X, viewSize.Width-r.Width), 0)
+	if r.Bottom() > viewSize.Height && fieldRect.Y-r.Height >= 0 {
+		r.Y = fieldRect.Y - r.Height
+	}
+	c.SetFrameRect(r)
+	f.MarkForRedraw()
+}
+
+// HideCalendar hides the calendar popup.
+func (f *DateField) HideCalendar() {
+	if f.popup != nil {
+		if wnd := f.popup.Window(); wnd != nil && wnd.root.overlayPanel == f.popup.AsPanel() {
+			wnd.root.setOverlay(nil)
+		}
+		f.popup = nil
+		f.MarkForRedraw()
+	}
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (f *DateField) DefaultFocusLost() {
+	f.HideCalendar()
+	if date := f.Value(); !date.IsZero() {
+		f.SetValue(date)
+	}
+	f.Field.DefaultFocusLost()
+}
+
+// DefaultDraw provides the default drawing.
+func (f *DateField) DefaultDraw(canvas *Canvas, dirty Rect) {
+	canvas.Save()
+	f.Field.DefaultDraw(canvas, dirty)
+	canvas.Restore()
+	r := f.buttonRect()
+	canvas.DrawLine(r.X+0.5, r.Y, r.X+0.5, r.Bottom(), f.ButtonEdgeInk.Paint(canvas, r, paintstyle.Stroke))
+	ink := f.ButtonInk
+	if !f.Enabled() {
+		ink = &ColorFilteredInk{
+			OriginalInk: ink,
+			ColorFilter: Grayscale30Filter(),
+		}
+	}
+	size := min(r.Width, r.Height) / 2
+	cx := r.X + r.Width/2
+	cy := r.Y + r.Height/2
+	path := NewPath()
+	if f.popup != nil {
+		path.MoveTo(cx-size/2, cy+size/4)
+		path.LineTo(cx+size/2, cy+size/4)
+		path.LineTo(cx, cy-size/4)
+	} else {
+		path.MoveTo(cx-size/2, cy-size/4)
+		path.LineTo(cx+size/2, cy-size/4)
+		path.LineTo(cx, cy+size/4)
+	}
+	path.Close()
+	canvas.DrawPath(path, ink.Paint(canvas, r, paintstyle.Fill))
+}
+
+// DefaultMouseDown is the default implementation for the MouseDownCallback.
+func (f *DateField) DefaultMouseDown(where Point, button, clickCount int, mod Modifiers) bool {
+	if button == ButtonLeft && f.buttonRect().ContainsPoint(where) {
+		if f.Enabled() {
+			if f.popup != nil {
+				f.HideCalendar()
+			} else {
+				f.RequestFocus()
+				f.ShowCalendar()
+			}
+		}
+		return true
+	}
+	return f.Field.DefaultMouseDown(where, button, clickCount, mod)
+}
+
+// DefaultUpdateCursor is the default implementation for the UpdateCursorCallback.
+func (f *DateField) DefaultUpdateCursor(where Point) *Cursor {
+	if f.buttonRect().ContainsPoint(where) {
+		return ArrowCursor()
+	}
+	return f.Field.DefaultUpdateCursor(where)
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *DateField) DefaultKeyDown(keyCode KeyCode, mod Modifiers, repeat bool) bool {
+	if f.popup == nil {
+		if keyCode == KeyDown && mod&(NonStickyModifiers&^OptionModifier) == 0 && f.Enabled() {
+			f.ShowCalendar()
+			return true
+		}
+		return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	switch keyCode {
+	case KeyLeft, KeyRight, KeyUp, KeyDown, KeyPageUp, KeyPageDown, KeyHome, KeyEnd:
+		f.popup.DefaultKeyDown(keyCode, mod, repeat)
+	case KeyReturn, KeyNumPadEnter:
+		if f.popup.Selected().IsZero() {
+			f.HideCalendar()
+			return false
+		}
+		f.choose()
+	case KeyEscape:
+		f.HideCalendar()
+	case KeyTab:
+		f.HideCalendar()
+		return false
+	default:
+		return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	return true
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (f *DateField) DefaultValidate() bool {
+	if text := f.tooltipTextForValidation(); text != "" {
+		f.Tooltip = NewTooltipWithText(text)
+		return false
+	}
+	f.Tooltip = nil
+	return true
+}
+
+func (f *DateField) tooltipTextForValidation() string {
+	text := strings.TrimSpace(f.Text())
+	if text == "" {
+		return ""
+	}
+	date, err := f.Conventions.ParseDate(text)
+	if err != nil {
+		return err.Error()
+	}
+	if !f.minimum.IsZero() && date.Before(f.minimum) {
+		return fmt.Sprintf(i18n.Text("Date must be no earlier than %s"), f.Conventions.FormatDate(f.minimum))
+	}
+	if !f.maximum.IsZero() && date.After(f.maximum) {
+		return fmt.Sprintf(i18n.Text("Date must be no later than %s"), f.Conventions.FormatDate(f.maximum))
+	}
+	if f.DateDisabled != nil && f.DateDisabled(date) {
+		return i18n.Text("Date is not available")
+	}
+	return ""
+}
+
+// textModified keeps the calendar popup in step with what is typed.
+// syncCalendar shows the date in the field in the calendar popup, if it is open.
+func (f *DateField) syncCalendar() {
+	if f.popup != nil {
+		if date := f.Value(); !date.IsZero() {
+			f.popup.SetSelected(date)
+			f.popup.SetDisplayedMonth(date)
+		}
+	}
+}
+
+func (f *DateField) choose() {
+	if f.popup == nil {
+		return
+	}
+	date := f.popup.Selected()
+	f.HideCalendar()
+	if date.IsZero() {
+		return
+	}
+	f.undoID = NextUndoID()
+	f.SetValue(date)
+	f.undoID = NextUndoID()
+	f.SelectAll()
+}
+
+func (f *DateField) buttonRect() Rect {
+	content := f.ContentRect(false)
+	return NewRect(content.Right(), content.Y, f.ButtonWidth, content.Height)
+}
@@ -0,0 +1,146 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces bursts of triggers into a single call of its function on the UI thread, made once no further
+// triggers have arrived for the delay period. Its methods may be called from any goroutine.
+type Debouncer struct {
+	f        func()
+	timer    stoppable
+	lock     sync.Mutex
+	delay    time.Duration
+	sequence int
+}
+
+// NewDebouncer creates a new Debouncer that calls f on the UI thread once triggers have stopped arriving for the delay
+// period.
+func NewDebouncer(delay time.Duration, f func()) *Debouncer {
+	return &Debouncer{
+		f:     f,
+		delay: delay,
+	}
+}
+
+// Trigger the debouncer, restarting its delay period.
+func (d *Debouncer) Trigger() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.sequence++
+	sequence := d.sequence
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = afterFunc(d.delay, func() { d.fire(sequence) })
+}
+
+// Pending returns true if a trigger is waiting for its delay period to end.
+func (d *Debouncer) Pending() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.timer != nil
+}
+
+// Cancel any pending call.
+func (d *Debouncer) Cancel() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.sequence++
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// Flush causes any pending call to be made at the next opportunity on the UI thread, without waiting for the rest of
+// the delay period.
+func (d *Debouncer) Flush() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.timer != nil && d.timer.Stop() {
+		sequence := d.sequence
+		InvokeTask(func() { d.run(sequence) })
+	}
+}
+
+func (d *Debouncer) fire(sequence int) {
+	InvokeTask(func() { d.run(sequence) })
+}
+
+func (d *Debouncer) run(sequence int) {
+	d.lock.Lock()
+	if d.sequence != sequence {
+		d.lock.Unlock()
+		return
+	}
+	d.timer = nil
+	d.lock.Unlock()
+	d.f()
+}
+
+// Throttler limits calls of its function on the UI thread to at most once per interval. The first trigger after a quiet
+// period results in an immediate call, and triggers arriving during the interval that follows are coalesced into a
+// single call made at the end of it. Its methods may be called from any goroutine.
+type Throttler struct {
+	f         func()
+	lastCall  time.Time
+	lock      sync.Mutex
+	interval  time.Duration
+	pending   bool
+	scheduled bool
+}
+
+// NewThrottler creates a new Throttler that calls f on the UI thread at most once per interval.
+func NewThrottler(interval time.Duration, f func()) *Throttler {
+	return &Throttler{
+		f:        f,
+		interval: interval,
+	}
+}
+
+// Trigger the throttler.
+func (t *Throttler) Trigger() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pending = true
+	if t.scheduled {
+		return
+	}
+	t.scheduled = true
+	if wait := t.interval - time.Since(t.lastCall); wait > 0 {
+		time.AfterFunc(wait, func() { InvokeTask(t.run) })
+	} else {
+		InvokeTask(t.run)
+	}
+}
+
+// Cancel any pending call.
+func (t *Throttler) Cancel() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pending = false
+}
+
+func (t *Throttler) run() {
+	t.lock.Lock()
+	t.scheduled = false
+	if !t.pending {
+		t.lock.Unlock()
+		return
+	}
+	t.pending = false
+	t.lastCall = time.Now()
+	t.lock.Unlock()
+	t.f()
+}
@@ -0,0 +1,76 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+)
+
+type recordingSuggestionProvider struct {
+	texts []string
+}
+
+func (p *recordingSuggestionProvider) Suggest(text string, _ func(suggestions []string)) {
+	p.texts = append(p.texts, text)
+}
+
+func TestDebouncerRestartsDelay(t *testing.T) {
+	clock := installFakeClock(t)
+	count := 0
+	d := NewDebouncer(100*time.Millisecond, func() { count++ })
+	d.Trigger()
+	clock.advance(99 * time.Millisecond)
+	d.Trigger()
+	clock.advance(99 * time.Millisecond)
+	RunPendingTasks()
+	check.Equal(t, 0, count, "each trigger restarts the delay period")
+	check.True(t, d.Pending())
+	clock.advance(time.Millisecond)
+	RunPendingTasks()
+	check.Equal(t, 1, count)
+	check.False(t, d.Pending())
+
+	d.Trigger()
+	d.Cancel()
+	clock.advance(time.Second)
+	RunPendingTasks()
+	check.Equal(t, 1, count)
+
+	d.Trigger()
+	d.Flush()
+	RunPendingTasks()
+	check.Equal(t, 2, count, "flushing makes the pending call without waiting")
+	clock.advance(time.Second)
+	RunPendingTasks()
+	check.Equal(t, 2, count)
+}
+
+func TestAutocompleteFieldDelayChange(t *testing.T) {
+	clock := installFakeClock(t)
+	p := &recordingSuggestionProvider{}
+	f := NewAutocompleteField(p)
+	f.Delay = time.Hour
+	f.DefaultRuneTyped('a')
+	clock.advance(time.Hour - time.Millisecond)
+	RunPendingTasks()
+	check.Equal(t, 0, len(p.texts), "a longer delay set after construction is honored")
+
+	f.Delay = time.Millisecond
+	f.DefaultRuneTyped('b')
+	clock.advance(time.Millisecond)
+	RunPendingTasks()
+	check.Equal(t, []string{"ab"}, p.texts, "a shorter delay replaces the pending longer one")
+	clock.advance(time.Hour)
+	RunPendingTasks()
+	check.Equal(t, []string{"ab"}, p.texts)
+}
@@ -66,6 +66,9 @@ type buttonData struct {
 	button *Button
 }
 
+// newDialogWindow creates the window a dialog is placed in. Tests substitute one that creates an offscreen window.
+var newDialogWindow = func() (*Window, error) { return NewWindow("", FloatingWindowOption()) }
+
 // Dialog holds information about a dialog.
 type Dialog struct {
 	wnd     *Window
@@ -79,10 +82,10 @@ func NewDialog(icon Drawable, iconInk Ink, msgPanel Paneler, buttonInfo []*Dialo
 	var frame Rect
 	if focused := ActiveWindow(); focused != nil {
 		frame = focused.FrameRect()
-	} else {
-		frame = PrimaryDisplay().Usable
+	} else if display := PrimaryDisplay(); display != nil {
+		frame = display.Usable
 	}
-	d.wnd, d.err = NewWindow("", FloatingWindowOption())
+	d.wnd, d.err = newDialogWindow()
 	if d.err != nil {
 		return nil, errs.NewWithCause("unable to create dialog", d.err)
 	}
@@ -252,6 +252,7 @@ func (d *dockHeader) PerformLayout(_ *Panel) {
 					remaining -= buttonSizes[overflowIndex].Width
 					hidden[tabs[i]] = true
 					d.overflowButton.Text = "»" + strconv.Itoa(len(hidden))
+					d.overflowButton.MarkForLayoutAndRedraw()
 					_, buttonSizes[overflowIndex], _ = d.overflowButton.Sizes(Size{})
 					remaining += buttonSizes[overflowIndex].Width
 					remaining -= tabSizes[i].Width + d.TabGap
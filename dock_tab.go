@@ -138,12 +138,7 @@ func (t *dockTab) updateTitle() {
 	if title != t.title.Text || t.title.Drawable != drawable {
 		t.title.Text = title
 		t.title.Drawable = drawable
-		t.NeedsLayout = true
-		t.title.NeedsLayout = true
-		if p := t.Parent(); p != nil {
-			p.NeedsLayout = true
-		}
-		t.MarkForRedraw()
+		t.title.InvalidateLayout()
 	}
 }
 
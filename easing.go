@@ -0,0 +1,103 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+)
+
+// Easing maps linear progress in the range 0-1 to eased progress. The result will be 0 for an input of 0 and 1 for an
+// input of 1, but may go outside of that range in between for easings that overshoot.
+type Easing func(t float32) float32
+
+// Some commonly used easing curves.
+var (
+	EaseLinear    Easing = func(t float32) float32 { return t }
+	EaseIn        Easing = func(t float32) float32 { return t * t * t }
+	EaseOut       Easing = func(t float32) float32 { t = 1 - t; return 1 - t*t*t }
+	EaseInOut     Easing = CubicBezierEasing(0.42, 0, 0.58, 1)
+	EaseStandard  Easing = CubicBezierEasing(0.25, 0.1, 0.25, 1)
+	EaseOutBack   Easing = easeOutBack
+	EaseOutBounce Easing = easeOutBounce
+)
+
+func easeOutBack(t float32) float32 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	t--
+	return 1 + c3*t*t*t + c1*t*t
+}
+
+func easeOutBounce(t float32) float32 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// CubicBezierEasing returns an Easing that follows a cubic Bézier curve from (0,0) to (1,1) with the control points
+// (x1,y1) and (x2,y2), as is done for CSS transitions. x1 and x2 are clamped to the range 0-1.
+func CubicBezierEasing(x1, y1, x2, y2 float32) Easing {
+	x1 = min(max(x1, 0), 1)
+	x2 = min(max(x2, 0), 1)
+	cx := 3 * float64(x1)
+	bx := 3*(float64(x2)-float64(x1)) - cx
+	ax := 1 - cx - bx
+	cy := 3 * float64(y1)
+	by := 3*(float64(y2)-float64(y1)) - cy
+	ay := 1 - cy - by
+	sampleX := func(s float64) float64 { return ((ax*s+bx)*s + cx) * s }
+	sampleDX := func(s float64) float64 { return (3*ax*s+2*bx)*s + cx }
+	return func(t float32) float32 {
+		if t <= 0 {
+			return 0
+		}
+		if t >= 1 {
+			return 1
+		}
+		x := float64(t)
+		// Newton's method first, falling back to bisection if it fails to converge
+		s := x
+		for i := 0; i < 8; i++ {
+			dx := sampleX(s) - x
+			if math.Abs(dx) < 1e-6 {
+				return float32(((ay*s+by)*s + cy) * s)
+			}
+			d := sampleDX(s)
+			if math.Abs(d) < 1e-6 {
+				break
+			}
+			s -= dx / d
+		}
+		lo := 0.0
+		hi := 1.0
+		s = x
+		for i := 0; i < 32 && hi-lo > 1e-7; i++ {
+			if sampleX(s) < x {
+				lo = s
+			} else {
+				hi = s
+			}
+			s = (lo + hi) / 2
+		}
+		return float32(((ay*s+by)*s + cy) * s)
+	}
+}
@@ -0,0 +1,122 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package anchor
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	Left Enum = iota
+	Right
+	Top
+	Bottom
+	Width
+	Height
+	CenterX
+	CenterY
+)
+
+// All possible values.
+var All = []Enum{
+	Left,
+	Right,
+	Top,
+	Bottom,
+	Width,
+	Height,
+	CenterX,
+	CenterY,
+}
+
+// Enum holds an attribute of a panel's frame that a layout constraint can refer to.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= CenterY {
+		return e
+	}
+	return Left
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	case Top:
+		return "top"
+	case Bottom:
+		return "bottom"
+	case Width:
+		return "width"
+	case Height:
+		return "height"
+	case CenterX:
+		return "center-x"
+	case CenterY:
+		return "center-y"
+	default:
+		return Left.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case Left:
+		return i18n.Text("Left")
+	case Right:
+		return i18n.Text("Right")
+	case Top:
+		return i18n.Text("Top")
+	case Bottom:
+		return i18n.Text("Bottom")
+	case Width:
+		return i18n.Text("Width")
+	case Height:
+		return i18n.Text("Height")
+	case CenterX:
+		return i18n.Text("Center-X")
+	case CenterY:
+		return i18n.Text("Center-Y")
+	default:
+		return Left.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return Left
+}
@@ -0,0 +1,98 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package flexdirection
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	Row           Enum = iota // Lay out left to right
+	RowReverse                // Lay out right to left
+	Column                    // Lay out top to bottom
+	ColumnReverse             // Lay out bottom to top
+)
+
+// All possible values.
+var All = []Enum{
+	Row,
+	RowReverse,
+	Column,
+	ColumnReverse,
+}
+
+// Enum holds the direction of the main axis of a flexbox layout.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= ColumnReverse {
+		return e
+	}
+	return Row
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case Row:
+		return "row"
+	case RowReverse:
+		return "row-reverse"
+	case Column:
+		return "column"
+	case ColumnReverse:
+		return "column-reverse"
+	default:
+		return Row.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case Row:
+		return i18n.Text("Row")
+	case RowReverse:
+		return i18n.Text("Row-Reverse")
+	case Column:
+		return i18n.Text("Column")
+	case ColumnReverse:
+		return i18n.Text("Column-Reverse")
+	default:
+		return Row.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return Row
+}
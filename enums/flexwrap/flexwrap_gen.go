@@ -0,0 +1,92 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package flexwrap
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	NoWrap      Enum = iota // Keep all children on a single line
+	Wrap                    // Wrap children onto additional lines, which are stacked along the cross axis
+	WrapReverse             // Wrap children onto additional lines, which are stacked in reverse along the cross axis
+)
+
+// All possible values.
+var All = []Enum{
+	NoWrap,
+	Wrap,
+	WrapReverse,
+}
+
+// Enum controls whether a flexbox layout wraps its children onto multiple lines.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= WrapReverse {
+		return e
+	}
+	return NoWrap
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case NoWrap:
+		return "no-wrap"
+	case Wrap:
+		return "wrap"
+	case WrapReverse:
+		return "wrap-reverse"
+	default:
+		return NoWrap.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case NoWrap:
+		return i18n.Text("No-Wrap")
+	case Wrap:
+		return i18n.Text("Wrap")
+	case WrapReverse:
+		return i18n.Text("Wrap-Reverse")
+	default:
+		return NoWrap.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return NoWrap
+}
@@ -0,0 +1,98 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gridtrack
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	Auto       Enum = iota // Size to the content, using its minimum size as a minimum and its preferred size as a maximum
+	Fixed                  // Size to a fixed value
+	MinContent             // Size to the largest minimum size of the content
+	Fr                     // Take a share of the space left over after all other tracks have been sized
+)
+
+// All possible values.
+var All = []Enum{
+	Auto,
+	Fixed,
+	MinContent,
+	Fr,
+}
+
+// Enum holds the kind of sizing function used for a track in a grid layout.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= Fr {
+		return e
+	}
+	return Auto
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case Auto:
+		return "auto"
+	case Fixed:
+		return "fixed"
+	case MinContent:
+		return "min-content"
+	case Fr:
+		return "fr"
+	default:
+		return Auto.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case Auto:
+		return i18n.Text("Auto")
+	case Fixed:
+		return i18n.Text("Fixed")
+	case MinContent:
+		return i18n.Text("Min-Content")
+	case Fr:
+		return i18n.Text("Fr")
+	default:
+		return Auto.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return Auto
+}
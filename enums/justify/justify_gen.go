@@ -0,0 +1,110 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package justify
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	Start        Enum = iota // Pack items toward the start
+	End                      // Pack items toward the end
+	Center                   // Pack items around the center
+	SpaceBetween             // Place the first and last items at the edges, distributing the space evenly between items
+	SpaceAround              // Give each item equal space on either side, so the space at the edges is half that between items
+	SpaceEvenly              // Distribute the space so that the space at the edges and between items is the same
+)
+
+// All possible values.
+var All = []Enum{
+	Start,
+	End,
+	Center,
+	SpaceBetween,
+	SpaceAround,
+	SpaceEvenly,
+}
+
+// Enum specifies how to distribute extra space between items along an axis.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= SpaceEvenly {
+		return e
+	}
+	return Start
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case Start:
+		return "start"
+	case End:
+		return "end"
+	case Center:
+		return "center"
+	case SpaceBetween:
+		return "space-between"
+	case SpaceAround:
+		return "space-around"
+	case SpaceEvenly:
+		return "space-evenly"
+	default:
+		return Start.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case Start:
+		return i18n.Text("Start")
+	case End:
+		return i18n.Text("End")
+	case Center:
+		return i18n.Text("Center")
+	case SpaceBetween:
+		return i18n.Text("Space-Between")
+	case SpaceAround:
+		return i18n.Text("Space-Around")
+	case SpaceEvenly:
+		return i18n.Text("Space-Evenly")
+	default:
+		return Start.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return Start
+}
@@ -0,0 +1,92 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 2021-2024 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package relation
+
+import (
+	"strings"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	Equal Enum = iota
+	LessOrEqual
+	GreaterOrEqual
+)
+
+// All possible values.
+var All = []Enum{
+	Equal,
+	LessOrEqual,
+	GreaterOrEqual,
+}
+
+// Enum holds the relationship between the two sides of a layout constraint.
+type Enum byte
+
+// EnsureValid ensures this is of a known value.
+func (e Enum) EnsureValid() Enum {
+	if e <= GreaterOrEqual {
+		return e
+	}
+	return Equal
+}
+
+// Key returns the key used in serialization.
+func (e Enum) Key() string {
+	switch e {
+	case Equal:
+		return "equal"
+	case LessOrEqual:
+		return "less-or-equal"
+	case GreaterOrEqual:
+		return "greater-or-equal"
+	default:
+		return Equal.Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (e Enum) String() string {
+	switch e {
+	case Equal:
+		return i18n.Text("Equal")
+	case LessOrEqual:
+		return i18n.Text("Less-Or-Equal")
+	case GreaterOrEqual:
+		return i18n.Text("Greater-Or-Equal")
+	default:
+		return Equal.String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum) MarshalText() (text []byte, err error) {
+	return []byte(e.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum) UnmarshalText(text []byte) error {
+	*e = Extract(string(text))
+	return nil
+}
+
+// Extract the value from a string.
+func Extract(str string) Enum {
+	for _, e := range All {
+		if strings.EqualFold(e.Key(), str) {
+			return e
+		}
+	}
+	return Equal
+}
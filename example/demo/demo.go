@@ -34,6 +34,9 @@ func NewDemoWindow(where unison.Point) (*unison.Window, error) {
 	// Install our menus
 	installDefaultMenus(wnd)
 
+	// Allow the panel hierarchy to be examined with the default inspector key binding
+	unison.InstallInspector(wnd, unison.KeyBinding{})
+
 	// Put some empty space around the edges of our window and apply a single column layout.
 	content := wnd.Content()
 	content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(10)))
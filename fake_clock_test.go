@@ -0,0 +1,89 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock stands in for the real clock used by delayed calls, only moving forward when advanced.
+type fakeClock struct {
+	lock   sync.Mutex
+	now    time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	f     func()
+	at    time.Duration
+	done  bool
+}
+
+// installFakeClock replaces the clock used by delayed calls until the test finishes.
+func installFakeClock(t *testing.T) *fakeClock {
+	t.Helper()
+	c := &fakeClock{}
+	prev := afterFunc
+	afterFunc = c.afterFunc
+	t.Cleanup(func() { afterFunc = prev })
+	return c
+}
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) stoppable {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	timer := &fakeTimer{
+		clock: c,
+		f:     f,
+		at:    c.now + d,
+	}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// advance moves the clock forward, making any calls that come due along the way in order.
+func (c *fakeClock) advance(d time.Duration) {
+	c.lock.Lock()
+	end := c.now + d
+	for {
+		c.timers = slices.DeleteFunc(c.timers, func(timer *fakeTimer) bool { return timer.done })
+		i := -1
+		for j, timer := range c.timers {
+			if timer.at <= end && (i == -1 || timer.at < c.timers[i].at) {
+				i = j
+			}
+		}
+		if i == -1 {
+			break
+		}
+		timer := c.timers[i]
+		timer.done = true
+		c.now = timer.at
+		c.lock.Unlock()
+		timer.f()
+		c.lock.Lock()
+	}
+	c.now = end
+	c.lock.Unlock()
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
@@ -83,6 +83,7 @@ type Field struct {
 	FieldTheme
 	ModifiedCallback   func(before, after *FieldState)
 	ValidateCallback   func() bool
+	modifiedHook       func()
 	Watermark          string
 	undoID             int64
 	runes              []rune
@@ -567,7 +568,7 @@ func (f *Field) DefaultKeyDown(keyCode KeyCode, mod Modifiers, _ bool) bool {
 			before := f.GetFieldState()
 			f.runes = append(f.runes[:f.selectionStart], f.runes[f.selectionStart+1:]...)
 			f.linesBuiltFor = -1
-			f.notifyOfModification(before, f.GetFieldState())
+			f.notifyOfModification(before, f.GetFieldState(), true)
 		}
 		f.MarkForRedraw()
 	case KeyLeft:
@@ -630,7 +631,7 @@ func (f *Field) DefaultRuneTyped(ch rune) bool {
 	f.runes = append(f.runes[:f.selectionStart], append([]rune{ch}, f.runes[f.selectionStart:]...)...)
 	f.linesBuiltFor = -1
 	f.SetSelectionTo(f.selectionStart + 1)
-	f.notifyOfModification(before, f.GetFieldState())
+	f.notifyOfModification(before, f.GetFieldState(), true)
 	return true
 }
 
@@ -918,7 +919,7 @@ func (f *Field) Paste() {
 		f.runes = append(f.runes[:f.selectionStart], append(runes, f.runes[f.selectionStart:]...)...)
 		f.linesBuiltFor = -1
 		f.SetSelectionTo(f.selectionStart + len(runes))
-		f.notifyOfModification(before, f.GetFieldState())
+		f.notifyOfModification(before, f.GetFieldState(), true)
 	} else if f.HasSelectionRange() {
 		f.Delete()
 	}
@@ -951,7 +952,7 @@ func (f *Field) Delete() {
 			f.runes = append(f.runes[:f.selectionStart-1], f.runes[f.selectionStart:]...)
 			f.SetSelectionTo(f.selectionStart - 1)
 		}
-		f.notifyOfModification(before, f.GetFieldState())
+		f.notifyOfModification(before, f.GetFieldState(), true)
 		f.MarkForRedraw()
 	}
 }
@@ -980,12 +981,17 @@ func (f *Field) SetText(text string) {
 		f.runes = runes
 		f.linesBuiltFor = -1
 		f.SetSelectionToEnd()
-		f.notifyOfModification(before, f.GetFieldState())
+		f.notifyOfModification(before, f.GetFieldState(), false)
 	}
 }
 
-func (f *Field) notifyOfModification(before, after *FieldState) {
+// notifyOfModification is called after the text has changed. userEdit should be true if the user made the change, as
+// only those changes are passed to the modifiedHook.
+func (f *Field) notifyOfModification(before, after *FieldState, userEdit bool) {
 	f.MarkForRedraw()
+	if userEdit && f.modifiedHook != nil {
+		f.modifiedHook()
+	}
 	if f.ModifiedCallback != nil {
 		f.ModifiedCallback(before, after)
 	}
@@ -0,0 +1,427 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/flexdirection"
+	"github.com/ddkwork/unison/enums/flexwrap"
+	"github.com/ddkwork/unison/enums/justify"
+	"github.com/ddkwork/unison/enums/selfalign"
+)
+
+var _ Layout = &FlexBoxLayout{}
+
+// FlexBoxLayout is a Layout that follows the CSS flexbox model. Children are placed one after another along the main
+// axis, as determined by Direction, and are optionally wrapped onto additional lines that are stacked along the cross
+// axis. Children may be given a *FlexBoxLayoutData as their layout data to control how they grow, shrink, are ordered
+// and are aligned. Children without one behave as if they had a FlexBoxLayoutData with all fields set to their zero
+// values.
+type FlexBoxLayout struct {
+	// Direction determines the main axis and the direction children are placed along it.
+	Direction flexdirection.Enum
+	// Wrap determines whether children that don't fit along the main axis are moved onto additional lines.
+	Wrap flexwrap.Enum
+	// Justify determines how any extra space along the main axis is distributed on each line.
+	Justify justify.Enum
+	// AlignItems determines how children are aligned along the cross axis within their line.
+	AlignItems align.Enum
+	// AlignContent determines how any extra space along the cross axis is distributed between lines. Only used when
+	// wrapping.
+	AlignContent justify.Enum
+	// HSpacing is the gap placed between children or lines horizontally.
+	HSpacing float32
+	// VSpacing is the gap placed between children or lines vertically.
+	VSpacing float32
+}
+
+// FlexBoxLayoutData is used to control how a child is laid out by a FlexBoxLayout.
+type FlexBoxLayoutData struct {
+	// Grow is the share of the extra main axis space on the child's line that the child receives, relative to the other
+	// children on the line. Zero means the child doesn't grow.
+	Grow float32
+	// Shrink is the share of the main axis overflow on the child's line that the child absorbs, relative to the other
+	// children on the line and weighted by the child's basis. Values less than or equal to zero are treated as 1, as
+	// in CSS.
+	Shrink float32
+	// Basis is the main axis size of the child before growing or shrinking. Values less than or equal to zero cause
+	// the child's preferred size to be used.
+	Basis float32
+	// Order determines where the child is placed relative to its siblings. Children with lower values are placed
+	// first; children with the same value are placed in the order they were added.
+	Order int
+	// AlignSelf overrides the FlexBoxLayout's AlignItems value for this child.
+	AlignSelf selfalign.Enum
+	// NoShrink, if true, prevents the child from shrinking below its basis, regardless of Shrink.
+	NoShrink bool
+}
+
+// shrinkFactor returns the effective Shrink value, which is zero if the child may not shrink.
+func (d *FlexBoxLayoutData) shrinkFactor() float32 {
+	switch {
+	case d.NoShrink:
+		return 0
+	case d.Shrink <= 0:
+		return 1
+	default:
+		return d.Shrink
+	}
+}
+
+type flexBoxItem struct {
+	panel        *Panel
+	data         FlexBoxLayoutData
+	minSize      Size
+	prefSize     Size
+	maxSize      Size
+	basis        float32
+	hypothetical float32
+	target       float32
+	main         float32
+	cross        float32
+	frozen       bool
+}
+
+type flexBoxLine struct {
+	items []*flexBoxItem
+	main  float32
+	cross float32
+}
+
+// LayoutSizes implements Layout.
+func (f *FlexBoxLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, maxSize Size) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	items := f.items(target)
+	mainGap := f.mainGap()
+	crossGap := f.crossGap()
+	var minMain, minCross float32
+	for i, item := range items {
+		itemMin := item.hypothetical
+		if item.data.shrinkFactor() > 0 {
+			itemMin = f.main(item.minSize)
+		}
+		if f.Wrap == flexwrap.NoWrap {
+			if i != 0 {
+				minMain += mainGap
+			}
+			minMain += itemMin
+			minCross = max(minCross, f.cross(item.minSize))
+		} else {
+			if i != 0 {
+				minCross += crossGap
+			}
+			minMain = max(minMain, itemMin)
+			minCross += f.cross(item.minSize)
+		}
+	}
+	avail := float32(math.MaxFloat32)
+	if f.Wrap != flexwrap.NoWrap {
+		hintMain := f.main(hint) - f.main(Size{Width: insets.Width(), Height: insets.Height()})
+		if hintMain >= 1 {
+			avail = hintMain
+		}
+	}
+	var prefMain, prefCross float32
+	for i, line := range f.lines(items, avail) {
+		f.resolve(line, math.MaxFloat32)
+		if i != 0 {
+			prefCross += crossGap
+		}
+		prefMain = max(prefMain, line.main)
+		prefCross += line.cross
+	}
+	minSize = f.size(minMain, minCross)
+	prefSize = f.size(max(prefMain, minMain), max(prefCross, minCross))
+	minSize.AddInsets(insets)
+	prefSize.AddInsets(insets)
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (f *FlexBoxLayout) PerformLayout(target *Panel) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	content := target.ContentRect(true)
+	content.X = insets.Left
+	content.Y = insets.Top
+	content.Width = max(content.Width-insets.Width(), 0)
+	content.Height = max(content.Height-insets.Height(), 0)
+	availMain := f.main(content.Size)
+	availCross := f.cross(content.Size)
+	lines := f.lines(f.items(target), availMain)
+	mainGap := f.mainGap()
+	crossGap := f.crossGap()
+	var usedCross float32
+	for i, line := range lines {
+		f.resolve(line, availMain)
+		if f.Wrap == flexwrap.NoWrap {
+			line.cross = availCross
+		}
+		if i != 0 {
+			usedCross += crossGap
+		}
+		usedCross += line.cross
+	}
+	crossPos, lineGap := justifySpacing(f.AlignContent, availCross-usedCross, len(lines))
+	for _, line := range lines {
+		var used float32
+		for i, item := range line.items {
+			if i != 0 {
+				used += mainGap
+			}
+			used += item.main
+		}
+		mainPos, itemGap := justifySpacing(f.Justify, availMain-used, len(line.items))
+		for _, item := range line.items {
+			itemCross := item.cross
+			itemCrossPos := crossPos
+			switch resolveSelfAlign(item.data.AlignSelf, f.AlignItems) {
+			case align.Middle:
+				itemCrossPos += (line.cross - itemCross) / 2
+			case align.End:
+				itemCrossPos += line.cross - itemCross
+			case align.Fill:
+				itemCross = max(min(line.cross, f.cross(item.maxSize)), f.cross(item.minSize))
+			default:
+			}
+			itemMainPos := mainPos
+			if f.Direction == flexdirection.RowReverse || f.Direction == flexdirection.ColumnReverse {
+				itemMainPos = availMain - (itemMainPos + item.main)
+			}
+			if f.Wrap == flexwrap.WrapReverse {
+				itemCrossPos = availCross - (itemCrossPos + itemCross)
+			}
+			r := Rect{Size: f.size(item.main, itemCross)}
+			if f.horizontal() {
+				r.Point = Point{X: content.X + itemMainPos, Y: content.Y + itemCrossPos}
+			} else {
+				r.Point = Point{X: content.X + itemCrossPos, Y: content.Y + itemMainPos}
+			}
+			item.panel.SetFrameRect(r)
+			mainPos += item.main + mainGap + itemGap
+		}
+		crossPos += line.cross + crossGap + lineGap
+	}
+}
+
+// items returns the children of the target, ordered for layout and with their hypothetical main sizes determined.
+func (f *FlexBoxLayout) items(target *Panel) []*flexBoxItem {
+	children := target.Children()
+	items := make([]*flexBoxItem, 0, len(children))
+	for _, child := range children {
+		item := &flexBoxItem{panel: child}
+		if data, ok := child.LayoutData().(*FlexBoxLayoutData); ok && data != nil {
+			item.data = *data
+		}
+		item.minSize, item.prefSize, item.maxSize = child.Sizes(Size{})
+		item.basis = item.data.Basis
+		if item.basis <= 0 {
+			item.basis = f.main(item.prefSize)
+		}
+		item.hypothetical = max(min(item.basis, f.main(item.maxSize)), f.main(item.minSize))
+		items = append(items, item)
+	}
+	slices.SortStableFunc(items, func(a, b *flexBoxItem) int { return cmp.Compare(a.data.Order, b.data.Order) })
+	return items
+}
+
+// lines breaks the items into lines that fit within the available main axis space.
+func (f *FlexBoxLayout) lines(items []*flexBoxItem, avail float32) []*flexBoxLine {
+	gap := f.mainGap()
+	var lines []*flexBoxLine
+	var line *flexBoxLine
+	var used float32
+	for _, item := range items {
+		if line != nil && f.Wrap != flexwrap.NoWrap && used+gap+item.hypothetical > avail {
+			line = nil
+		}
+		if line == nil {
+			line = &flexBoxLine{}
+			lines = append(lines, line)
+			used = item.hypothetical
+		} else {
+			used += gap + item.hypothetical
+		}
+		line.items = append(line.items, item)
+	}
+	return lines
+}
+
+// resolve determines the main and cross sizes of the items on the line by growing or shrinking them to fill the
+// available main axis space. Pass math.MaxFloat32 for avail to leave the items at their hypothetical main sizes.
+func (f *FlexBoxLayout) resolve(line *flexBoxLine, avail float32) {
+	gaps := f.mainGap() * float32(len(line.items)-1)
+	var used float32
+	for _, item := range line.items {
+		item.main = item.hypothetical
+		item.frozen = false
+		used += item.hypothetical
+	}
+	if avail != math.MaxFloat32 && used+gaps != avail {
+		growing := used+gaps < avail
+		unfrozen := 0
+		for _, item := range line.items {
+			switch {
+			case growing && (item.data.Grow <= 0 || item.basis > item.hypothetical),
+				!growing && (item.data.shrinkFactor() <= 0 || item.basis < item.hypothetical):
+				item.frozen = true
+			default:
+				unfrozen++
+			}
+		}
+		for unfrozen > 0 {
+			free := avail - gaps
+			var factors float32
+			for _, item := range line.items {
+				if item.frozen {
+					free -= item.main
+				} else {
+					free -= item.basis
+					if growing {
+						factors += item.data.Grow
+					} else {
+						factors += item.data.shrinkFactor() * item.basis
+					}
+				}
+			}
+			if factors <= 0 {
+				break
+			}
+			if growing && factors < 1 {
+				free *= factors
+			}
+			var violation float32
+			for _, item := range line.items {
+				if item.frozen {
+					continue
+				}
+				if growing {
+					item.target = item.basis + free*item.data.Grow/factors
+				} else {
+					item.target = item.basis + free*item.data.shrinkFactor()*item.basis/factors
+				}
+				item.main = max(min(item.target, f.main(item.maxSize)), f.main(item.minSize))
+				violation += item.main - item.target
+			}
+			for _, item := range line.items {
+				if !item.frozen && (violation == 0 || (violation > 0 && item.main > item.target) ||
+					(violation < 0 && item.main < item.target)) {
+					item.frozen = true
+					unfrozen--
+				}
+			}
+		}
+	}
+	line.main = gaps
+	line.cross = 0
+	for _, item := range line.items {
+		line.main += item.main
+		if f.horizontal() {
+			// The height of a child may depend upon its width, such as for wrapped text
+			minSize, prefSize, maxSize := item.panel.Sizes(Size{Width: item.main})
+			item.cross = max(min(prefSize.Height, maxSize.Height), minSize.Height)
+		} else {
+			item.cross = max(min(item.prefSize.Width, item.maxSize.Width), item.minSize.Width)
+		}
+		line.cross = max(line.cross, item.cross)
+	}
+}
+
+func (f *FlexBoxLayout) horizontal() bool {
+	return f.Direction == flexdirection.Row || f.Direction == flexdirection.RowReverse
+}
+
+func (f *FlexBoxLayout) main(size Size) float32 {
+	if f.horizontal() {
+		return size.Width
+	}
+	return size.Height
+}
+
+func (f *FlexBoxLayout) cross(size Size) float32 {
+	if f.horizontal() {
+		return size.Height
+	}
+	return size.Width
+}
+
+func (f *FlexBoxLayout) size(main, cross float32) Size {
+	if f.horizontal() {
+		return Size{Width: main, Height: cross}
+	}
+	return Size{Width: cross, Height: main}
+}
+
+func (f *FlexBoxLayout) mainGap() float32 {
+	if f.horizontal() {
+		return f.HSpacing
+	}
+	return f.VSpacing
+}
+
+func (f *FlexBoxLayout) crossGap() float32 {
+	if f.horizontal() {
+		return f.VSpacing
+	}
+	return f.HSpacing
+}
+
+// resolveSelfAlign returns the alignment to use for an item, given its own alignment and the alignment its container
+// specifies.
+func resolveSelfAlign(self selfalign.Enum, inherited align.Enum) align.Enum {
+	switch self {
+	case selfalign.Start:
+		return align.Start
+	case selfalign.Middle:
+		return align.Middle
+	case selfalign.End:
+		return align.End
+	case selfalign.Fill:
+		return align.Fill
+	default:
+		return inherited
+	}
+}
+
+// justifySpacing returns the offset of the first of count items and the extra spacing to place between each item in
+// order to distribute the free space as specified. Negative free space is only used to offset the first item.
+func justifySpacing(j justify.Enum, free float32, count int) (lead, between float32) {
+	switch j {
+	case justify.End:
+		return free, 0
+	case justify.Center:
+		return free / 2, 0
+	case justify.SpaceBetween:
+		if free > 0 && count > 1 {
+			return 0, free / float32(count-1)
+		}
+	case justify.SpaceAround:
+		if free > 0 && count > 0 {
+			between = free / float32(count)
+			return between / 2, between
+		}
+	case justify.SpaceEvenly:
+		if free > 0 && count > 0 {
+			between = free / float32(count+1)
+			return between, between
+		}
+	default:
+	}
+	return 0, 0
+}
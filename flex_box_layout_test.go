@@ -0,0 +1,63 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/flexdirection"
+	"github.com/ddkwork/unison/enums/flexwrap"
+	"github.com/ddkwork/unison/enums/justify"
+)
+
+func TestFlexBoxLayout(t *testing.T) {
+	parent := unison.NewPanel()
+	layout := &unison.FlexBoxLayout{HSpacing: 10}
+	parent.SetLayout(layout)
+	a := newLayoutChild(parent, nil)
+	b := newLayoutChild(parent, &unison.FlexBoxLayoutData{Grow: 1, NoShrink: true})
+	c := newLayoutChild(parent, &unison.FlexBoxLayoutData{Order: -1})
+	_, pref, _ := parent.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 170, Height: 20}, pref)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 300, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, unison.Rect{Size: unison.Size{Width: 50, Height: 20}}, c.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 60}, Size: unison.Size{Width: 50, Height: 20}}, a.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 120}, Size: unison.Size{Width: 180, Height: 20}}, b.FrameRect())
+
+	// Shrinking stops at the minimum size, and children with NoShrink set don't shrink at all
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 40, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, float32(10), c.FrameRect().Width)
+	check.Equal(t, float32(10), a.FrameRect().Width)
+	check.Equal(t, float32(50), b.FrameRect().Width)
+
+	layout.Direction = flexdirection.RowReverse
+	layout.Justify = justify.SpaceBetween
+	b.SetLayoutData(nil)
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 300, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, float32(250), c.FrameRect().X)
+	check.Equal(t, float32(125), a.FrameRect().X)
+	check.Equal(t, float32(0), b.FrameRect().X)
+
+	layout.Direction = flexdirection.Row
+	layout.Wrap = flexwrap.Wrap
+	layout.VSpacing = 5
+	layout.Justify = justify.Start
+	_, pref, _ = parent.Sizes(unison.Size{Width: 120})
+	check.Equal(t, unison.Size{Width: 110, Height: 45}, pref)
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 120, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, unison.Rect{Point: unison.Point{Y: 25}, Size: unison.Size{Width: 50, Height: 20}}, b.FrameRect())
+}
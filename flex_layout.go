@@ -18,7 +18,6 @@ var _ Layout = &FlexLayout{}
 
 // FlexLayout lays out the children of its Layoutable based on the FlexLayoutData assigned to each child.
 type FlexLayout struct {
-	sizingCache  map[*Panel]map[Size]*flexSizingCacheData
 	rows         int
 	Columns      int
 	HSpacing     float32
@@ -28,7 +27,7 @@ type FlexLayout struct {
 	EqualColumns bool
 }
 
-type flexSizingCacheData struct {
+type flexSizingData struct {
 	minSize  Size
 	prefSize Size
 	maxSize  Size
@@ -50,7 +49,6 @@ type FlexLayoutData struct {
 
 // LayoutSizes implements the Layout interface.
 func (f *FlexLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, maxSize Size) {
-	f.sizingCache = make(map[*Panel]map[Size]*flexSizingCacheData)
 	var insets Insets
 	if b := target.Border(); b != nil {
 		insets = b.Insets()
@@ -65,7 +63,6 @@ func (f *FlexLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, m
 
 // PerformLayout implements the Layout interface.
 func (f *FlexLayout) PerformLayout(target *Panel) {
-	f.sizingCache = make(map[*Panel]map[Size]*flexSizingCacheData)
 	var insets Insets
 	if b := target.Border(); b != nil {
 		insets = b.Insets()
@@ -120,27 +117,19 @@ func (f *FlexLayout) layout(target *Panel, location Point, hint Size, move, useM
 	return totalSize
 }
 
-func (f *FlexLayout) sizingCacheData(panel *Panel, hint Size) *flexSizingCacheData {
-	m, ok := f.sizingCache[panel]
-	if !ok {
-		m = make(map[Size]*flexSizingCacheData)
-		f.sizingCache[panel] = m
-	}
-	var data *flexSizingCacheData
-	if data, ok = m[hint]; !ok {
-		var sizing flexSizingCacheData
-		sizing.minSize, sizing.prefSize, sizing.maxSize = panel.Sizes(hint)
-		data = &sizing
-		m[hint] = data
-	}
-	return data
+// sizingData returns the sizes of the panel for the hint. Repeated requests are answered from the panel's own cache,
+// which is dropped when the panel is marked as needing layout.
+func (f *FlexLayout) sizingData(panel *Panel, hint Size) *flexSizingData {
+	var sizing flexSizingData
+	sizing.minSize, sizing.prefSize, sizing.maxSize = panel.Sizes(hint)
+	return &sizing
 }
 
 func (f *FlexLayout) prepChildren(target *Panel, useMinimumSize bool) []*Panel {
 	var hint Size
 	children := target.Children()
 	for _, child := range children {
-		getDataFromTarget(child).computeCacheSize(f.sizingCacheData(child, hint), hint, useMinimumSize)
+		getDataFromTarget(child).computeCacheSize(f.sizingData(child, hint), hint, useMinimumSize)
 	}
 	return children
 }
@@ -443,7 +432,7 @@ func (f *FlexLayout) wrap(width float32, grid [][]*Panel, widths []float32, useM
 						currentWidth += float32(hSpan-1) * f.HSpacing
 						if currentWidth != data.cacheSize.Width && data.HAlign == align.Fill || data.cacheSize.Width > currentWidth {
 							hint := Size{Width: max(data.minCacheSize.Width, currentWidth)}
-							data.computeCacheSize(f.sizingCacheData(grid[i][j], hint), hint, useMinimumSize)
+							data.computeCacheSize(f.sizingData(grid[i][j], hint), hint, useMinimumSize)
 							minimumHeight := data.MinSize.Height
 							if data.VGrab && minimumHeight > 0 && data.cacheSize.Height < minimumHeight {
 								data.cacheSize.Height = minimumHeight
@@ -653,7 +642,7 @@ func (f *FlexLayout) positionChildren(location Point, grid [][]*Panel, widths, h
 	}
 }
 
-func (f *FlexLayoutData) computeCacheSize(sizing *flexSizingCacheData, hint Size, useMinimumSize bool) {
+func (f *FlexLayoutData) computeCacheSize(sizing *flexSizingData, hint Size, useMinimumSize bool) {
 	f.cacheSize.Width = 0
 	f.cacheSize.Height = 0
 	f.minCacheSize.Width = 0
@@ -70,6 +70,21 @@ func main() {
 			{Key: "fill"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/anchor",
+		Name: "anchor",
+		Desc: "holds an attribute of a panel's frame that a layout constraint can refer to",
+		Values: []enumValue{
+			{Key: "left"},
+			{Key: "right"},
+			{Key: "top"},
+			{Key: "bottom"},
+			{Key: "width"},
+			{Key: "height"},
+			{Key: "center-x"},
+			{Key: "center-y"},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "enums/arcsize",
 		Name: "arcsize",
@@ -177,6 +192,38 @@ func main() {
 			{Key: "linear", Comment: "Interpolate between 2x2 sample points (bilinear interpolation)"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/flexdirection",
+		Name: "flexdirection",
+		Desc: "holds the direction of the main axis of a flexbox layout",
+		Values: []enumValue{
+			{Key: "row", Comment: "Lay out left to right"},
+			{Key: "row-reverse", Comment: "Lay out right to left"},
+			{Key: "column", Comment: "Lay out top to bottom"},
+			{Key: "column-reverse", Comment: "Lay out bottom to top"},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/flexwrap",
+		Name: "flexwrap",
+		Desc: "controls whether a flexbox layout wraps its children onto multiple lines",
+		Values: []enumValue{
+			{Key: "no-wrap", Comment: "Keep all children on a single line"},
+			{Key: "wrap", Comment: "Wrap children onto additional lines, which are stacked along the cross axis"},
+			{Key: "wrap-reverse", Comment: "Wrap children onto additional lines, which are stacked in reverse along the cross axis"},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/gridtrack",
+		Name: "gridtrack",
+		Desc: "holds the kind of sizing function used for a track in a grid layout",
+		Values: []enumValue{
+			{Key: "auto", Comment: "Size to the content, using its minimum size as a minimum and its preferred size as a maximum"},
+			{Key: "fixed", Comment: "Size to a fixed value"},
+			{Key: "min-content", Comment: "Size to the largest minimum size of the content"},
+			{Key: "fr", Comment: "Take a share of the space left over after all other tracks have been sized"},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "enums/imgfmt",
 		Name: "imgfmt",
@@ -202,6 +249,19 @@ func main() {
 			{Key: "lightness"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/justify",
+		Name: "justify",
+		Desc: "specifies how to distribute extra space between items along an axis",
+		Values: []enumValue{
+			{Key: "start", Comment: "Pack items toward the start"},
+			{Key: "end", Comment: "Pack items toward the end"},
+			{Key: "center", Comment: "Pack items around the center"},
+			{Key: "space-between", Comment: "Place the first and last items at the edges, distributing the space evenly between items"},
+			{Key: "space-around", Comment: "Give each item equal space on either side, so the space at the edges is half that between items"},
+			{Key: "space-evenly", Comment: "Distribute the space so that the space at the edges and between items is the same"},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:      "enums/mipmapmode",
 		Name:     "mipmapmode",
@@ -255,6 +315,28 @@ func main() {
 			{Key: "polygon"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/relation",
+		Name: "relation",
+		Desc: "holds the relationship between the two sides of a layout constraint",
+		Values: []enumValue{
+			{Key: "equal"},
+			{Key: "less-or-equal"},
+			{Key: "greater-or-equal"},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "enums/selfalign",
+		Name: "selfalign",
+		Desc: "specifies how to align an individual item, overriding the alignment its container specifies",
+		Values: []enumValue{
+			{Key: "auto", Comment: "Use the alignment specified by the container"},
+			{Key: "start"},
+			{Key: "middle"},
+			{Key: "end"},
+			{Key: "fill"},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "enums/side",
 		Name: "side",
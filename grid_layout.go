@@ -0,0 +1,566 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/gridtrack"
+	"github.com/ddkwork/unison/enums/selfalign"
+)
+
+var _ Layout = &GridLayout{}
+
+// GridTrackSize is a sizing function for one end of a GridTrack.
+type GridTrackSize struct {
+	// Value is the size for gridtrack.Fixed, or the flex factor for gridtrack.Fr. Unused for other kinds.
+	Value float32
+	Kind  gridtrack.Enum
+}
+
+// GridTrack defines how a row or column of a GridLayout is sized. The zero value is an auto track.
+type GridTrack struct {
+	// Min is the sizing function used to determine the smallest size the track may have. A gridtrack.Fr value is
+	// treated as gridtrack.Auto here.
+	Min GridTrackSize
+	// Max is the sizing function used to determine the size the track grows to when space is available.
+	Max GridTrackSize
+}
+
+// FixedGridTrack returns a track with a fixed size.
+func FixedGridTrack(size float32) GridTrack {
+	s := GridTrackSize{Value: size, Kind: gridtrack.Fixed}
+	return GridTrack{Min: s, Max: s}
+}
+
+// AutoGridTrack returns a track sized to its content, ranging from the largest minimum size to the largest preferred
+// size of the children within it. Auto tracks also stretch to fill any space left over when there are no fr tracks.
+func AutoGridTrack() GridTrack {
+	return GridTrack{}
+}
+
+// MinContentGridTrack returns a track sized to the largest minimum size of the children within it.
+func MinContentGridTrack() GridTrack {
+	s := GridTrackSize{Kind: gridtrack.MinContent}
+	return GridTrack{Min: s, Max: s}
+}
+
+// FrGridTrack returns a track that takes the given share of the space left over after the other tracks have been
+// sized, but which is never smaller than the largest minimum size of the children within it.
+func FrGridTrack(fr float32) GridTrack {
+	return GridTrack{Max: GridTrackSize{Value: fr, Kind: gridtrack.Fr}}
+}
+
+// MinMaxGridTrack returns a track whose size ranges between the two sizing functions.
+func MinMaxGridTrack(minimum, maximum GridTrackSize) GridTrack {
+	return GridTrack{Min: minimum, Max: maximum}
+}
+
+// GridLayout is a Layout modelled on CSS Grid. Rows and columns are defined by tracks, optionally with named areas
+// spanning them, and children are placed into cells either explicitly, by line number or area name, or automatically
+// into the next free cell. Children may be given a *GridLayoutData as their layout data to control their placement and
+// alignment; children without one are placed automatically into a single cell.
+type GridLayout struct {
+	// Columns defines the explicit columns of the grid.
+	Columns []GridTrack
+	// Rows defines the explicit rows of the grid.
+	Rows []GridTrack
+	// AutoColumns defines any columns created beyond those in Columns in order to hold the children.
+	AutoColumns GridTrack
+	// AutoRows defines any rows created beyond those in Rows in order to hold the children.
+	AutoRows GridTrack
+	// Areas names regions of the grid. Each entry describes a row, as a whitespace-separated list of area names, one
+	// per column. A name of "." leaves the cell unnamed. Areas must be rectangular; those that are not are ignored.
+	// For example: []string{"header header", "nav main", "footer footer"}.
+	Areas []string
+	// HAlign is the horizontal alignment of children within their cells, unless overridden by their layout data.
+	HAlign align.Enum
+	// VAlign is the vertical alignment of children within their cells, unless overridden by their layout data.
+	VAlign align.Enum
+	// HSpacing is the gap placed between columns.
+	HSpacing float32
+	// VSpacing is the gap placed between rows.
+	VSpacing float32
+}
+
+// GridLayoutData is used to control how a child is placed by a GridLayout.
+type GridLayoutData struct {
+	// Area is the name of the template area to place the child into. When set to the name of a valid area, Column,
+	// Row and the spans are ignored.
+	Area string
+	// Column is the grid line the child starts at, with 1 being the left edge of the grid. Values less than 1 cause
+	// the column to be chosen automatically.
+	Column int
+	// Row is the grid line the child starts at, with 1 being the top edge of the grid. Values less than 1 cause the row
+	// to be chosen automatically.
+	Row int
+	// ColumnSpan is the number of columns the child spans. Values less than 1 are treated as 1.
+	ColumnSpan int
+	// RowSpan is the number of rows the child spans. Values less than 1 are treated as 1.
+	RowSpan int
+	// HAlign overrides the GridLayout's HAlign value for this child.
+	HAlign selfalign.Enum
+	// VAlign overrides the GridLayout's VAlign value for this child.
+	VAlign selfalign.Enum
+}
+
+type gridArea struct {
+	col     int
+	row     int
+	colSpan int
+	rowSpan int
+}
+
+type gridItem struct {
+	panel    *Panel
+	data     GridLayoutData
+	minSize  Size
+	prefSize Size
+	maxSize  Size
+	area     gridArea
+}
+
+type gridCell struct {
+	col int
+	row int
+}
+
+// LayoutSizes implements Layout.
+func (g *GridLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, maxSize Size) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	items, cols, rows := g.placeItems(target)
+	availWidth := float32(math.MaxFloat32)
+	if hint.Width >= 1 {
+		availWidth = max(hint.Width-insets.Width(), 0)
+	}
+	colMin, colSizes := g.sizeTracks(g.tracks(g.Columns, g.AutoColumns, cols), items, true, availWidth, nil)
+	rowMin, rowSizes := g.sizeTracks(g.tracks(g.Rows, g.AutoRows, rows), items, false, math.MaxFloat32, colSizes)
+	minSize = Size{Width: g.extent(colMin, g.HSpacing), Height: g.extent(rowMin, g.VSpacing)}
+	prefSize = Size{Width: g.extent(colSizes, g.HSpacing), Height: g.extent(rowSizes, g.VSpacing)}
+	minSize.AddInsets(insets)
+	prefSize.AddInsets(insets)
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (g *GridLayout) PerformLayout(target *Panel) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	size := target.ContentRect(true).Size
+	size.Width = max(size.Width-insets.Width(), 0)
+	size.Height = max(size.Height-insets.Height(), 0)
+	items, cols, rows := g.placeItems(target)
+	_, colSizes := g.sizeTracks(g.tracks(g.Columns, g.AutoColumns, cols), items, true, size.Width, nil)
+	_, rowSizes := g.sizeTracks(g.tracks(g.Rows, g.AutoRows, rows), items, false, size.Height, colSizes)
+	colPos := g.positions(colSizes, insets.Left, g.HSpacing)
+	rowPos := g.positions(rowSizes, insets.Top, g.VSpacing)
+	for _, item := range items {
+		cell := Rect{
+			Point: Point{X: colPos[item.area.col], Y: rowPos[item.area.row]},
+			Size: Size{
+				Width:  g.spanSize(colSizes, item.area.col, item.area.colSpan, g.HSpacing),
+				Height: g.spanSize(rowSizes, item.area.row, item.area.rowSpan, g.VSpacing),
+			},
+		}
+		r := cell
+		hAlign := resolveSelfAlign(item.data.HAlign, g.HAlign)
+		if hAlign == align.Fill {
+			r.Width = max(min(cell.Width, item.maxSize.Width), item.minSize.Width)
+		} else {
+			r.Width = min(item.prefSize.Width, cell.Width)
+		}
+		minSize, prefSize, maxSize := item.panel.Sizes(Size{Width: r.Width})
+		vAlign := resolveSelfAlign(item.data.VAlign, g.VAlign)
+		if vAlign == align.Fill {
+			r.Height = max(min(cell.Height, maxSize.Height), minSize.Height)
+		} else {
+			r.Height = min(prefSize.Height, cell.Height)
+		}
+		switch hAlign {
+		case align.Middle:
+			r.X += (cell.Width - r.Width) / 2
+		case align.End:
+			r.X += cell.Width - r.Width
+		default:
+		}
+		switch vAlign {
+		case align.Middle:
+			r.Y += (cell.Height - r.Height) / 2
+		case align.End:
+			r.Y += cell.Height - r.Height
+		default:
+		}
+		item.panel.SetFrameRect(r)
+	}
+}
+
+// placeItems assigns each child to a region of the grid and returns them, along with the number of columns and rows
+// needed to hold them.
+func (g *GridLayout) placeItems(target *Panel) (items []*gridItem, cols, rows int) {
+	areas, areaCols := g.parseAreas()
+	cols = max(len(g.Columns), areaCols)
+	rows = max(len(g.Rows), len(g.Areas))
+	children := target.Children()
+	items = make([]*gridItem, 0, len(children))
+	occupied := make(map[gridCell]bool)
+	occupy := func(item *gridItem) {
+		for r := item.area.row; r < item.area.row+item.area.rowSpan; r++ {
+			for c := item.area.col; c < item.area.col+item.area.colSpan; c++ {
+				occupied[gridCell{col: c, row: r}] = true
+			}
+		}
+		cols = max(cols, item.area.col+item.area.colSpan)
+		rows = max(rows, item.area.row+item.area.rowSpan)
+	}
+	var pending []*gridItem
+	for _, child := range children {
+		item := &gridItem{panel: child}
+		if data, ok := child.LayoutData().(*GridLayoutData); ok && data != nil {
+			item.data = *data
+		}
+		item.minSize, item.prefSize, item.maxSize = child.Sizes(Size{})
+		items = append(items, item)
+		if area, ok := areas[item.data.Area]; ok {
+			item.area = area
+			occupy(item)
+			continue
+		}
+		item.area.colSpan = max(item.data.ColumnSpan, 1)
+		item.area.rowSpan = max(item.data.RowSpan, 1)
+		if item.data.Column > 0 && item.data.Row > 0 {
+			item.area.col = item.data.Column - 1
+			item.area.row = item.data.Row - 1
+			occupy(item)
+			continue
+		}
+		pending = append(pending, item)
+	}
+	fits := func(item *gridItem, col, row int) bool {
+		for r := row; r < row+item.area.rowSpan; r++ {
+			for c := col; c < col+item.area.colSpan; c++ {
+				if occupied[gridCell{col: c, row: r}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	var cursor gridCell
+	for _, item := range pending {
+		switch {
+		case item.data.Row > 0:
+			item.area.row = item.data.Row - 1
+			item.area.col = 0
+			for !fits(item, item.area.col, item.area.row) {
+				item.area.col++
+			}
+		case item.data.Column > 0:
+			item.area.col = item.data.Column - 1
+			item.area.row = 0
+			for !fits(item, item.area.col, item.area.row) {
+				item.area.row++
+			}
+		default:
+			// Place row by row, never moving backwards, wrapping to the next row when the item would extend past the
+			// last column
+			width := max(cols, item.area.colSpan)
+			for {
+				if cursor.col+item.area.colSpan > width {
+					cursor.col = 0
+					cursor.row++
+					continue
+				}
+				if fits(item, cursor.col, cursor.row) {
+					break
+				}
+				cursor.col++
+			}
+			item.area.col = cursor.col
+			item.area.row = cursor.row
+			cursor.col += item.area.colSpan
+		}
+		occupy(item)
+	}
+	return items, cols, rows
+}
+
+// parseAreas returns the valid named areas, along with the number of columns the area definitions use.
+func (g *GridLayout) parseAreas() (areas map[string]gridArea, cols int) {
+	areas = make(map[string]gridArea)
+	cells := make(map[string]int)
+	for row, line := range g.Areas {
+		names := strings.Fields(line)
+		cols = max(cols, len(names))
+		for col, name := range names {
+			if strings.Trim(name, ".") == "" {
+				continue
+			}
+			cells[name]++
+			area, ok := areas[name]
+			if !ok {
+				areas[name] = gridArea{col: col, row: row, colSpan: 1, rowSpan: 1}
+				continue
+			}
+			right := max(area.col+area.colSpan, col+1)
+			area.col = min(area.col, col)
+			area.colSpan = right - area.col
+			area.rowSpan = row - area.row + 1
+			areas[name] = area
+		}
+	}
+	for name, area := range areas {
+		if cells[name] != area.colSpan*area.rowSpan {
+			delete(areas, name)
+		}
+	}
+	return areas, cols
+}
+
+func (g *GridLayout) tracks(defined []GridTrack, implicit GridTrack, count int) []GridTrack {
+	tracks := make([]GridTrack, count)
+	for i := range tracks {
+		if i < len(defined) {
+			tracks[i] = defined[i]
+		} else {
+			tracks[i] = implicit
+		}
+	}
+	return tracks
+}
+
+// sizeTracks returns the minimum and final sizes of the tracks. Pass math.MaxFloat32 for avail to size the tracks
+// without any constraint on the space available. When sizing rows, colSizes must contain the final column sizes, so
+// that the height of children whose height depends upon their width can be determined.
+func (g *GridLayout) sizeTracks(tracks []GridTrack, items []*gridItem, horizontal bool, avail float32, colSizes []float32) (minSizes, sizes []float32) {
+	gap := g.VSpacing
+	if horizontal {
+		gap = g.HSpacing
+	}
+	base := make([]float32, len(tracks))
+	limit := make([]float32, len(tracks))
+	canGrowBase := make([]bool, len(tracks))
+	canGrowLimit := make([]bool, len(tracks))
+	for i, track := range tracks {
+		if track.Min.Kind == gridtrack.Fixed {
+			base[i] = track.Min.Value
+		} else {
+			canGrowBase[i] = true
+		}
+		if track.Max.Kind == gridtrack.Fixed {
+			limit[i] = track.Max.Value
+		} else {
+			canGrowLimit[i] = true
+		}
+	}
+
+	// Children spanning a single track are considered first, so that those spanning multiple tracks only add to the
+	// tracks when the single-span children haven't already made them large enough
+	for _, multiple := range []bool{false, true} {
+		for _, item := range items {
+			start, span := item.area.row, item.area.rowSpan
+			if horizontal {
+				start, span = item.area.col, item.area.colSpan
+			}
+			if (span > 1) != multiple {
+				continue
+			}
+			var minContent, prefContent float32
+			if horizontal {
+				minContent = item.minSize.Width
+				prefContent = item.prefSize.Width
+			} else {
+				minSize, prefSize, _ := item.panel.Sizes(Size{Width: g.spanSize(colSizes, item.area.col, item.area.colSpan, g.HSpacing)})
+				minContent = minSize.Height
+				prefContent = prefSize.Height
+			}
+			if !multiple {
+				if canGrowBase[start] {
+					base[start] = max(base[start], minContent)
+				}
+				switch tracks[start].Max.Kind {
+				case gridtrack.MinContent:
+					limit[start] = max(limit[start], minContent)
+				case gridtrack.Auto, gridtrack.Fr:
+					limit[start] = max(limit[start], prefContent)
+				default:
+				}
+				continue
+			}
+			growSpan(base, canGrowBase, start, span, minContent, gap)
+			growSpan(limit, canGrowLimit, start, span, prefContent, gap)
+		}
+	}
+	for i := range limit {
+		limit[i] = max(limit[i], base[i])
+	}
+	minSizes = base
+	sizes = make([]float32, len(tracks))
+	copy(sizes, base)
+	if avail == math.MaxFloat32 {
+		// Unconstrained, so give each track the size it would prefer, keeping fr tracks in proportion to each other
+		var frUnit float32
+		for i, track := range tracks {
+			if track.Max.Kind == gridtrack.Fr {
+				if track.Max.Value > 0 {
+					frUnit = max(frUnit, limit[i]/track.Max.Value)
+				}
+			} else {
+				sizes[i] = limit[i]
+			}
+		}
+		for i, track := range tracks {
+			if track.Max.Kind == gridtrack.Fr {
+				sizes[i] = max(sizes[i], frUnit*track.Max.Value)
+			}
+		}
+		return minSizes, sizes
+	}
+	free := avail - g.extent(sizes, gap)
+
+	// Grow the non-fr tracks toward their limits, sharing the free space equally
+	for free > 0 {
+		growing := 0
+		share := float32(math.MaxFloat32)
+		for i, track := range tracks {
+			if track.Max.Kind != gridtrack.Fr && sizes[i] < limit[i] {
+				growing++
+				share = min(share, limit[i]-sizes[i])
+			}
+		}
+		if growing == 0 {
+			break
+		}
+		share = min(share, free/float32(growing))
+		for i, track := range tracks {
+			if track.Max.Kind != gridtrack.Fr && sizes[i] < limit[i] {
+				sizes[i] += share
+				free -= share
+			}
+		}
+	}
+
+	// Give whatever is left to the fr tracks. Tracks whose minimum is larger than their share are removed from the
+	// flexible set and the share recalculated, until the share is stable.
+	hasFr := false
+	flexible := make([]bool, len(tracks))
+	for i, track := range tracks {
+		if track.Max.Kind == gridtrack.Fr && track.Max.Value > 0 {
+			flexible[i] = true
+			hasFr = true
+		}
+	}
+	if hasFr {
+		for {
+			leftover := avail - gap*float32(max(len(tracks)-1, 0))
+			var factors float32
+			for i, track := range tracks {
+				if flexible[i] {
+					factors += track.Max.Value
+				} else {
+					leftover -= sizes[i]
+				}
+			}
+			if factors == 0 {
+				break
+			}
+			frUnit := max(leftover, 0) / max(factors, 1)
+			stable := true
+			for i, track := range tracks {
+				if flexible[i] && base[i] > frUnit*track.Max.Value {
+					flexible[i] = false
+					stable = false
+				}
+			}
+			if stable {
+				for i, track := range tracks {
+					if flexible[i] {
+						sizes[i] = frUnit * track.Max.Value
+					}
+				}
+				break
+			}
+		}
+		return minSizes, sizes
+	}
+
+	// With no fr tracks, stretch the auto tracks to fill any remaining space
+	if free > 0 {
+		var autoCount int
+		for _, track := range tracks {
+			if track.Max.Kind == gridtrack.Auto {
+				autoCount++
+			}
+		}
+		if autoCount > 0 {
+			share := free / float32(autoCount)
+			for i, track := range tracks {
+				if track.Max.Kind == gridtrack.Auto {
+					sizes[i] += share
+				}
+			}
+		}
+	}
+	return minSizes, sizes
+}
+
+// growSpan increases the sizes of the eligible tracks within the span equally, such that the span is at least as
+// large as needed.
+func growSpan(sizes []float32, eligible []bool, start, span int, needed, gap float32) {
+	have := gap * float32(span-1)
+	count := 0
+	for i := start; i < start+span; i++ {
+		have += sizes[i]
+		if eligible[i] {
+			count++
+		}
+	}
+	if needed > have && count > 0 {
+		extra := (needed - have) / float32(count)
+		for i := start; i < start+span; i++ {
+			if eligible[i] {
+				sizes[i] += extra
+			}
+		}
+	}
+}
+
+func (g *GridLayout) extent(sizes []float32, gap float32) float32 {
+	if len(sizes) == 0 {
+		return 0
+	}
+	total := gap * float32(len(sizes)-1)
+	for _, size := range sizes {
+		total += size
+	}
+	return total
+}
+
+func (g *GridLayout) positions(sizes []float32, start, gap float32) []float32 {
+	positions := make([]float32, len(sizes))
+	for i, size := range sizes {
+		positions[i] = start
+		start += size + gap
+	}
+	return positions
+}
+
+func (g *GridLayout) spanSize(sizes []float32, start, span int, gap float32) float32 {
+	if start+span > len(sizes) {
+		return 0
+	}
+	return g.extent(sizes[start:start+span], gap)
+}
@@ -0,0 +1,46 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/selfalign"
+)
+
+func TestGridLayout(t *testing.T) {
+	parent := unison.NewPanel()
+	parent.SetLayout(&unison.GridLayout{
+		Columns:  []unison.GridTrack{unison.FixedGridTrack(100), unison.FrGridTrack(1), unison.FrGridTrack(2)},
+		Areas:    []string{"head head head", "side main main"},
+		HAlign:   align.Fill,
+		HSpacing: 10,
+	})
+	head := newLayoutChild(parent, &unison.GridLayoutData{Area: "head"})
+	side := newLayoutChild(parent, &unison.GridLayoutData{Area: "side", HAlign: selfalign.End})
+	main := newLayoutChild(parent, &unison.GridLayoutData{Area: "main"})
+	auto := newLayoutChild(parent, nil)
+	byLine := newLayoutChild(parent, &unison.GridLayoutData{Column: 2, Row: 3, ColumnSpan: 2})
+
+	// Unconstrained, the fr columns keep their proportions while fitting the children spanning them
+	_, pref, _ := parent.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 180, Height: 60}, pref)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 420, Height: 60}})
+	parent.ValidateLayout()
+	check.Equal(t, unison.Rect{Size: unison.Size{Width: 420, Height: 20}}, head.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 50, Y: 20}, Size: unison.Size{Width: 50, Height: 20}}, side.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 110, Y: 20}, Size: unison.Size{Width: 310, Height: 20}}, main.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{Y: 40}, Size: unison.Size{Width: 100, Height: 20}}, auto.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 110, Y: 40}, Size: unison.Size{Width: 310, Height: 20}}, byLine.FrameRect())
+}
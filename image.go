@@ -109,9 +109,18 @@ func NewImageFromDrawing(width, height, ppi int, draw func(*Canvas)) (*Image, er
 	c.Restore()
 	c.Flush()
 	defer s.dispose()
-	img := skia.SurfaceMakeImageSnapshot(s.surface)
-	width = skia.ImageGetWidth(img)
-	height = skia.ImageGetHeight(img)
+	return imageFromSurface(s.surface, 1)
+}
+
+// imageFromSurface reads the pixels of the surface back into a new image with the given scale.
+func imageFromSurface(s skia.Surface, scale float32) (*Image, error) {
+	img := skia.SurfaceMakeImageSnapshot(s)
+	if img == nil {
+		return nil, errs.New("unable to snapshot surface")
+	}
+	defer skia.ImageUnref(img)
+	width := skia.ImageGetWidth(img)
+	height := skia.ImageGetHeight(img)
 	pixels := make([]byte, width*height*4)
 	if !skia.ImageReadPixels(img, &skia.ImageInfo{
 		Colorspace: skiaColorspace,
@@ -122,8 +131,7 @@ func NewImageFromDrawing(width, height, ppi int, draw func(*Canvas)) (*Image, er
 	}, pixels, width*4, 0, 0, skia.ImageCachingHintDisallow) {
 		return nil, errs.New("unable to read raw pixels from image")
 	}
-	skia.ImageUnref(img)
-	return NewImageFromPixels(width, height, pixels, 1)
+	return NewImageFromPixels(width, height, pixels, scale)
 }
 
 func newImage(img skia.Image, scale float32, hash uint64) (*Image, error) {
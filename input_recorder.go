@@ -0,0 +1,378 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/toolbox/xio/fs"
+)
+
+// InputEventType identifies the kind of input an InputEvent holds.
+type InputEventType string
+
+// Possible values for InputEventType.
+const (
+	MouseDownInputEvent   InputEventType = "mouse_down"
+	MouseUpInputEvent     InputEventType = "mouse_up"
+	MouseMoveInputEvent   InputEventType = "mouse_move"
+	MouseEnterInputEvent  InputEventType = "mouse_enter"
+	MouseExitInputEvent   InputEventType = "mouse_exit"
+	MouseWheelInputEvent  InputEventType = "mouse_wheel"
+	KeyDownInputEvent     InputEventType = "key_down"
+	KeyUpInputEvent       InputEventType = "key_up"
+	RuneTypedInputEvent   InputEventType = "rune_typed"
+	FileDropInputEvent    InputEventType = "file_drop"
+	FocusGainedInputEvent InputEventType = "focus_gained"
+	FocusLostInputEvent   InputEventType = "focus_lost"
+)
+
+const inputRecordingFileType = "unison.input_recording"
+
+// InputEvent holds a single recorded input event. Locations are relative to the panel identified by RefKey, or to the
+// window if RefKey is empty.
+type InputEvent struct {
+	Type       InputEventType `json:"type"`
+	Time       time.Duration  `json:"time"`
+	RefKey     string         `json:"ref_key,omitempty"`
+	Where      Point          `json:"where"`
+	Delta      Point          `json:"delta"`
+	Button     int            `json:"button,omitempty"`
+	ClickCount int            `json:"click_count,omitempty"`
+	Modifiers  Modifiers      `json:"modifiers,omitempty"`
+	KeyCode    KeyCode        `json:"key_code,omitempty"`
+	Repeat     bool           `json:"repeat,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Files      []string       `json:"files,omitempty"`
+}
+
+// InputRecording holds a sequence of input events captured by an InputRecorder.
+type InputRecording struct {
+	Type   string        `json:"type"`
+	Events []*InputEvent `json:"events"`
+}
+
+// LoadInputRecording loads an InputRecording from a JSON file.
+func LoadInputRecording(filePath string) (*InputRecording, error) {
+	var r InputRecording
+	if err := fs.LoadJSON(filePath, &r); err != nil {
+		return nil, err
+	}
+	if r.Type != inputRecordingFileType {
+		return nil, errs.Newf("%s is not an input recording", filePath)
+	}
+	return &r, nil
+}
+
+// Save the recording as a JSON file.
+func (r *InputRecording) Save(filePath string) error {
+	r.Type = inputRecordingFileType
+	return fs.SaveJSON(filePath, r, true)
+}
+
+// InputRecorder captures the input events delivered to a window. Events are recorded relative to the closest panel
+// under the mouse that has a RefKey which uniquely identifies it within the window, so that the recording can later be
+// replayed even if the window's geometry has changed. Only one recorder may be active on a window at a time.
+type InputRecorder struct {
+	window     *Window
+	start      time.Time
+	events     []*InputEvent
+	dragRefKey string
+}
+
+// NewInputRecorder creates a new recorder for the window.
+func NewInputRecorder(wnd *Window) *InputRecorder {
+	return &InputRecorder{window: wnd}
+}
+
+// Start recording, discarding any previously recorded events.
+func (r *InputRecorder) Start() {
+	r.events = nil
+	r.dragRefKey = ""
+	r.start = time.Now()
+	r.window.recorder = r
+}
+
+// Stop recording.
+func (r *InputRecorder) Stop() {
+	if r.window.recorder == r {
+		r.window.recorder = nil
+	}
+}
+
+// Recording returns true if this recorder is currently capturing events.
+func (r *InputRecorder) Recording() bool {
+	return r.window.recorder == r
+}
+
+// InputRecording returns the events captured so far.
+func (r *InputRecorder) InputRecording() *InputRecording {
+	events := make([]*InputEvent, len(r.events))
+	copy(events, r.events)
+	return &InputRecording{
+		Type:   inputRecordingFileType,
+		Events: events,
+	}
+}
+
+func (r *InputRecorder) add(event *InputEvent) {
+	event.Time = time.Since(r.start)
+	r.events = append(r.events, event)
+}
+
+func (r *InputRecorder) addAt(event *InputEvent, where Point) {
+	event.RefKey, event.Where = r.locate(where)
+	r.add(event)
+}
+
+func (r *InputRecorder) locate(where Point) (refKey string, pt Point) {
+	panel := r.window.root.PanelAt(where)
+	for panel != nil && (panel.RefKey == "" || !r.window.root.FindRefKey(panel.RefKey).Is(panel)) {
+		panel = panel.parent
+	}
+	if panel == nil {
+		return "", where
+	}
+	return panel.RefKey, panel.PointFromRoot(where)
+}
+
+func (r *InputRecorder) mouseButton(where Point, button, clickCount int, pressed bool, mod Modifiers) {
+	event := &InputEvent{
+		Button:     button,
+		ClickCount: clickCount,
+		Modifiers:  mod,
+	}
+	if pressed {
+		event.Type = MouseDownInputEvent
+		r.addAt(event, where)
+		r.dragRefKey = event.RefKey
+	} else {
+		event.Type = MouseUpInputEvent
+		r.addDragRelative(event, where)
+		r.dragRefKey = ""
+	}
+}
+
+func (r *InputRecorder) mouseMove(where Point, mod Modifiers, dragging bool) {
+	event := &InputEvent{
+		Type:      MouseMoveInputEvent,
+		Modifiers: mod,
+	}
+	if dragging {
+		r.addDragRelative(event, where)
+	} else {
+		r.addAt(event, where)
+	}
+}
+
+// addDragRelative records the event relative to the panel the mouse was pressed in, since drags and releases are
+// delivered there even when the mouse has moved outside of it.
+func (r *InputRecorder) addDragRelative(event *InputEvent, where Point) {
+	if r.dragRefKey != "" {
+		if panel := r.window.root.FindRefKey(r.dragRefKey); panel != nil {
+			event.RefKey = r.dragRefKey
+			event.Where = panel.PointFromRoot(where)
+			r.add(event)
+			return
+		}
+	}
+	event.Where = where
+	r.add(event)
+}
+
+func (r *InputRecorder) mouseEnterOrExit(entered bool, where Point, mod Modifiers) {
+	if entered {
+		r.addAt(&InputEvent{Type: MouseEnterInputEvent, Modifiers: mod}, where)
+	} else {
+		r.add(&InputEvent{Type: MouseExitInputEvent})
+	}
+}
+
+func (r *InputRecorder) mouseWheel(where, delta Point, mod Modifiers) {
+	r.addAt(&InputEvent{
+		Type:      MouseWheelInputEvent,
+		Delta:     delta,
+		Modifiers: mod,
+	}, where)
+}
+
+func (r *InputRecorder) key(keyCode KeyCode, mod Modifiers, pressed, repeat bool) {
+	event := &InputEvent{
+		Type:      KeyUpInputEvent,
+		KeyCode:   keyCode,
+		Modifiers: mod,
+		Repeat:    repeat,
+	}
+	if pressed {
+		event.Type = KeyDownInputEvent
+	}
+	r.add(event)
+}
+
+func (r *InputRecorder) runeTyped(ch rune) {
+	r.add(&InputEvent{
+		Type: RuneTypedInputEvent,
+		Text: string(ch),
+	})
+}
+
+func (r *InputRecorder) fileDrop(where Point, files []string) {
+	list := make([]string, len(files))
+	copy(list, files)
+	r.addAt(&InputEvent{
+		Type:  FileDropInputEvent,
+		Files: list,
+	}, where)
+}
+
+func (r *InputRecorder) focusChange(focused bool) {
+	if focused {
+		r.add(&InputEvent{Type: FocusGainedInputEvent})
+	} else {
+		r.add(&InputEvent{Type: FocusLostInputEvent})
+	}
+}
+
+// InputPlayer replays an InputRecording against a window. Events are delivered through the same paths as the Inject*
+// methods on Window.
+type InputPlayer struct {
+	window    *Window
+	recording *InputRecording
+	// Speed is the multiplier applied to the recorded timing when using Play(). Values less than or equal to zero are
+	// treated as 1.
+	Speed float64
+	// DoneCallback, if set, is called when asynchronous playback started with Play() finishes or fails.
+	DoneCallback func(err error)
+	start        time.Time
+	next         int
+	sequence     int
+	playing      bool
+}
+
+// NewInputPlayer creates a new player for the recording.
+func NewInputPlayer(wnd *Window, recording *InputRecording) *InputPlayer {
+	return &InputPlayer{
+		window:    wnd,
+		recording: recording,
+		Speed:     1,
+	}
+}
+
+// PlayAll dispatches every event in the recording immediately, ignoring the recorded timing. Returns the first error
+// encountered, such as a recorded RefKey that cannot be found in the window. Must be called on the UI thread.
+func (p *InputPlayer) PlayAll() error {
+	for _, event := range p.recording.Events {
+		if err := p.dispatch(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Play starts dispatching events on the UI thread, honoring the recorded timing adjusted by Speed. Returns immediately.
+func (p *InputPlayer) Play() {
+	p.Stop()
+	p.playing = true
+	p.next = 0
+	p.start = time.Now()
+	p.schedule()
+}
+
+// Playing returns true if asynchronous playback is in progress.
+func (p *InputPlayer) Playing() bool {
+	return p.playing
+}
+
+// Stop any asynchronous playback that is in progress.
+func (p *InputPlayer) Stop() {
+	p.sequence++
+	p.playing = false
+}
+
+func (p *InputPlayer) schedule() {
+	if p.next >= len(p.recording.Events) {
+		p.finish(nil)
+		return
+	}
+	speed := p.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	sequence := p.sequence
+	delay := time.Duration(float64(p.recording.Events[p.next].Time)/speed) - time.Since(p.start)
+	InvokeTaskAfter(func() {
+		if !p.playing || p.sequence != sequence {
+			return
+		}
+		event := p.recording.Events[p.next]
+		p.next++
+		if err := p.dispatch(event); err != nil {
+			p.finish(err)
+			return
+		}
+		p.schedule()
+	}, max(delay, 0))
+}
+
+func (p *InputPlayer) finish(err error) {
+	p.playing = false
+	if p.DoneCallback != nil {
+		p.DoneCallback(err)
+	}
+}
+
+func (p *InputPlayer) dispatch(event *InputEvent) error {
+	w := p.window
+	if !w.IsValid() {
+		return errs.New("window is no longer valid")
+	}
+	where := event.Where
+	if event.RefKey != "" {
+		panel := w.root.FindRefKey(event.RefKey)
+		if panel == nil {
+			return errs.Newf("unable to locate panel with ref key %q", event.RefKey)
+		}
+		where = panel.PointToRoot(where)
+	}
+	switch event.Type {
+	case MouseDownInputEvent:
+		if event.ClickCount <= 1 {
+			// Ensure a fresh click sequence, since playback timing may not match the original
+			w.lastButtonTime = time.Time{}
+		}
+		w.InjectMouseDown(where, event.Button, event.Modifiers)
+	case MouseUpInputEvent:
+		w.InjectMouseUp(where, event.Button, event.Modifiers)
+	case MouseMoveInputEvent:
+		w.InjectMouseMove(where, event.Modifiers)
+	case MouseEnterInputEvent:
+		w.InjectMouseEnter(where, event.Modifiers)
+	case MouseExitInputEvent:
+		w.InjectMouseExit()
+	case MouseWheelInputEvent:
+		w.InjectMouseWheel(where, event.Delta, event.Modifiers)
+	case KeyDownInputEvent:
+		w.InjectKeyDown(event.KeyCode, event.Modifiers, event.Repeat)
+	case KeyUpInputEvent:
+		w.InjectKeyUp(event.KeyCode, event.Modifiers)
+	case RuneTypedInputEvent:
+		w.InjectText(event.Text)
+	case FileDropInputEvent:
+		w.InjectFileDrop(where, event.Files)
+	case FocusGainedInputEvent:
+		w.InjectFocusChange(true)
+	case FocusLostInputEvent:
+		w.InjectFocusChange(false)
+	default:
+		return errs.Newf("unknown input event type: %s", event.Type)
+	}
+	return nil
+}
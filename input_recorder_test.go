@@ -0,0 +1,114 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+// newRecorderWindow creates a window holding a panel with the "target" ref key at the given location. The panel logs
+// the input it receives, in its own coordinates.
+func newRecorderWindow(t *testing.T, at unison.Point) (wnd *unison.Window, log *[]string) {
+	t.Helper()
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 200, Height: 150}, 1))
+	check.NoError(t, err)
+	var events []string
+	content := unison.NewPanel()
+	target := unison.NewPanel()
+	target.RefKey = "target"
+	target.SetFocusable(true)
+	target.SetFrameRect(unison.Rect{Point: at, Size: unison.Size{Width: 40, Height: 30}})
+	target.MouseDownCallback = func(where unison.Point, _, clickCount int, _ unison.Modifiers) bool {
+		events = append(events, fmt.Sprintf("down %v,%v x%d", where.X, where.Y, clickCount))
+		return true
+	}
+	target.MouseDragCallback = func(where unison.Point, _ int, _ unison.Modifiers) bool {
+		events = append(events, fmt.Sprintf("drag %v,%v", where.X, where.Y))
+		return true
+	}
+	target.MouseUpCallback = func(where unison.Point, _ int, _ unison.Modifiers) bool {
+		events = append(events, fmt.Sprintf("up %v,%v", where.X, where.Y))
+		return true
+	}
+	target.KeyDownCallback = func(keyCode unison.KeyCode, _ unison.Modifiers, _ bool) bool {
+		events = append(events, fmt.Sprintf("key %v", keyCode))
+		return true
+	}
+	target.RuneTypedCallback = func(ch rune) bool {
+		events = append(events, fmt.Sprintf("rune %c", ch))
+		return true
+	}
+	content.AddChild(target)
+	wnd.SetContent(content)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+	target.RequestFocus()
+	return wnd, &events
+}
+
+func TestInputRecorder(t *testing.T) {
+	wnd, recorded := newRecorderWindow(t, unison.Point{X: 10, Y: 10})
+	defer wnd.Dispose()
+	recorder := unison.NewInputRecorder(wnd)
+	recorder.Start()
+	check.True(t, recorder.Recording())
+	wnd.InjectClick(unison.Point{X: 15, Y: 12}, unison.ButtonLeft, 0)
+	wnd.InjectClick(unison.Point{X: 15, Y: 12}, unison.ButtonLeft, 0)
+	wnd.InjectDrag(unison.Point{X: 20, Y: 20}, unison.Point{X: 80, Y: 60}, unison.ButtonLeft, 2, 0)
+	wnd.InjectText("hi")
+	wnd.InjectKeyStroke(unison.KeyA, 0)
+	wnd.InjectClick(unison.Point{X: 150, Y: 100}, unison.ButtonLeft, 0)
+	recorder.Stop()
+	check.False(t, recorder.Recording())
+	check.Equal(t, []string{
+		"down 5,2 x1", "up 5,2", "down 5,2 x2", "up 5,2",
+		"down 10,10 x1", "drag 40,30", "drag 70,50", "up 70,50",
+		"rune h", "rune i", "key A",
+	}, *recorded)
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	check.NoError(t, recorder.InputRecording().Save(path))
+	recording, err := unison.LoadInputRecording(path)
+	check.NoError(t, err)
+	check.Equal(t, recorder.InputRecording().Events, recording.Events)
+	first := recording.Events[0]
+	check.Equal(t, "target", first.RefKey)
+	check.Equal(t, unison.Point{X: 5, Y: 2}, first.Where, "locations are relative to the ref key's panel")
+	last := recording.Events[len(recording.Events)-1]
+	check.Equal(t, "", last.RefKey)
+	check.Equal(t, unison.Point{X: 150, Y: 100}, last.Where, "locations outside of any ref key are window-relative")
+
+	moved, replayed := newRecorderWindow(t, unison.Point{X: 100, Y: 70})
+	defer moved.Dispose()
+	check.NoError(t, unison.NewInputPlayer(moved, recording).PlayAll())
+	check.Equal(t, *recorded, *replayed, "playback follows the panel to its new location")
+
+	empty, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 200, Height: 150}, 1))
+	check.NoError(t, err)
+	defer empty.Dispose()
+	check.Error(t, unison.NewInputPlayer(empty, recording).PlayAll(), "the target panel doesn't exist")
+
+	count := len(recorder.InputRecording().Events)
+	wnd.InjectClick(unison.Point{X: 15, Y: 12}, unison.ButtonLeft, 0)
+	check.Equal(t, count, len(recorder.InputRecording().Events), "nothing is recorded once stopped")
+}
+
+func TestLoadInputRecordingRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.json")
+	check.NoError(t, os.WriteFile(path, []byte(`{"type":"something_else","events":[]}`), 0o640))
+	_, err := unison.LoadInputRecording(path)
+	check.Error(t, err)
+}
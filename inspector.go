@@ -0,0 +1,572 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/behavior"
+	"github.com/ddkwork/unison/enums/paintstyle"
+	"github.com/google/uuid"
+)
+
+// Column indexes used by the inspector's tree view.
+const (
+	inspectorTypeColumn = iota
+	inspectorRefKeyColumn
+	inspectorEnabledColumn
+	inspectorFocusedColumn
+	inspectorMinColumn
+	inspectorPrefColumn
+	inspectorMaxColumn
+	inspectorClientDataColumn
+	inspectorColumnCount
+)
+
+// DefaultInspectorTheme holds the default InspectorTheme values for Inspectors. Modifying this data will not alter
+// existing Inspectors, but will alter any Inspectors created in the future.
+var DefaultInspectorTheme = InspectorTheme{
+	Font:         MonospacedFont,
+	FrameColor:   ARGB(0.35, 255, 0, 255),
+	TargetColor:  ARGB(1, 255, 0, 255),
+	InsetsColor:  ARGB(0.3, 255, 160, 0),
+	ContentColor: ARGB(0.2, 0, 160, 255),
+	InfoColor:    ARGB(0.85, 0, 0, 0),
+	OnInfoColor:  RGB(255, 255, 255),
+}
+
+// InspectorTheme holds theming data for an Inspector.
+type InspectorTheme struct {
+	Font         Font
+	FrameColor   Color
+	TargetColor  Color
+	InsetsColor  Color
+	ContentColor Color
+	InfoColor    Color
+	OnInfoColor  Color
+}
+
+// Inspector provides a debugging aid for examining the panel hierarchy of a window. While active, it draws the frame of
+// every visible panel over the window's content and, for the panel under the mouse (or the one selected in its tree
+// view), the border insets, content rect and FlexLayoutData. It also opens a separate window containing a tree view of
+// the panel hierarchy.
+type Inspector struct {
+	InspectorTheme
+	KeyBinding       KeyBinding
+	window           *Window
+	treeWindow       *Window
+	table            *Table[*inspectorRow]
+	selected         *Panel
+	closed           map[*Panel]bool
+	prevKeyDown      func(keyCode KeyCode, mod Modifiers, repeat bool) bool
+	prevMouseEnter   func(where Point, mod Modifiers) bool
+	prevMouseMove    func(where Point, mod Modifiers) bool
+	prevDrawOver     func(gc *Canvas, rect Rect)
+	prevWillClose    func()
+	active           bool
+	showTreeOnToggle bool
+}
+
+// DefaultInspectorKeyBinding returns the key binding used to toggle the inspector when no other is specified.
+func DefaultInspectorKeyBinding() KeyBinding {
+	return KeyBinding{KeyCode: KeyI, Modifiers: OSMenuCmdModifier() | ShiftModifier}
+}
+
+// InstallInspector installs an inspector on the window which is toggled on and off by the key binding. If the key
+// binding is empty, DefaultInspectorKeyBinding() is used.
+func InstallInspector(wnd *Window, keyBinding KeyBinding) *Inspector {
+	if keyBinding.KeyCode == 0 {
+		keyBinding = DefaultInspectorKeyBinding()
+	}
+	i := &Inspector{
+		InspectorTheme:   DefaultInspectorTheme,
+		KeyBinding:       keyBinding,
+		window:           wnd,
+		closed:           make(map[*Panel]bool),
+		showTreeOnToggle: !wnd.IsOffscreen(),
+		prevKeyDown:      wnd.KeyDownCallback,
+		prevMouseEnter:   wnd.MouseEnterCallback,
+		prevMouseMove:    wnd.MouseMoveCallback,
+		prevDrawOver:     wnd.root.DrawOverCallback,
+		prevWillClose:    wnd.WillCloseCallback,
+	}
+	wnd.KeyDownCallback = i.keyDown
+	wnd.MouseEnterCallback = i.mouseEnter
+	wnd.MouseMoveCallback = i.mouseMove
+	wnd.root.DrawOverCallback = i.drawOver
+	wnd.WillCloseCallback = i.willClose
+	return i
+}
+
+// Uninstall the inspector, restoring the window callbacks that were present when it was installed.
+func (i *Inspector) Uninstall() {
+	i.SetActive(false)
+	w := i.window
+	w.KeyDownCallback = i.prevKeyDown
+	w.MouseEnterCallback = i.prevMouseEnter
+	w.MouseMoveCallback = i.prevMouseMove
+	w.root.DrawOverCallback = i.prevDrawOver
+	w.WillCloseCallback = i.prevWillClose
+}
+
+// Active returns true if the inspector is currently active.
+func (i *Inspector) Active() bool {
+	return i.active
+}
+
+// SetActive sets whether the inspector is active. Activating it also opens the tree view window, unless the inspected
+// window is offscreen.
+func (i *Inspector) SetActive(active bool) {
+	if i.active == active {
+		return
+	}
+	i.active = active
+	if active {
+		if i.showTreeOnToggle {
+			i.ShowTree()
+		}
+	} else {
+		i.selected = nil
+		if i.treeWindow != nil {
+			i.treeWindow.Dispose()
+		}
+	}
+	i.window.MarkForRedraw()
+}
+
+// Toggle the active state of the inspector.
+func (i *Inspector) Toggle() {
+	i.SetActive(!i.active)
+}
+
+// Selected returns the panel currently selected in the tree view, if any.
+func (i *Inspector) Selected() *Panel {
+	return i.selected
+}
+
+// SetSelected sets the panel to highlight in place of the one under the mouse. Pass nil to return to tracking the
+// mouse.
+func (i *Inspector) SetSelected(panel *Panel) {
+	if i.selected != panel {
+		i.selected = panel
+		i.window.MarkForRedraw()
+	}
+}
+
+// ShowTree opens the tree view window, or brings it to the front if it is already open. The tree view window is
+// offscreen if the inspected window is.
+func (i *Inspector) ShowTree() {
+	if i.treeWindow != nil {
+		i.Refresh()
+		i.treeWindow.ToFront()
+		return
+	}
+	var options []WindowOption
+	if i.window.IsOffscreen() {
+		// Keep the tree view offscreen too, so that it can be used without a display
+		options = append(options, OffscreenWindowOption(Size{Width: 800, Height: 600}, 1))
+	}
+	wnd, err := NewWindow("Inspector: "+i.window.Title(), options...)
+	if err != nil {
+		errs.Log(errs.NewWithCause("unable to create inspector window", err))
+		return
+	}
+	i.treeWindow = wnd
+	wnd.WillCloseCallback = func() {
+		i.treeWindow = nil
+		i.table = nil
+	}
+	content := wnd.Content()
+	content.SetLayout(&FlexLayout{
+		Columns:  1,
+		HSpacing: StdHSpacing,
+		VSpacing: StdVSpacing,
+	})
+	content.SetBorder(NewEmptyBorder(StdInsets()))
+
+	refresh := NewButton()
+	refresh.Text = "Refresh"
+	refresh.ClickCallback = i.Refresh
+	content.AddChild(refresh)
+
+	i.table = NewTable[*inspectorRow](&SimpleTableModel[*inspectorRow]{})
+	i.table.Columns = make([]ColumnInfo, inspectorColumnCount)
+	for j := range i.table.Columns {
+		i.table.Columns[j].ID = j
+		i.table.Columns[j].Minimum = 20
+		i.table.Columns[j].Maximum = 10000
+	}
+	i.table.SelectionChangedCallback = func() {
+		var panel *Panel
+		if rows := i.table.SelectedRows(false); len(rows) != 0 {
+			panel = rows[0].panel
+		}
+		i.SetSelected(panel)
+	}
+	header := NewTableHeader[*inspectorRow](i.table,
+		NewTableColumnHeader[*inspectorRow]("Type", ""),
+		NewTableColumnHeader[*inspectorRow]("Ref Key", ""),
+		NewTableColumnHeader[*inspectorRow]("Enabled", ""),
+		NewTableColumnHeader[*inspectorRow]("Focused", ""),
+		NewTableColumnHeader[*inspectorRow]("Min", ""),
+		NewTableColumnHeader[*inspectorRow]("Pref", ""),
+		NewTableColumnHeader[*inspectorRow]("Max", ""),
+		NewTableColumnHeader[*inspectorRow]("Client Data", ""),
+	)
+	header.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	content.AddChild(header)
+	scroller := NewScrollPanel()
+	scroller.SetContent(i.table, behavior.Fill, behavior.Fill)
+	scroller.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	content.AddChild(scroller)
+	i.Refresh()
+
+	frame := i.window.FrameRect()
+	wnd.SetFrameRect(Rect{Point: Point{X: frame.Right(), Y: frame.Y}, Size: Size{Width: 800, Height: frame.Height}})
+	wnd.ToFront()
+}
+
+// Refresh rebuilds the tree view from the current panel hierarchy.
+func (i *Inspector) Refresh() {
+	if i.table == nil {
+		return
+	}
+	root := i.newRow(i.window.root.AsPanel(), nil)
+	// Forget the collapsed state of panels that have left the window, so they aren't kept alive by it
+	for panel := range i.closed {
+		if panel.Window() != i.window {
+			delete(i.closed, panel)
+		}
+	}
+	i.table.SetRootRows([]*inspectorRow{root})
+	if i.selected != nil {
+		if row := root.find(i.selected); row != nil {
+			for p := row.parent; p != nil; p = p.parent {
+				p.SetOpen(true)
+			}
+			i.table.SyncToModel()
+			if index := i.table.RowToIndex(row); index != -1 {
+				i.table.SelectByIndex(index)
+				i.table.ScrollRowIntoView(index)
+			}
+		}
+	}
+	i.table.SizeColumnsToFit(true)
+}
+
+func (i *Inspector) newRow(panel *Panel, parent *inspectorRow) *inspectorRow {
+	row := &inspectorRow{
+		inspector: i,
+		parent:    parent,
+		panel:     panel,
+		id:        uuid.New(),
+	}
+	row.children = make([]*inspectorRow, 0, len(panel.children))
+	for _, child := range panel.children {
+		row.children = append(row.children, i.newRow(child, row))
+	}
+	return row
+}
+
+func (i *Inspector) keyDown(keyCode KeyCode, mod Modifiers, repeat bool) bool {
+	if !repeat && keyCode == i.KeyBinding.KeyCode && mod == i.KeyBinding.Modifiers {
+		i.Toggle()
+		return true
+	}
+	if i.prevKeyDown != nil {
+		return i.prevKeyDown(keyCode, mod, repeat)
+	}
+	return false
+}
+
+func (i *Inspector) mouseEnter(where Point, mod Modifiers) bool {
+	if i.active {
+		i.window.MarkForRedraw()
+	}
+	if i.prevMouseEnter != nil {
+		return i.prevMouseEnter(where, mod)
+	}
+	return false
+}
+
+func (i *Inspector) mouseMove(where Point, mod Modifiers) bool {
+	if i.active {
+		i.window.MarkForRedraw()
+	}
+	if i.prevMouseMove != nil {
+		return i.prevMouseMove(where, mod)
+	}
+	return false
+}
+
+func (i *Inspector) willClose() {
+	if i.treeWindow != nil {
+		i.treeWindow.Dispose()
+	}
+	if i.prevWillClose != nil {
+		i.prevWillClose()
+	}
+}
+
+func (i *Inspector) target() *Panel {
+	if i.selected != nil && i.selected.Window() == i.window {
+		return i.selected
+	}
+	if !i.window.IsValid() {
+		return nil
+	}
+	return i.window.root.PanelAt(i.window.MouseLocation())
+}
+
+func (i *Inspector) drawOver(gc *Canvas, rect Rect) {
+	if i.prevDrawOver != nil {
+		i.prevDrawOver(gc, rect)
+	}
+	if !i.active {
+		return
+	}
+	root := i.window.root.AsPanel()
+	paint := i.FrameColor.Paint(gc, rect, paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	frame := root.FrameRect()
+	i.drawFrames(gc, root, frame.CopyAndZeroLocation(), paint)
+	if target := i.target(); target != nil {
+		i.drawTarget(gc, target, rect)
+	}
+}
+
+func (i *Inspector) drawFrames(gc *Canvas, panel *Panel, clip Rect, paint *Paint) {
+	if panel.Hidden {
+		return
+	}
+	r := panel.RectToRoot(panel.ContentRect(true))
+	r.Intersect(clip)
+	if r.IsEmpty() {
+		return
+	}
+	gc.DrawRect(inspectorStrokeRect(r), paint)
+	for _, child := range panel.children {
+		i.drawFrames(gc, child, r, paint)
+	}
+}
+
+func (i *Inspector) drawTarget(gc *Canvas, target *Panel, rect Rect) {
+	r := target.RectToRoot(target.ContentRect(true))
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	content := r
+	content.Inset(insets)
+	if insets != (Insets{}) {
+		path := NewPath()
+		path.Rect(r)
+		path.Rect(content)
+		path.SetFillType(EvenOdd)
+		gc.DrawPath(path, i.InsetsColor.Paint(gc, r, paintstyle.Fill))
+	}
+	gc.DrawRect(content, i.ContentColor.Paint(gc, content, paintstyle.Fill))
+	paint := i.TargetColor.Paint(gc, r, paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	gc.DrawRect(inspectorStrokeRect(r), paint)
+	i.drawInfo(gc, rect, r, inspectorInfo(target, insets))
+}
+
+func (i *Inspector) drawInfo(gc *Canvas, bounds, avoid Rect, lines []string) {
+	font := i.Font
+	lineHeight := font.LineHeight()
+	var width float32
+	for _, line := range lines {
+		width = max(width, font.SimpleWidth(line))
+	}
+	const margin = 4
+	box := Rect{Size: Size{Width: width + margin*2, Height: lineHeight*float32(len(lines)) + margin*2}}
+	box.X = avoid.X
+	box.Y = avoid.Bottom() + 2
+	if box.Bottom() > bounds.Bottom() {
+		box.Y = avoid.Y - (box.Height + 2)
+	}
+	box.X = max(min(box.X, bounds.Right()-box.Width), bounds.X)
+	box.Y = max(min(box.Y, bounds.Bottom()-box.Height), bounds.Y)
+	gc.DrawRect(box, i.InfoColor.Paint(gc, box, paintstyle.Fill))
+	paint := i.OnInfoColor.Paint(gc, box, paintstyle.Fill)
+	y := box.Y + margin + font.Baseline()
+	for _, line := range lines {
+		gc.DrawSimpleString(line, box.X+margin, y, font, paint)
+		y += lineHeight
+	}
+}
+
+func inspectorInfo(panel *Panel, insets Insets) []string {
+	lines := make([]string, 0, 8)
+	header := panel.String()
+	if panel.RefKey != "" {
+		header += " [" + panel.RefKey + "]"
+	}
+	lines = append(lines, header)
+	frame := panel.FrameRect()
+	lines = append(lines, fmt.Sprintf("frame:  %g,%g %s", frame.X, frame.Y, inspectorSize(frame.Size)))
+	if insets != (Insets{}) {
+		lines = append(lines, fmt.Sprintf("insets: t:%g l:%g b:%g r:%g", insets.Top, insets.Left, insets.Bottom,
+			insets.Right))
+	}
+	minSize, prefSize, maxSize := panel.Sizes(Size{})
+	lines = append(lines, fmt.Sprintf("sizes:  min %s, pref %s, max %s", inspectorSize(minSize),
+		inspectorSize(prefSize), inspectorSize(maxSize)))
+	if ld, ok := panel.LayoutData().(*FlexLayoutData); ok && ld != nil {
+		lines = append(lines,
+			fmt.Sprintf("flex:   span %dx%d, align %s/%s, grab %v/%v", ld.HSpan, ld.VSpan, ld.HAlign, ld.VAlign,
+				ld.HGrab, ld.VGrab),
+			fmt.Sprintf("        hint %s, min %s", inspectorSize(ld.SizeHint), inspectorSize(ld.MinSize)))
+	}
+	return lines
+}
+
+// inspectorStrokeRect returns the rect inset by half a pixel, so that a 1 pixel stroke lands within it.
+func inspectorStrokeRect(r Rect) Rect {
+	r.Inset(NewUniformInsets(0.5))
+	return r
+}
+
+func inspectorSize(size Size) string {
+	return fmt.Sprintf("%gx%g", size.Width, size.Height)
+}
+
+var _ TableRowData[*inspectorRow] = &inspectorRow{}
+
+type inspectorRow struct {
+	inspector *Inspector
+	parent    *inspectorRow
+	panel     *Panel
+	children  []*inspectorRow
+	id        uuid.UUID
+}
+
+func (r *inspectorRow) find(panel *Panel) *inspectorRow {
+	if r.panel == panel {
+		return r
+	}
+	for _, child := range r.children {
+		if found := child.find(panel); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (r *inspectorRow) CloneForTarget(_ Paneler, newParent *inspectorRow) *inspectorRow {
+	clone := *r
+	clone.parent = newParent
+	clone.id = uuid.New()
+	return &clone
+}
+
+func (r *inspectorRow) UUID() uuid.UUID {
+	return r.id
+}
+
+func (r *inspectorRow) Parent() *inspectorRow {
+	return r.parent
+}
+
+func (r *inspectorRow) SetParent(parent *inspectorRow) {
+	r.parent = parent
+}
+
+func (r *inspectorRow) CanHaveChildren() bool {
+	return len(r.children) != 0
+}
+
+func (r *inspectorRow) Children() []*inspectorRow {
+	return r.children
+}
+
+func (r *inspectorRow) SetChildren(children []*inspectorRow) {
+	r.children = children
+}
+
+func (r *inspectorRow) CellDataForSort(col int) string {
+	p := r.panel
+	switch col {
+	case inspectorTypeColumn:
+		return p.String()
+	case inspectorRefKeyColumn:
+		return p.RefKey
+	case inspectorEnabledColumn:
+		return fmt.Sprint(p.Enabled())
+	case inspectorFocusedColumn:
+		return fmt.Sprint(p.Focused())
+	case inspectorMinColumn, inspectorPrefColumn, inspectorMaxColumn:
+		minSize, prefSize, maxSize := p.Sizes(Size{})
+		switch col {
+		case inspectorMinColumn:
+			return inspectorSize(minSize)
+		case inspectorPrefColumn:
+			return inspectorSize(prefSize)
+		default:
+			return inspectorSize(maxSize)
+		}
+	case inspectorClientDataColumn:
+		// Access the field directly, since ClientData() would allocate a map as a side effect
+		if len(p.data) == 0 {
+			return ""
+		}
+		keys := make([]string, 0, len(p.data))
+		for k := range p.data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		var buffer strings.Builder
+		for j, k := range keys {
+			if j != 0 {
+				buffer.WriteString(", ")
+			}
+			fmt.Fprintf(&buffer, "%s=%v", k, p.data[k])
+		}
+		return buffer.String()
+	default:
+		return ""
+	}
+}
+
+func (r *inspectorRow) ColumnCell(_, col int, foreground, _ Ink, _, _, _ bool) Paneler {
+	label := NewLabel()
+	label.Text = r.CellDataForSort(col)
+	label.OnBackgroundInk = foreground
+	if col != inspectorTypeColumn && col != inspectorRefKeyColumn {
+		label.Font = MonospacedFont
+	}
+	return label
+}
+
+func (r *inspectorRow) IsOpen() bool {
+	return !r.inspector.closed[r.panel]
+}
+
+func (r *inspectorRow) SetOpen(open bool) {
+	if open {
+		delete(r.inspector.closed, r.panel)
+	} else {
+		r.inspector.closed[r.panel] = true
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+)
+
+func inspectorRowPanels(rows []*inspectorRow) []*Panel {
+	var list []*Panel
+	for _, row := range rows {
+		list = append(list, row.panel)
+		list = append(list, inspectorRowPanels(row.children)...)
+	}
+	return list
+}
+
+func TestInspector(t *testing.T) {
+	wnd, err := NewWindow("", OffscreenWindowOption(Size{Width: 100, Height: 100}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	content := NewPanel()
+	outer := NewPanel()
+	outer.RefKey = "outer"
+	content.AddChild(outer)
+	inner := NewPanel()
+	outer.AddChild(inner)
+	wnd.SetContent(content)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+	var passedKeys []KeyCode
+	wnd.KeyDownCallback = func(keyCode KeyCode, _ Modifiers, _ bool) bool {
+		passedKeys = append(passedKeys, keyCode)
+		return true
+	}
+
+	i := InstallInspector(wnd, KeyBinding{})
+	check.Equal(t, DefaultInspectorKeyBinding(), i.KeyBinding)
+	check.NotNil(t, wnd.root.DrawOverCallback)
+	check.NotNil(t, wnd.MouseEnterCallback)
+	check.NotNil(t, wnd.MouseMoveCallback)
+	check.NotNil(t, wnd.WillCloseCallback)
+	wnd.InjectKeyDown(KeyA, 0, false)
+	check.Equal(t, []KeyCode{KeyA}, passedKeys, "other keys must reach the previous callback")
+
+	wnd.InjectKeyDown(KeyI, i.KeyBinding.Modifiers, false)
+	check.True(t, i.Active())
+	check.Equal(t, []KeyCode{KeyA}, passedKeys, "the key binding must not reach the previous callback")
+	check.Nil(t, i.treeWindow, "offscreen windows don't open the tree view on their own")
+
+	i.ShowTree()
+	check.NotNil(t, i.table)
+	check.True(t, i.treeWindow.IsOffscreen())
+	rows := i.table.RootRows()
+	check.Equal(t, 1, len(rows))
+	check.Equal(t, []*Panel{wnd.root.AsPanel(), content, outer, inner}, inspectorRowPanels(rows))
+	check.Equal(t, "outer", rows[0].children[0].children[0].CellDataForSort(inspectorRefKeyColumn))
+
+	rows[0].children[0].children[0].SetOpen(false)
+	check.True(t, i.closed[outer])
+	i.Refresh()
+	check.False(t, i.table.RootRows()[0].children[0].children[0].IsOpen(), "collapsed state survives a refresh")
+
+	content.RemoveAllChildren()
+	i.Refresh()
+	check.Equal(t, []*Panel{wnd.root.AsPanel(), content}, inspectorRowPanels(i.table.RootRows()))
+	check.Equal(t, 0, len(i.closed), "panels that left the window must be forgotten")
+
+	wnd.InjectKeyDown(KeyI, i.KeyBinding.Modifiers, false)
+	check.False(t, i.Active())
+	check.Nil(t, i.treeWindow)
+
+	i.Uninstall()
+	check.Nil(t, wnd.root.DrawOverCallback)
+	check.Nil(t, wnd.MouseEnterCallback)
+	check.Nil(t, wnd.MouseMoveCallback)
+	check.Nil(t, wnd.WillCloseCallback)
+	wnd.InjectKeyDown(KeyI, DefaultInspectorKeyBinding().Modifiers, false)
+	check.False(t, i.Active())
+	check.Equal(t, []KeyCode{KeyA, KeyI}, passedKeys, "the previous callback must be restored")
+}
@@ -0,0 +1,262 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ddkwork/toolbox"
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/toolbox/i18n"
+	"github.com/ddkwork/unison/enums/align"
+)
+
+// DefaultJobShowDelay is the default amount of time a job runs for before its progress dialog is shown.
+const DefaultJobShowDelay = 500 * time.Millisecond
+
+const jobProgressUpdateInterval = time.Second / 30
+
+// JobProgress is used by a job to report its progress. Its methods may be called from any goroutine.
+type JobProgress struct {
+	job           *Job
+	message       string
+	lock          sync.Mutex
+	fraction      float32
+	indeterminate bool
+}
+
+// SetFraction sets the fraction of the work that has been completed, in the range 0-1. This also takes the progress
+// out of indeterminate mode.
+func (p *JobProgress) SetFraction(fraction float32) {
+	p.lock.Lock()
+	p.fraction = min(max(fraction, 0), 1)
+	p.indeterminate = false
+	p.lock.Unlock()
+	p.job.throttle.Trigger()
+}
+
+// SetIndeterminate puts the progress into indeterminate mode, for when the amount of work remaining is unknown.
+func (p *JobProgress) SetIndeterminate() {
+	p.lock.Lock()
+	p.indeterminate = true
+	p.lock.Unlock()
+	p.job.throttle.Trigger()
+}
+
+// SetMessage sets the message describing what the job is currently doing.
+func (p *JobProgress) SetMessage(msg string) {
+	p.lock.Lock()
+	p.message = msg
+	p.lock.Unlock()
+	p.job.throttle.Trigger()
+}
+
+func (p *JobProgress) state() (fraction float32, msg string, indeterminate bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.fraction, p.message, p.indeterminate
+}
+
+// Job runs a long-running function on a background goroutine, displaying a progress dialog if it takes longer than
+// ShowDelay to complete.
+type Job struct {
+	// Title is shown as the primary message in the progress dialog, and in the error dialog should the job fail.
+	Title string
+	// ShowDelay is the amount of time the job must run for before the progress dialog is shown.
+	ShowDelay time.Duration
+	// DoneCallback, if set, is called on the UI thread once the job has finished and its dialogs have closed. err will
+	// be nil on success and will match context.Canceled if the job was canceled.
+	DoneCallback func(err error)
+	// Modal causes the progress dialog to be run as a modal dialog, blocking interaction with other windows.
+	Modal bool
+	// Cancelable causes the progress dialog to have a Cancel button, which cancels the job's context.
+	Cancelable bool
+	f          func(ctx context.Context, progress *JobProgress) error
+	ctx        context.Context
+	cancel     context.CancelFunc
+	progress   *JobProgress
+	throttle   *Throttler
+	dialog     *Dialog
+	message    *Label
+	bar        *ProgressBar
+	err        error
+	started    bool
+	done       bool
+	inModal    bool
+}
+
+// NewJob creates a new cancelable, non-modal job which will call f when started. f should periodically check ctx and
+// return its error once it is done.
+func NewJob(title string, f func(ctx context.Context, progress *JobProgress) error) *Job {
+	j := &Job{
+		Title:      title,
+		ShowDelay:  DefaultJobShowDelay,
+		Cancelable: true,
+		f:          f,
+	}
+	j.progress = &JobProgress{
+		job:           j,
+		indeterminate: true,
+	}
+	j.throttle = NewThrottler(jobProgressUpdateInterval, j.updateDialog)
+	return j
+}
+
+// Start the job. A job may only be started once. Must be called on the UI thread.
+func (j *Job) Start() {
+	if j.started {
+		return
+	}
+	j.started = true
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+	InvokeTaskAfterWithContext(j.ctx, j.showDialog, j.ShowDelay)
+	go j.run()
+}
+
+// Cancel the job. This only cancels the job's context; the job finishes once its function returns.
+func (j *Job) Cancel() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+}
+
+// Done returns true once the job has finished.
+func (j *Job) Done() bool {
+	return j.done
+}
+
+// Err returns the error the job finished with, if any.
+func (j *Job) Err() error {
+	return j.err
+}
+
+func (j *Job) run() {
+	var err error
+	toolbox.CallWithHandler(func() { err = j.f(j.ctx, j.progress) }, func(panicErr error) { err = panicErr })
+	if err == nil && j.ctx.Err() != nil {
+		err = j.ctx.Err()
+	}
+	InvokeTask(func() { j.finish(err) })
+}
+
+func (j *Job) showDialog() {
+	if j.done || j.dialog != nil {
+		return
+	}
+	var buttons []*DialogButtonInfo
+	if j.Cancelable {
+		buttons = append(buttons, NewCancelButtonInfo())
+	}
+	panel := NewPanel()
+	panel.SetLayout(&FlexLayout{
+		Columns:  1,
+		HSpacing: StdHSpacing,
+		VSpacing: StdVSpacing,
+	})
+	title := NewLabel()
+	title.Text = j.Title
+	title.Font = EmphasizedSystemFont
+	panel.AddChild(title)
+	j.message = NewLabel()
+	j.message.Text = " "
+	j.message.SetLayoutData(&FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(j.message)
+	j.bar = NewProgressBar(0)
+	j.bar.SetLayoutData(&FlexLayoutData{
+		MinSize: Size{Width: 300},
+		HAlign:  align.Fill,
+		HGrab:   true,
+	})
+	panel.AddChild(j.bar)
+	var err error
+	if j.dialog, err = NewDialog(nil, nil, panel, buttons); err != nil {
+		errs.Log(err)
+		return
+	}
+	if b := j.dialog.Button(ModalResponseCancel); b != nil {
+		b.ClickCallback = func() {
+			b.SetEnabled(false)
+			j.message.Text = i18n.Text("Canceling…")
+			j.message.MarkForRedraw()
+			j.Cancel()
+		}
+	}
+	j.dialog.Window().AllowCloseCallback = func() bool {
+		if j.Cancelable {
+			j.Cancel()
+		}
+		return false
+	}
+	j.updateDialog()
+	if j.Modal {
+		j.inModal = true
+		j.dialog.RunModal()
+		j.inModal = false
+		j.dialog = nil
+		j.report()
+	} else {
+		j.dialog.Window().ToFront()
+	}
+}
+
+func (j *Job) updateDialog() {
+	if j.dialog == nil || j.done {
+		return
+	}
+	fraction, msg, indeterminate := j.progress.state()
+	if b := j.dialog.Button(ModalResponseCancel); b == nil || b.Enabled() {
+		if msg == "" {
+			msg = " "
+		}
+		if j.message.Text != msg {
+			j.message.Text = msg
+			j.message.MarkForRedraw()
+		}
+	}
+	if indeterminate {
+		j.bar.SetMaximum(0)
+	} else {
+		j.bar.SetMaximum(1)
+		j.bar.SetCurrent(fraction)
+	}
+}
+
+func (j *Job) finish(err error) {
+	j.done = true
+	j.cancel()
+	j.throttle.Cancel()
+	j.err = err
+	switch {
+	case j.inModal:
+		// The code following RunModal() will take care of reporting
+		j.dialog.StopModal(ModalResponseOK)
+	case j.dialog != nil:
+		j.dialog.Window().Dispose()
+		j.dialog = nil
+		j.report()
+	default:
+		j.report()
+	}
+}
+
+func (j *Job) report() {
+	if j.err != nil && !errors.Is(j.err, context.Canceled) {
+		ErrorDialogWithError(j.Title, j.err)
+	}
+	if j.DoneCallback != nil {
+		j.DoneCallback(j.err)
+	}
+}
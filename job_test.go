@@ -0,0 +1,132 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/toolbox/errs"
+)
+
+// runTasksUntilJobDone stands in for the UI event loop while the job's goroutine finishes up.
+func runTasksUntilJobDone(t *testing.T, j *Job) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !j.Done() {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		RunPendingTasks()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// installOffscreenDialogs causes dialogs to be placed in offscreen windows until the test finishes, so that no display
+// is needed.
+func installOffscreenDialogs(t *testing.T) {
+	t.Helper()
+	prev := newDialogWindow
+	newDialogWindow = func() (*Window, error) {
+		return NewWindow("", OffscreenWindowOption(Size{Width: 400, Height: 200}, 1))
+	}
+	t.Cleanup(func() { newDialogWindow = prev })
+}
+
+func labelTexts(panel *Panel) []string {
+	var list []string
+	if label, ok := panel.Self.(*Label); ok {
+		list = append(list, label.Text)
+	}
+	for _, child := range panel.children {
+		list = append(list, labelTexts(child)...)
+	}
+	return list
+}
+
+func waitForContextCancel(ctx context.Context, _ *JobProgress) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestJobShowsDialogAfterDelay(t *testing.T) {
+	clock := installFakeClock(t)
+	installOffscreenDialogs(t)
+	j := NewJob("Working", waitForContextCancel)
+	var doneErr error
+	j.DoneCallback = func(err error) { doneErr = err }
+	j.Start()
+	RunPendingTasks()
+	check.Nil(t, j.dialog)
+	clock.advance(j.ShowDelay - time.Millisecond)
+	RunPendingTasks()
+	check.Nil(t, j.dialog, "the dialog must not be shown before the delay")
+
+	clock.advance(time.Millisecond)
+	RunPendingTasks()
+	check.NotNil(t, j.dialog)
+	wnd := j.dialog.Window()
+	check.True(t, wnd.IsValid())
+	check.Equal(t, "Working", labelTexts(wnd.Content())[0])
+
+	cancelButton := j.dialog.Button(ModalResponseCancel)
+	check.NotNil(t, cancelButton)
+	cancelButton.ClickCallback()
+	check.True(t, errors.Is(j.ctx.Err(), context.Canceled), "Cancel must cancel the job's context")
+	runTasksUntilJobDone(t, j)
+	check.False(t, wnd.IsValid())
+	check.True(t, errors.Is(doneErr, context.Canceled))
+	check.Equal(t, 0, len(modalStack), "a canceled job must not report an error")
+}
+
+func TestJobCanceledBeforeDelay(t *testing.T) {
+	clock := installFakeClock(t)
+	installOffscreenDialogs(t)
+	j := NewJob("Working", waitForContextCancel)
+	j.Start()
+	j.Cancel()
+	check.True(t, errors.Is(j.ctx.Err(), context.Canceled))
+	runTasksUntilJobDone(t, j)
+	clock.advance(j.ShowDelay)
+	RunPendingTasks()
+	check.Nil(t, j.dialog, "the dialog must not be shown once the job has finished")
+	check.True(t, errors.Is(j.Err(), context.Canceled))
+}
+
+func TestJobReportsError(t *testing.T) {
+	installFakeClock(t)
+	installOffscreenDialogs(t)
+	failure := errs.New("disk full")
+	j := NewJob("Saving", func(_ context.Context, _ *JobProgress) error { return failure })
+	var doneErr error
+	j.DoneCallback = func(err error) { doneErr = err }
+	var shown []string
+	var dismissErrorDialog func()
+	dismissErrorDialog = func() {
+		if len(modalStack) == 0 {
+			InvokeTask(dismissErrorDialog)
+			return
+		}
+		wnd := modalStack[len(modalStack)-1]
+		shown = labelTexts(wnd.Content())
+		wnd.StopModal(ModalResponseOK)
+	}
+	InvokeTask(dismissErrorDialog)
+	j.Start()
+	runTasksUntilJobDone(t, j)
+	check.True(t, slices.Contains(shown, "Saving"), "the error dialog must show the job's title")
+	check.True(t, slices.Contains(shown, "disk full"), "the error dialog must show the error's message")
+	check.True(t, doneErr == failure)
+	check.True(t, j.Err() == failure)
+}
@@ -0,0 +1,35 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"github.com/ddkwork/unison"
+)
+
+// newSizedPanel adds a new panel to the parent that reports the given sizes, regardless of the hint it is given.
+func newSizedPanel(parent *unison.Panel, minSize, prefSize, maxSize unison.Size) *unison.Panel {
+	child := unison.NewPanel()
+	child.SetSizer(func(_ unison.Size) (minimum, preferred, maximum unison.Size) {
+		return minSize, prefSize, maxSize
+	})
+	parent.AddChild(child)
+	return child
+}
+
+// newLayoutChild adds a new panel to the parent with a minimum size of 10x10, a preferred size of 50x20 and a maximum
+// size of 1000x1000. The layout data is set on it if it isn't nil.
+func newLayoutChild(parent *unison.Panel, data any) *unison.Panel {
+	child := newSizedPanel(parent, unison.Size{Width: 10, Height: 10}, unison.Size{Width: 50, Height: 20},
+		unison.Size{Width: 1000, Height: 1000})
+	if data != nil {
+		child.SetLayoutData(data)
+	}
+	return child
+}
@@ -827,8 +827,7 @@ func (m *Markdown) retrieveImage(target string, label *Label) *Image {
 				InvokeTask(func() {
 					m.imgCache[revisedTarget] = img
 					label.Drawable = m.constrainImage(img)
-					label.MarkForRedraw()
-					label.MarkForLayoutRecursivelyUpward()
+					label.InvalidateLayout()
 				})
 			}
 		}()
@@ -0,0 +1,389 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ddkwork/toolbox/i18n"
+	"github.com/ddkwork/toolbox/xmath"
+)
+
+var _ NumericEvaluator = &ExpressionEvaluator{}
+
+// LengthUnits holds common units of length, converted to inches.
+var LengthUnits = Units{
+	"in": 1,
+	`"`:  1,
+	"ft": 12,
+	"'":  12,
+	"yd": 36,
+	"mi": 63360,
+	"pt": 1.0 / 72,
+	"mm": 1 / 25.4,
+	"cm": 1 / 2.54,
+	"m":  100 / 2.54,
+	"km": 100000 / 2.54,
+}
+
+// PercentUnits holds the percent unit, converted to a fraction.
+var PercentUnits = Units{"%": 0.01}
+
+// Units maps unit symbols to the factor that converts a value in that unit to the base unit of the table. For example,
+// a table with a base unit of inches would map "ft" to 12.
+type Units map[string]float64
+
+// NumericEvaluator evaluates text into a number.
+type NumericEvaluator interface {
+	Evaluate(text string) (float64, error)
+}
+
+// ExpressionError is returned by ExpressionEvaluator when the text cannot be evaluated.
+type ExpressionError struct {
+	// Message describes the problem.
+	Message string
+	// Position is the byte offset within the text at which the problem was found.
+	Position int
+	// Incomplete is true if the text is not yet valid, but could become so by typing more at the end of it, such as an
+	// expression with an unclosed parenthesis or a trailing operator.
+	Incomplete bool
+}
+
+func (e *ExpressionError) Error() string {
+	return e.Message
+}
+
+// ExpressionEvaluator is a NumericEvaluator that permits simple arithmetic, optionally with units and variables. Its
+// grammar is deliberately small so that it is safe to use on any user input:
+//
+//	expression := term { ("+" | "-") term }
+//	term       := power { ("*" | "/") power }
+//	power      := unary [ "^" power ]
+//	unary      := ("+" | "-") unary | primary
+//	primary    := (number | variable | call | "(" expression ")") [unit]
+//	call       := name "(" [ expression { "," expression } ] ")"
+//
+// Numbers without a unit are taken to be in the base unit of the Units table, as is the result.
+type ExpressionEvaluator struct {
+	// Units holds the units that may follow a value. May be nil.
+	Units Units
+	// Variables resolves names into values. May be nil.
+	Variables func(name string) (value float64, ok bool)
+	// Functions holds the functions that may be called. May be nil.
+	Functions map[string]func(args ...float64) (float64, error)
+}
+
+// ExpressionExtractor returns a function suitable for use as the extract function of NewNumericField that evaluates the
+// text with the evaluator. Results are rounded for integer types. An *ExpressionError is returned if the result cannot
+// be represented by T.
+func ExpressionExtractor[T xmath.Numeric](evaluator NumericEvaluator) func(s string) (T, error) {
+	return func(s string) (T, error) {
+		v, err := evaluator.Evaluate(s)
+		if err != nil {
+			return 0, err
+		}
+		var inRange bool
+		if isIntegral[T]() {
+			v = math.Round(v)
+			// Converting an out-of-range value yields an arbitrary result, which never converts back to the same value
+			inRange = float64(T(v)) == v
+		} else {
+			inRange = !math.IsInf(float64(T(v)), 0)
+		}
+		if !inRange {
+			return 0, &ExpressionError{Message: i18n.Text("Result is out of range")}
+		}
+		return T(v), nil
+	}
+}
+
+// Evaluate implements NumericEvaluator.
+func (e *ExpressionEvaluator) Evaluate(text string) (float64, error) {
+	p := &expressionParser{evaluator: e, text: text}
+	p.skipSpace()
+	if p.atEnd() {
+		return 0, p.fail(i18n.Text("A value is required"), true)
+	}
+	v, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	if !p.atEnd() {
+		if p.peek() == ')' {
+			return 0, p.fail(i18n.Text("Unexpected closing parenthesis"), false)
+		}
+		return 0, p.fail(fmt.Sprintf(i18n.Text("Unexpected %q"), p.peek()), false)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, p.fail(i18n.Text("Result is not a finite number"), false)
+	}
+	return v, nil
+}
+
+type expressionParser struct {
+	evaluator *ExpressionEvaluator
+	text      string
+	pos       int
+}
+
+func (p *expressionParser) atEnd() bool {
+	return p.pos >= len(p.text)
+}
+
+func (p *expressionParser) peek() rune {
+	if p.atEnd() {
+		return 0
+	}
+	ch, _ := utf8.DecodeRuneInString(p.text[p.pos:])
+	return ch
+}
+
+func (p *expressionParser) skipSpace() {
+	for !p.atEnd() {
+		ch, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if !unicode.IsSpace(ch) {
+			break
+		}
+		p.pos += size
+	}
+}
+
+func (p *expressionParser) consume(ch byte) bool {
+	p.skipSpace()
+	if !p.atEnd() && p.text[p.pos] == ch {
+		p.pos++
+		p.skipSpace()
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) fail(msg string, incomplete bool) error {
+	return &ExpressionError{Message: msg, Position: p.pos, Incomplete: incomplete}
+}
+
+func (p *expressionParser) expression() (float64, error) {
+	left, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.consume('+'):
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			left += right
+		case p.consume('-'):
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			left -= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *expressionParser) term() (float64, error) {
+	left, err := p.power()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.consume('*'):
+			right, err := p.power()
+			if err != nil {
+				return 0, err
+			}
+			left *= right
+		case p.consume('/'):
+			start := p.pos
+			right, err := p.power()
+			if err != nil {
+				return 0, err
+			}
+			if right == 0 {
+				return 0, &ExpressionError{Message: i18n.Text("Division by zero"), Position: start}
+			}
+			left /= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *expressionParser) power() (float64, error) {
+	base, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	if p.consume('^') {
+		exponent, err := p.power()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exponent), nil
+	}
+	return base, nil
+}
+
+func (p *expressionParser) unary() (float64, error) {
+	switch {
+	case p.consume('-'):
+		v, err := p.unary()
+		return -v, err
+	case p.consume('+'):
+		return p.unary()
+	default:
+		return p.primary()
+	}
+}
+
+func (p *expressionParser) primary() (float64, error) {
+	p.skipSpace()
+	if p.atEnd() {
+		return 0, p.fail(i18n.Text("A value is expected"), true)
+	}
+	var v float64
+	var err error
+	ch := p.peek()
+	switch {
+	case ch == '(':
+		p.pos++
+		if v, err = p.expression(); err != nil {
+			return 0, err
+		}
+		if !p.consume(')') {
+			return 0, p.fail(i18n.Text("Missing closing parenthesis"), p.atEnd())
+		}
+	case ch == '.' || (ch >= '0' && ch <= '9'):
+		if v, err = p.number(); err != nil {
+			return 0, err
+		}
+	case unicode.IsLetter(ch) || ch == '_':
+		if v, err = p.name(); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, p.fail(fmt.Sprintf(i18n.Text("Unexpected %q"), ch), false)
+	}
+	return p.unit(v)
+}
+
+func (p *expressionParser) number() (float64, error) {
+	start := p.pos
+	for !p.atEnd() && (p.text[p.pos] == '.' || (p.text[p.pos] >= '0' && p.text[p.pos] <= '9')) {
+		p.pos++
+	}
+	text := p.text[start:p.pos]
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.fail(fmt.Sprintf(i18n.Text("Invalid number %q"), text), text == ".")
+	}
+	return v, nil
+}
+
+func (p *expressionParser) identifier() string {
+	start := p.pos
+	for !p.atEnd() {
+		ch, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' {
+			break
+		}
+		p.pos += size
+	}
+	return p.text[start:p.pos]
+}
+
+func (p *expressionParser) name() (float64, error) {
+	start := p.pos
+	name := p.identifier()
+	if p.consume('(') {
+		f, ok := p.evaluator.Functions[name]
+		if !ok {
+			p.pos = start
+			return 0, p.fail(fmt.Sprintf(i18n.Text("Unknown function %q"), name), false)
+		}
+		var args []float64
+		if !p.consume(')') {
+			for {
+				arg, err := p.expression()
+				if err != nil {
+					return 0, err
+				}
+				args = append(args, arg)
+				if p.consume(')') {
+					break
+				}
+				if !p.consume(',') {
+					return 0, p.fail(i18n.Text("Missing closing parenthesis"), p.atEnd())
+				}
+			}
+		}
+		v, err := f(args...)
+		if err != nil {
+			return 0, &ExpressionError{Message: err.Error(), Position: start}
+		}
+		return v, nil
+	}
+	if p.evaluator.Variables != nil {
+		if v, ok := p.evaluator.Variables(name); ok {
+			return v, nil
+		}
+	}
+	incomplete := p.atEnd()
+	p.pos = start
+	return 0, p.fail(fmt.Sprintf(i18n.Text("Unknown name %q"), name), incomplete)
+}
+
+// unit applies any unit that follows the value.
+func (p *expressionParser) unit(v float64) (float64, error) {
+	if len(p.evaluator.Units) == 0 {
+		return v, nil
+	}
+	p.skipSpace()
+	start := p.pos
+	for !p.atEnd() {
+		ch, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if !unicode.IsLetter(ch) && !strings.ContainsRune(`%"'°`, ch) {
+			break
+		}
+		p.pos += size
+	}
+	if start == p.pos {
+		return v, nil
+	}
+	symbol := p.text[start:p.pos]
+	if factor, ok := p.evaluator.Units[symbol]; ok {
+		p.skipSpace()
+		return v * factor, nil
+	}
+	incomplete := false
+	if p.atEnd() {
+		for one := range p.evaluator.Units {
+			if strings.HasPrefix(one, symbol) {
+				incomplete = true
+				break
+			}
+		}
+	}
+	p.pos = start
+	return 0, p.fail(fmt.Sprintf(i18n.Text("Unknown unit %q"), symbol), incomplete)
+}
@@ -0,0 +1,93 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestExpressionEvaluator(t *testing.T) {
+	e := &unison.ExpressionEvaluator{
+		Units: unison.LengthUnits,
+		Variables: func(name string) (float64, bool) {
+			if name == "width" {
+				return 10, true
+			}
+			return 0, false
+		},
+	}
+	for _, one := range []struct {
+		text     string
+		expected float64
+	}{
+		{"12*3+4", 40},
+		{"2.5in", 2.5},
+		{"1ft + 6", 18},
+		{"-(2 + 3) * 2", -10},
+		{"2^3^2", 512},
+		{"width / 4", 2.5},
+		{"1yd - 2ft", 12},
+	} {
+		v, err := e.Evaluate(one.text)
+		check.NoError(t, err, one.text)
+		check.Equal(t, one.expected, v, one.text)
+	}
+
+	for _, one := range []struct {
+		text       string
+		incomplete bool
+	}{
+		{"12*", true},
+		{"(1+2", true},
+		{"3 f", true},
+		{"height", true},
+		{"1/0", false},
+		{"1)", false},
+		{"2 @ 3", false},
+		{"3 xyz", false},
+	} {
+		_, err := e.Evaluate(one.text)
+		var exprErr *unison.ExpressionError
+		check.True(t, errors.As(err, &exprErr), one.text)
+		check.Equal(t, one.incomplete, exprErr.Incomplete, one.text)
+	}
+
+	extract := unison.ExpressionExtractor[int](&unison.ExpressionEvaluator{Units: unison.PercentUnits})
+	v, err := extract("50% * 7")
+	check.NoError(t, err)
+	check.Equal(t, 4, v)
+	_, err = extract("10^30")
+	var exprErr *unison.ExpressionError
+	check.True(t, errors.As(err, &exprErr), "10^30 must not fit in an int")
+	check.False(t, exprErr.Incomplete)
+
+	extract8 := unison.ExpressionExtractor[uint8](&unison.ExpressionEvaluator{})
+	v8, err := extract8("255")
+	check.NoError(t, err)
+	check.Equal(t, uint8(255), v8)
+	for _, text := range []string{"256", "-1", "-0.6"} {
+		_, err = extract8(text)
+		check.True(t, errors.As(err, &exprErr), text)
+	}
+	v8, err = extract8("-0.4")
+	check.NoError(t, err)
+	check.Equal(t, uint8(0), v8)
+
+	extract32 := unison.ExpressionExtractor[float32](&unison.ExpressionEvaluator{})
+	f, err := extract32("0.1")
+	check.NoError(t, err)
+	check.Equal(t, float32(0.1), f)
+	_, err = extract32("10^39")
+	check.True(t, errors.As(err, &exprErr), "10^39 must not fit in a float32")
+}
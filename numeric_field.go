@@ -10,38 +10,103 @@
 package unison
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/ddkwork/golibrary/mylog"
 	"github.com/ddkwork/toolbox/i18n"
 	"github.com/ddkwork/toolbox/xmath"
+	"github.com/ddkwork/unison/enums/paintstyle"
 )
 
+// DefaultNumericFieldTheme holds the default NumericFieldTheme values for NumericFields. Modifying this data will not
+// alter existing NumericFields, but will alter any NumericFields created in the future.
+var DefaultNumericFieldTheme = NumericFieldTheme{
+	StepperInk:           OnEditableColor,
+	StepperPressedInk:    ControlPressedColor,
+	OnStepperPressedInk:  OnControlPressedColor,
+	StepperEdgeInk:       ControlEdgeColor,
+	StepperWidth:         12,
+	CoarseStepMultiplier: 10,
+	FineStepMultiplier:   0.1,
+	ScrubDistancePerStep: 4,
+	AutoRepeatDelay:      400 * time.Millisecond,
+	AutoRepeatInterval:   50 * time.Millisecond,
+}
+
+// NumericFieldTheme holds theming data for the stepping behavior of a NumericField.
+type NumericFieldTheme struct {
+	StepperInk          Ink
+	StepperPressedInk   Ink
+	OnStepperPressedInk Ink
+	StepperEdgeInk      Ink
+	StepperWidth        float32
+	// CoarseStepMultiplier is applied to the step while the shift key is down.
+	CoarseStepMultiplier float64
+	// FineStepMultiplier is applied to the step while the option key is down.
+	FineStepMultiplier float64
+	// ScrubDistancePerStep is the distance the mouse must be dragged horizontally to change the value by one step.
+	ScrubDistancePerStep float32
+	AutoRepeatDelay      time.Duration
+	AutoRepeatInterval   time.Duration
+}
+
 // NumericField holds a numeric value that can be edited.
+//
+// When Step is greater than zero, the value may also be stepped with the up and down arrow keys (page up and page down
+// use the coarse step), with the mouse wheel while the field has the focus, with the optional stepper buttons, and by
+// dragging horizontally across the field while it does not have the focus. All such changes are made via SetValue().
+// Since a press on a field without the focus may be the start of such a drag, the field only gains the focus when the
+// button is released without having dragged. When Step is zero, the field gains the focus as soon as it is pressed.
 type NumericField[T xmath.Numeric] struct {
 	*Field
+	NumericFieldTheme
 	Format     func(T) string
 	Extract    func(s string) (T, error)
 	Prototypes func(minimum, maximum T) []T
-	minimum    T
-	maximum    T
+	// Step is the amount the value changes by for each step. A value of 0 disables stepping.
+	Step              T
+	minimum           T
+	maximum           T
+	focusedBorder     Border
+	unfocusedBorder   Border
+	stepperPressed    int
+	stepperOver       bool
+	repeatGeneration  int
+	scrubStartValue   float64
+	scrubStartX       float32
+	scrubPending      bool
+	scrubbing         bool
+	pendingClickCount int
+	pendingMod        Modifiers
+	pendingWhere      Point
+	steppersVisible   bool
 }
 
 // NewNumericField creates a new field that holds a numeric value and limits its input to a specific range of values.
 // The format and extract functions allow the field to be presented as something other than numbers.
 func NewNumericField[T xmath.Numeric](current, minimum, maximum T, format func(T) string, extract func(s string) (T, error), prototypes func(minimum, maximum T) []T) *NumericField[T] {
 	f := &NumericField[T]{
-		Field:      NewField(),
-		Prototypes: prototypes,
-		Format:     format,
-		Extract:    extract,
-		minimum:    minimum,
-		maximum:    maximum,
+		Field:             NewField(),
+		NumericFieldTheme: DefaultNumericFieldTheme,
+		Prototypes:        prototypes,
+		Format:            format,
+		Extract:           extract,
+		minimum:           minimum,
+		maximum:           maximum,
 	}
 	f.Self = f
+	f.DrawCallback = f.DefaultDraw
 	f.LostFocusCallback = f.DefaultFocusLost
+	f.MouseDownCallback = f.DefaultMouseDown
+	f.MouseDragCallback = f.DefaultMouseDrag
+	f.MouseUpCallback = f.DefaultMouseUp
+	f.MouseWheelCallback = f.DefaultMouseWheel
+	f.UpdateCursorCallback = f.DefaultUpdateCursor
+	f.KeyDownCallback = f.DefaultKeyDown
 	f.RuneTypedCallback = f.DefaultRuneTyped
 	f.ValidateCallback = f.DefaultValidate
 	f.SetText(f.Format(current))
@@ -83,8 +148,11 @@ func (f *NumericField[T]) DefaultFocusLost() {
 func (f *NumericField[T]) DefaultRuneTyped(ch rune) bool {
 	if !unicode.IsControl(ch) {
 		if _, e := (f.Extract(strings.TrimSpace(string(f.RunesIfPasted([]rune{ch}))))); e != nil {
-			Beep()
-			return false
+			var exprErr *ExpressionError
+			if !errors.As(e, &exprErr) || !exprErr.Incomplete {
+				Beep()
+				return false
+			}
 		}
 	}
 	return f.Field.DefaultRuneTyped(ch)
@@ -102,10 +170,17 @@ func (f *NumericField[T]) DefaultValidate() bool {
 
 func (f *NumericField[T]) tooltipTextForValidation() string {
 	s := strings.TrimSpace(f.Text())
-	v := mylog.Check2(f.Extract(s))
 	if s == "-" || s == "+" {
 		return i18n.Text("Invalid value")
 	}
+	v, err := f.Extract(s)
+	if err != nil {
+		var exprErr *ExpressionError
+		if errors.As(err, &exprErr) {
+			return exprErr.Message
+		}
+		return i18n.Text("Invalid value")
+	}
 	if minimum := f.minimum; v < minimum {
 		return fmt.Sprintf(i18n.Text("Value must be at least %s"), f.Format(minimum))
 	}
@@ -137,3 +212,303 @@ func (f *NumericField[T]) adjustMinimumTextWidth() {
 		f.SetMinimumTextWidthUsing(candidates...)
 	}
 }
+
+// SteppersVisible returns true if the stepper buttons are shown.
+func (f *NumericField[T]) SteppersVisible() bool {
+	return f.steppersVisible
+}
+
+// SetSteppersVisible sets whether the stepper buttons are shown. The stepper buttons are placed inside the field's
+// border, so if the FocusedBorder or UnfocusedBorder are to be changed, do so before calling this method.
+func (f *NumericField[T]) SetSteppersVisible(visible bool) {
+	if f.steppersVisible == visible {
+		return
+	}
+	f.steppersVisible = visible
+	if visible {
+		f.focusedBorder = f.FocusedBorder
+		f.unfocusedBorder = f.UnfocusedBorder
+		room := NewEmptyBorder(Insets{Right: f.StepperWidth})
+		f.FocusedBorder = NewCompoundBorder(f.FocusedBorder, room)
+		f.UnfocusedBorder = NewCompoundBorder(f.UnfocusedBorder, room)
+	} else {
+		f.FocusedBorder = f.focusedBorder
+		f.UnfocusedBorder = f.unfocusedBorder
+		f.focusedBorder = nil
+		f.unfocusedBorder = nil
+	}
+	if f.Focused() {
+		f.SetBorder(f.FocusedBorder)
+	} else {
+		f.SetBorder(f.UnfocusedBorder)
+	}
+	f.InvalidateLayout()
+}
+
+// StepValue changes the value by the given number of steps, using the coarse or fine step if the modifiers call for it.
+func (f *NumericField[T]) StepValue(steps int, mod Modifiers) {
+	if f.Step > 0 {
+		f.setValueFrom(float64(f.Value()) + float64(steps)*f.stepAmount(mod))
+	}
+}
+
+// InstallScrubber makes dragging horizontally across the target, such as the label for this field, step the value. The
+// target's mouse and cursor callbacks are replaced.
+func (f *NumericField[T]) InstallScrubber(target Paneler) {
+	p := target.AsPanel()
+	p.MouseDownCallback = func(where Point, button, _ int, _ Modifiers) bool {
+		if button != ButtonLeft || f.Step <= 0 || !f.Enabled() {
+			return false
+		}
+		f.startScrub(p.PointTo(where, f.AsPanel()))
+		return true
+	}
+	p.MouseDragCallback = func(where Point, _ int, mod Modifiers) bool {
+		if f.scrubPending && (f.scrubbing || p.IsDragGesture(where)) {
+			f.scrub(p.PointTo(where, f.AsPanel()), mod)
+		}
+		return true
+	}
+	p.MouseUpCallback = func(_ Point, _ int, _ Modifiers) bool {
+		f.endScrub()
+		return true
+	}
+	p.UpdateCursorCallback = func(_ Point) *Cursor {
+		if f.Step > 0 && f.Enabled() {
+			return ResizeHorizontalCursor()
+		}
+		return ArrowCursor()
+	}
+}
+
+// DefaultDraw provides the default drawing.
+func (f *NumericField[T]) DefaultDraw(canvas *Canvas, dirty Rect) {
+	canvas.Save()
+	f.Field.DefaultDraw(canvas, dirty)
+	canvas.Restore()
+	if !f.steppersVisible {
+		return
+	}
+	r := f.stepperRect(0)
+	edge := f.StepperEdgeInk.Paint(canvas, r, paintstyle.Stroke)
+	canvas.DrawLine(r.X+0.5, r.Y, r.X+0.5, r.Bottom(), edge)
+	canvas.DrawLine(r.X, r.Y+r.Height/2, r.Right(), r.Y+r.Height/2, edge)
+	for _, dir := range []int{1, -1} {
+		button := f.stepperRect(dir)
+		ink := f.StepperInk
+		if f.stepperPressed == dir && f.stepperOver {
+			canvas.DrawRect(button, f.StepperPressedInk.Paint(canvas, button, paintstyle.Fill))
+			ink = f.OnStepperPressedInk
+		}
+		if !f.Enabled() || f.Step <= 0 {
+			ink = &ColorFilteredInk{
+				OriginalInk: ink,
+				ColorFilter: Grayscale30Filter(),
+			}
+		}
+		size := min(button.Width, button.Height) / 2
+		cx := button.X + button.Width/2
+		cy := button.Y + button.Height/2
+		path := NewPath()
+		if dir > 0 {
+			path.MoveTo(cx-size/2, cy+size/4)
+			path.LineTo(cx+size/2, cy+size/4)
+			path.LineTo(cx, cy-size/4)
+		} else {
+			path.MoveTo(cx-size/2, cy-size/4)
+			path.LineTo(cx+size/2, cy-size/4)
+			path.LineTo(cx, cy+size/4)
+		}
+		path.Close()
+		canvas.DrawPath(path, ink.Paint(canvas, button, paintstyle.Fill))
+	}
+}
+
+// DefaultMouseDown is the default implementation for the MouseDownCallback. While stepping is enabled and the field
+// does not have the focus, the press is held until the button is released, as it may start a scrub. If no scrubbing
+// took place, DefaultMouseUp() then replays the press, which gives the field the focus and positions the caret.
+func (f *NumericField[T]) DefaultMouseDown(where Point, button, clickCount int, mod Modifiers) bool {
+	if button == ButtonLeft && f.Step > 0 && f.Enabled() {
+		if f.steppersVisible {
+			for _, dir := range []int{1, -1} {
+				if f.stepperRect(dir).ContainsPoint(where) {
+					f.undoID = NextUndoID()
+					f.stepperPressed = dir
+					f.stepperOver = true
+					f.repeatGeneration++
+					f.StepValue(dir, mod)
+					generation := f.repeatGeneration
+					InvokeTaskAfter(func() { f.repeatStep(generation, dir, mod) }, f.AutoRepeatDelay)
+					f.MarkForRedraw()
+					return true
+				}
+			}
+		}
+		if !f.Focused() {
+			f.startScrub(where)
+			f.pendingWhere = where
+			f.pendingClickCount = clickCount
+			f.pendingMod = mod
+			return true
+		}
+	}
+	return f.Field.DefaultMouseDown(where, button, clickCount, mod)
+}
+
+// DefaultMouseDrag is the default implementation for the MouseDragCallback.
+func (f *NumericField[T]) DefaultMouseDrag(where Point, button int, mod Modifiers) bool {
+	switch {
+	case f.stepperPressed != 0:
+		if over := f.stepperRect(f.stepperPressed).ContainsPoint(where); over != f.stepperOver {
+			f.stepperOver = over
+			f.MarkForRedraw()
+		}
+	case f.scrubPending:
+		if f.scrubbing || f.IsDragGesture(where) {
+			f.scrub(where, mod)
+		}
+	default:
+		return f.Field.DefaultMouseDrag(where, button, mod)
+	}
+	return true
+}
+
+// DefaultMouseUp is the default implementation for the MouseUpCallback. See DefaultMouseDown() for how a press on a
+// field without the focus is handled.
+func (f *NumericField[T]) DefaultMouseUp(_ Point, button int, _ Modifiers) bool {
+	switch {
+	case f.stepperPressed != 0:
+		f.stepperPressed = 0
+		f.stepperOver = false
+		f.repeatGeneration++
+		f.undoID = NextUndoID()
+		f.MarkForRedraw()
+	case f.scrubPending:
+		if !f.endScrub() {
+			f.Field.DefaultMouseDown(f.pendingWhere, button, f.pendingClickCount, f.pendingMod)
+		}
+	}
+	return true
+}
+
+// DefaultMouseWheel is the default implementation for the MouseWheelCallback. The value is only stepped while the field
+// has the focus, so that scrolling past it doesn't alter it.
+func (f *NumericField[T]) DefaultMouseWheel(_, delta Point, mod Modifiers) bool {
+	if f.Step <= 0 || !f.Enabled() || !f.Focused() || delta.Y == 0 {
+		return false
+	}
+	if delta.Y > 0 {
+		f.StepValue(1, mod)
+	} else {
+		f.StepValue(-1, mod)
+	}
+	return true
+}
+
+// DefaultUpdateCursor is the default implementation for the UpdateCursorCallback.
+func (f *NumericField[T]) DefaultUpdateCursor(where Point) *Cursor {
+	if f.Step > 0 && f.Enabled() {
+		if f.steppersVisible && f.stepperRect(0).ContainsPoint(where) {
+			return ArrowCursor()
+		}
+		if !f.Focused() {
+			return ResizeHorizontalCursor()
+		}
+	}
+	return f.Field.DefaultUpdateCursor(where)
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *NumericField[T]) DefaultKeyDown(keyCode KeyCode, mod Modifiers, repeat bool) bool {
+	if f.Step > 0 && f.Enabled() && !mod.OSMenuCmdModifierDown() {
+		switch keyCode {
+		case KeyUp:
+			f.StepValue(1, mod)
+			return true
+		case KeyDown:
+			f.StepValue(-1, mod)
+			return true
+		case KeyPageUp:
+			f.StepValue(1, mod|ShiftModifier)
+			return true
+		case KeyPageDown:
+			f.StepValue(-1, mod|ShiftModifier)
+			return true
+		default:
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+}
+
+// stepperRect returns the area of the up stepper button for a positive dir, the down stepper button for a negative
+// dir, or both for zero.
+func (f *NumericField[T]) stepperRect(dir int) Rect {
+	content := f.ContentRect(false)
+	r := NewRect(content.Right(), content.Y, f.StepperWidth, content.Height)
+	switch {
+	case dir > 0:
+		r.Height /= 2
+	case dir < 0:
+		r.Height /= 2
+		r.Y += r.Height
+	}
+	return r
+}
+
+func (f *NumericField[T]) repeatStep(generation, dir int, mod Modifiers) {
+	if f.repeatGeneration != generation || f.stepperPressed != dir {
+		return
+	}
+	if f.stepperOver {
+		f.StepValue(dir, mod)
+	}
+	InvokeTaskAfter(func() { f.repeatStep(generation, dir, mod) }, f.AutoRepeatInterval)
+}
+
+func (f *NumericField[T]) startScrub(where Point) {
+	f.scrubPending = true
+	f.scrubbing = false
+	f.scrubStartX = where.X
+	f.scrubStartValue = float64(f.Value())
+}
+
+func (f *NumericField[T]) scrub(where Point, mod Modifiers) {
+	if !f.scrubbing {
+		f.scrubbing = true
+		f.undoID = NextUndoID()
+	}
+	steps := math.Round(float64((where.X - f.scrubStartX) / max(f.ScrubDistancePerStep, 1)))
+	f.setValueFrom(f.scrubStartValue + steps*f.stepAmount(mod))
+}
+
+// endScrub finishes a scrub operation, returning true if any scrubbing took place.
+func (f *NumericField[T]) endScrub() bool {
+	scrubbed := f.scrubbing
+	if scrubbed {
+		f.undoID = NextUndoID()
+	}
+	f.scrubPending = false
+	f.scrubbing = false
+	return scrubbed
+}
+
+func (f *NumericField[T]) stepAmount(mod Modifiers) float64 {
+	step := float64(f.Step)
+	switch {
+	case mod.ShiftDown():
+		step *= f.CoarseStepMultiplier
+	case mod.OptionDown():
+		step *= f.FineStepMultiplier
+	}
+	if isIntegral[T]() {
+		step = max(math.Round(step), 1)
+	}
+	return step
+}
+
+func (f *NumericField[T]) setValueFrom(value float64) {
+	if isIntegral[T]() {
+		value = math.Round(value)
+	}
+	f.SetValue(T(min(max(value, float64(f.minimum)), float64(f.maximum))))
+}
@@ -0,0 +1,100 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func newIntField(current, minimum, maximum int) *unison.NumericField[int] {
+	return unison.NewNumericField(current, minimum, maximum, strconv.Itoa, strconv.Atoi, nil)
+}
+
+func newFloatField(current, minimum, maximum float64) *unison.NumericField[float64] {
+	return unison.NewNumericField(current, minimum, maximum,
+		func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+		func(s string) (float64, error) { return strconv.ParseFloat(strings.TrimSpace(s), 64) }, nil)
+}
+
+func TestNumericFieldStepValue(t *testing.T) {
+	f := newIntField(5, 0, 100)
+	f.StepValue(1, 0)
+	check.Equal(t, 5, f.Value(), "stepping is disabled while Step is zero")
+
+	f.Step = 2
+	f.StepValue(1, 0)
+	check.Equal(t, 7, f.Value())
+	f.StepValue(-2, 0)
+	check.Equal(t, 3, f.Value())
+	f.StepValue(1, unison.ShiftModifier)
+	check.Equal(t, 23, f.Value(), "shift applies the coarse multiplier")
+	f.StepValue(1, unison.OptionModifier)
+	check.Equal(t, 24, f.Value(), "the fine step of an integer field is rounded, but never below 1")
+	f.FineStepMultiplier = 0.75
+	f.StepValue(1, unison.OptionModifier)
+	check.Equal(t, 26, f.Value(), "the fine step of an integer field is rounded to the nearest integer")
+	f.StepValue(10, unison.ShiftModifier)
+	check.Equal(t, 100, f.Value(), "stepping is clamped to the maximum")
+	f.StepValue(-1000, 0)
+	check.Equal(t, 0, f.Value(), "stepping is clamped to the minimum")
+	check.True(t, f.DefaultKeyDown(unison.KeyPageUp, 0, false))
+	check.Equal(t, 20, f.Value(), "page up uses the coarse step")
+	check.True(t, f.DefaultKeyDown(unison.KeyDown, 0, false))
+	check.Equal(t, 18, f.Value())
+
+	g := newFloatField(1, -10, 10)
+	g.Step = 0.5
+	g.StepValue(1, 0)
+	check.Equal(t, 1.5, g.Value())
+	g.StepValue(-1, unison.ShiftModifier)
+	check.Equal(t, -3.5, g.Value())
+	g.StepValue(1, unison.OptionModifier)
+	check.Equal(t, -3.45, g.Value())
+	g.StepValue(-3, unison.ShiftModifier)
+	check.Equal(t, float64(-10), g.Value())
+
+	g.SetText("2.25")
+	g.StepValue(1, 0)
+	check.Equal(t, 2.75, g.Value(), "fractional values of floating-point fields are not rounded")
+}
+
+func TestNumericFieldFocusOnRelease(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 200, Height: 40}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlowLayout{})
+	other := newIntField(0, 0, 10)
+	content.AddChild(other)
+	f := newIntField(5, 0, 10)
+	content.AddChild(f)
+	wnd.SetContent(content)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+	other.RequestFocus()
+	where := f.RectToRoot(f.ContentRect(false)).Center()
+
+	f.Step = 1
+	wnd.InjectMouseDown(where, unison.ButtonLeft, 0)
+	check.False(t, f.Focused(), "the press might start a scrub, so focus waits for the release")
+	wnd.InjectMouseUp(where, unison.ButtonLeft, 0)
+	check.True(t, f.Focused())
+
+	other.RequestFocus()
+	f.Step = 0
+	wnd.InjectMouseDown(where, unison.ButtonLeft, 0)
+	check.True(t, f.Focused(), "without stepping, the field gains the focus as soon as it is pressed")
+	wnd.InjectMouseUp(where, unison.ButtonLeft, 0)
+}
@@ -12,6 +12,7 @@ package unison
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/ddkwork/unison/enums/pathop"
 
@@ -20,7 +21,18 @@ import (
 	"github.com/ddkwork/toolbox/collection/slice"
 )
 
-var _ Paneler = &Panel{}
+// maxSizesCacheEntries is the maximum number of hints a panel will cache sizes for.
+const maxSizesCacheEntries = 8
+
+var (
+	_ Paneler = &Panel{}
+
+	// layoutPass identifies the current layout validation pass, or the current outermost call to Sizes() when outside
+	// of one. Cached sizes are only reused within the pass that computed them, since panels may change their content
+	// without notice between passes.
+	layoutPass      uint64
+	layoutPassDepth int
+)
 
 // Paneler is used to convert widgets into the base Panel type.
 type Paneler interface {
@@ -58,18 +70,30 @@ type Panel struct {
 	layout               Layout
 	layoutData           any
 	children             []*Panel
+	sizesCache           map[Size]panelSizes
+	sizesGeneration      uint64
 	canPerformMap        map[int]func(any) bool
 	performMap           map[int]func(any)
 	data                 map[string]any
 	RefKey               string
 	scale                float32
 	NeedsLayout          bool
+	sizesInvalid         bool
+	sizesInvalidBelow    bool
 	focusable            bool
 	disabled             bool
 	Hidden               bool
 	TooltipImmediate     bool
 }
 
+type panelSizes struct {
+	minSize    Size
+	prefSize   Size
+	maxSize    Size
+	pass       uint64
+	generation uint64
+}
+
 // NewPanel creates a new panel.
 func NewPanel() *Panel {
 	p := &Panel{}
@@ -137,7 +161,7 @@ func (p *Panel) AddChild(child Paneler) {
 	c.RemoveFromParent()
 	p.children = append(p.children, c)
 	c.parent = p
-	p.NeedsLayout = true
+	p.invalidateSizes()
 	if c.ParentChangedCallback != nil {
 		c.ParentChangedCallback()
 	}
@@ -159,7 +183,7 @@ func (p *Panel) AddChildAtIndex(child Paneler, index int) {
 		p.children[index] = c
 	}
 	c.parent = p
-	p.NeedsLayout = true
+	p.invalidateSizes()
 	if c.ParentChangedCallback != nil {
 		c.ParentChangedCallback()
 	}
@@ -172,7 +196,7 @@ func (p *Panel) RemoveAllChildren() {
 		child.parent = nil
 	}
 	p.children = nil
-	p.NeedsLayout = true
+	p.invalidateSizes()
 	for _, child := range children {
 		if child.ParentChangedCallback != nil {
 			child.ParentChangedCallback()
@@ -191,7 +215,7 @@ func (p *Panel) RemoveChildAtIndex(index int) {
 		child := p.children[index]
 		child.parent = nil
 		p.children = slice.ZeroedDelete(p.children, index, index+1)
-		p.NeedsLayout = true
+		p.invalidateSizes()
 		if child.ParentChangedCallback != nil {
 			child.ParentChangedCallback()
 		}
@@ -267,7 +291,7 @@ func (p *Panel) SetFrameRect(rect Rect) {
 		}
 		if resized {
 			p.frame.Size = rect.Size
-			p.NeedsLayout = true
+			p.invalidateSizes()
 		}
 		if p.FrameChangeCallback != nil {
 			p.FrameChangeCallback()
@@ -313,24 +337,31 @@ func (p *Panel) Sizer() Sizer {
 // SetSizer sets the sizer for this panel. May be nil.
 func (p *Panel) SetSizer(sizer Sizer) {
 	p.sizer = sizer
-	p.NeedsLayout = true
+	p.invalidateSizes()
 }
 
 // Sizes returns the minimum, preferred, and maximum sizes the panel wishes to be. It does this by first asking the
 // panel's layout. If no layout is present, then the panel's sizer is asked. If no sizer is present, then it finally
-// uses a default set of sizes that are used for all panels.
+// uses a default set of sizes that are used for all panels. Results are cached by hint for the duration of a layout
+// validation pass, or of the outermost call to Sizes() when made outside of one, until the panel is marked as needing
+// layout.
 func (p *Panel) Sizes(hint Size) (minSize, prefSize, maxSize Size) {
+	if layoutPassDepth == 0 {
+		layoutPass++
+		layoutPassDepth++
+		defer func() { layoutPassDepth-- }()
+	}
 	scale := p.Scale()
 	hint.Width /= scale
 	hint.Height /= scale
-	switch {
-	case p.layout != nil:
-		minSize, prefSize, maxSize = p.layout.LayoutSizes(p, hint)
-	case p.sizer != nil:
-		minSize, prefSize, maxSize = p.sizer(hint)
-	default:
+	if p.layout == nil && p.sizer == nil {
 		return minSize, prefSize, Size{Width: DefaultMaxSize, Height: DefaultMaxSize}
 	}
+	if cached, ok := p.sizesCache[hint]; ok && cached.pass == layoutPass && cached.generation == p.sizesGeneration {
+		minSize, prefSize, maxSize = cached.minSize, cached.prefSize, cached.maxSize
+	} else {
+		minSize, prefSize, maxSize = p.computeSizes(hint)
+	}
 	minSize.Width *= scale
 	minSize.Height *= scale
 	prefSize.Width *= scale
@@ -340,6 +371,31 @@ func (p *Panel) Sizes(hint Size) (minSize, prefSize, maxSize Size) {
 	return
 }
 
+// computeSizes asks the layout or sizer for the sizes and records them in the cache. The cache entries also serve as a
+// record of the sizes the panel's parent last saw, which is used to decide whether an invalidation needs to propagate
+// upward.
+func (p *Panel) computeSizes(hint Size) (minSize, prefSize, maxSize Size) {
+	if p.layout != nil {
+		minSize, prefSize, maxSize = p.layout.LayoutSizes(p, hint)
+	} else {
+		minSize, prefSize, maxSize = p.sizer(hint)
+	}
+	if _, exists := p.sizesCache[hint]; !exists && len(p.sizesCache) >= maxSizesCacheEntries {
+		p.sizesCache = nil
+	}
+	if p.sizesCache == nil {
+		p.sizesCache = make(map[Size]panelSizes)
+	}
+	p.sizesCache[hint] = panelSizes{
+		minSize:    minSize,
+		prefSize:   prefSize,
+		maxSize:    maxSize,
+		pass:       layoutPass,
+		generation: p.sizesGeneration,
+	}
+	return minSize, prefSize, maxSize
+}
+
 // Pack resizes the panel to its preferred size.
 func (p *Panel) Pack() {
 	_, pref, _ := p.Sizes(Size{})
@@ -354,23 +410,112 @@ func (p *Panel) Layout() Layout {
 // SetLayout sets the Layout for this panel. May be nil.
 func (p *Panel) SetLayout(lay Layout) {
 	p.layout = lay
-	p.NeedsLayout = true
+	p.invalidateSizes()
 }
 
-// ValidateLayout performs any layout that needs to be run by this panel or its children.
+// ValidateLayout performs any layout that needs to be run by this panel or its children. Panels that were marked with
+// InvalidateLayout() are re-measured first, with their ancestors being marked as needing layout only as far up as the
+// sizes actually changed.
 func (p *Panel) ValidateLayout() {
+	if layoutPassDepth == 0 {
+		layoutPass++
+		layoutProfiler = p.profiler()
+		defer func() { layoutProfiler = nil }()
+	}
+	layoutPassDepth++
+	defer func() { layoutPassDepth-- }()
+	p.resolveInvalidSizes(true)
+	p.validateLayout()
+}
+
+func (p *Panel) validateLayout() {
 	if p.NeedsLayout {
 		if p.layout != nil {
-			p.layout.PerformLayout(p)
+			if layoutProfiler != nil {
+				start := time.Now()
+				p.layout.PerformLayout(p)
+				layoutProfiler.panelLaidOut(p, time.Since(start))
+			} else {
+				p.layout.PerformLayout(p)
+			}
 			p.MarkForRedraw()
 		}
 		p.NeedsLayout = false
 	}
 	for _, child := range p.children {
-		child.ValidateLayout()
+		child.validateLayout()
 	}
 }
 
+// resolveInvalidSizes re-measures the panels within this subtree that were marked with InvalidateLayout(), deepest
+// first, so that each change propagates to a parent only when the sizes the parent last saw are different. Parents
+// within the subtree are resolved as the walk returns to them, so only a change to the sizes of the subtree's top
+// panel needs to mark the ancestors above it for the next walk.
+func (p *Panel) resolveInvalidSizes(top bool) {
+	if p.sizesInvalidBelow {
+		p.sizesInvalidBelow = false
+		for _, child := range p.children {
+			child.resolveInvalidSizes(false)
+		}
+	}
+	if p.sizesInvalid {
+		p.sizesInvalid = false
+		if p.parent != nil && p.sizesChanged() {
+			if top {
+				p.parent.InvalidateLayout()
+			} else {
+				p.parent.markSizesInvalid()
+			}
+		}
+	}
+}
+
+// sizesChanged re-measures the panel for each hint in its cache, returning true if any of the results differ. A panel
+// that has no cached sizes is considered to have changed, since nothing is known about what its parent last saw.
+func (p *Panel) sizesChanged() bool {
+	if p.layout == nil && p.sizer == nil {
+		return false
+	}
+	if len(p.sizesCache) == 0 {
+		return true
+	}
+	previous := p.sizesCache
+	p.sizesCache = nil
+	changed := false
+	for hint, old := range previous {
+		minSize, prefSize, maxSize := p.computeSizes(hint)
+		if minSize != old.minSize || prefSize != old.prefSize || maxSize != old.maxSize {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// InvalidateLayout marks this panel as needing to be laid out because something affecting its sizes, such as its text,
+// has changed. At the next layout validation, the panel is re-measured and, only if its sizes differ from those its
+// parent last saw, the parent is invalidated in turn. This stops at the first ancestor whose sizes did not change,
+// making it much cheaper than MarkForLayoutRecursivelyUpward() in large panel hierarchies.
+func (p *Panel) InvalidateLayout() {
+	p.markSizesInvalid()
+	for parent := p.parent; parent != nil && !parent.sizesInvalidBelow; parent = parent.parent {
+		parent.sizesInvalidBelow = true
+	}
+}
+
+// markSizesInvalid marks the panel for re-measuring at the next layout validation, without marking its ancestors.
+func (p *Panel) markSizesInvalid() {
+	p.invalidateSizes()
+	p.sizesInvalid = true
+	p.MarkForRedraw()
+}
+
+// invalidateSizes marks the panel as needing to be laid out and prevents its cached sizes from being reused. The cached
+// entries are kept, since they also record the sizes the panel's parent last saw.
+func (p *Panel) invalidateSizes() {
+	p.NeedsLayout = true
+	p.sizesGeneration++
+}
+
 // LayoutData returns the layout data, if any, associated with this panel.
 func (p *Panel) LayoutData() any {
 	return p.layoutData
@@ -379,12 +524,12 @@ func (p *Panel) LayoutData() any {
 // SetLayoutData sets layout data on this panel. May be nil.
 func (p *Panel) SetLayoutData(data any) {
 	p.layoutData = data
-	p.NeedsLayout = true
+	p.invalidateSizes()
 }
 
 // MarkForLayoutRecursively marks this panel and all of its descendents as needing to be laid out.
 func (p *Panel) MarkForLayoutRecursively() {
-	p.NeedsLayout = true
+	p.invalidateSizes()
 	for _, child := range p.Children() {
 		child.MarkForLayoutRecursively()
 	}
@@ -394,14 +539,14 @@ func (p *Panel) MarkForLayoutRecursively() {
 func (p *Panel) MarkForLayoutRecursivelyUpward() {
 	one := p
 	for one != nil {
-		one.NeedsLayout = true
+		one.invalidateSizes()
 		one = one.Parent()
 	}
 }
 
 // MarkForLayoutAndRedraw marks this panel as needing to be laid out as well as redrawn at the next update.
 func (p *Panel) MarkForLayoutAndRedraw() {
-	p.NeedsLayout = true
+	p.invalidateSizes()
 	p.MarkForRedraw()
 }
 
@@ -426,6 +571,10 @@ func (p *Panel) Draw(gc *Canvas, rect Rect) {
 	if p.Hidden {
 		return
 	}
+	if drawProfiler != nil {
+		drawProfiler.beginPanelDraw()
+		defer drawProfiler.endPanelDraw(p)
+	}
 	rect.Intersect(p.frame.CopyAndZeroLocation())
 	if !rect.IsEmpty() {
 		gc.Save()
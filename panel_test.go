@@ -0,0 +1,123 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/align"
+)
+
+func TestInvalidateLayout(t *testing.T) {
+	root := unison.NewPanel()
+	root.SetLayout(&unison.FlexLayout{Columns: 1})
+	middle := unison.NewPanel()
+	middle.SetLayout(&unison.StackLayout{})
+	root.AddChild(middle)
+	leafWidth := float32(50)
+	leaf := unison.NewPanel()
+	leaf.SetSizer(func(_ unison.Size) (minSize, prefSize, maxSize unison.Size) {
+		s := unison.Size{Width: leafWidth, Height: 20}
+		return s, s, s
+	})
+	middle.AddChild(leaf)
+	siblingCount := 0
+	sibling := unison.NewPanel()
+	sibling.SetSizer(func(_ unison.Size) (minSize, prefSize, maxSize unison.Size) {
+		siblingCount++
+		s := unison.Size{Width: 30, Height: 20}
+		return s, s, s
+	})
+	root.AddChild(sibling)
+	root.SetFrameRect(unison.Rect{Size: unison.Size{Width: 200, Height: 100}})
+	root.ValidateLayout()
+	check.Equal(t, float32(50), leaf.FrameRect().Width)
+
+	// Nothing actually changed, so only the leaf should be laid out again.
+	count := siblingCount
+	leaf.InvalidateLayout()
+	root.ValidateLayout()
+	check.Equal(t, count, siblingCount)
+	check.False(t, root.NeedsLayout)
+
+	// The leaf grew, so its ancestors must be laid out again.
+	leafWidth = 80
+	leaf.InvalidateLayout()
+	root.ValidateLayout()
+	check.True(t, siblingCount > count)
+	check.Equal(t, float32(80), middle.FrameRect().Width)
+	check.Equal(t, float32(80), leaf.FrameRect().Width)
+}
+
+type remeasureLayout struct {
+	child    *unison.Panel
+	setWidth func(width float32)
+	widths   []float32
+}
+
+func (l *remeasureLayout) LayoutSizes(_ *unison.Panel, _ unison.Size) (minSize, prefSize, maxSize unison.Size) {
+	_, prefSize, _ = l.child.Sizes(unison.Size{})
+	return prefSize, prefSize, prefSize
+}
+
+func (l *remeasureLayout) PerformLayout(_ *unison.Panel) {
+	_, pref, _ := l.child.Sizes(unison.Size{})
+	l.widths = append(l.widths, pref.Width)
+	l.setWidth(90)
+	l.child.MarkForLayoutAndRedraw()
+	_, pref, _ = l.child.Sizes(unison.Size{})
+	l.widths = append(l.widths, pref.Width)
+}
+
+func TestSizesCacheDroppedWhenMarkedForLayout(t *testing.T) {
+	root := unison.NewPanel()
+	width := float32(40)
+	child := newSizedPanel(root, unison.Size{}, unison.Size{}, unison.Size{})
+	child.SetSizer(func(_ unison.Size) (minSize, prefSize, maxSize unison.Size) {
+		s := unison.Size{Width: width, Height: 20}
+		return s, s, s
+	})
+	layout := &remeasureLayout{child: child, setWidth: func(w float32) { width = w }}
+	root.SetLayout(layout)
+	root.SetFrameRect(unison.Rect{Size: unison.Size{Width: 200, Height: 100}})
+	root.ValidateLayout()
+	check.Equal(t, []float32{40, 90}, layout.widths, "a panel re-measured after being marked must not use stale sizes")
+}
+
+func TestFlexLayoutMeasuresEachHintOnce(t *testing.T) {
+	root := unison.NewPanel()
+	root.SetLayout(&unison.FlexLayout{Columns: 2})
+	var hints []unison.Size
+	child := newSizedPanel(root, unison.Size{}, unison.Size{}, unison.Size{})
+	child.SetSizer(func(hint unison.Size) (minSize, prefSize, maxSize unison.Size) {
+		hints = append(hints, hint)
+		s := unison.Size{Width: 50, Height: 20}
+		return s, s, s
+	})
+	child.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Fill, HGrab: true})
+	newLayoutChild(root, nil)
+	root.Sizes(unison.Size{})
+	check.True(t, len(hints) > 0)
+	seen := make(map[unison.Size]bool)
+	for _, hint := range hints {
+		check.False(t, seen[hint], "sizes for hint %v were computed more than once", hint)
+		seen[hint] = true
+	}
+
+	hints = nil
+	root.SetFrameRect(unison.Rect{Size: unison.Size{Width: 300, Height: 100}})
+	root.ValidateLayout()
+	count := len(hints)
+	check.True(t, count > 0)
+	root.Sizes(unison.Size{})
+	check.True(t, len(hints) > count, "each outermost call to Sizes measures afresh")
+}
@@ -0,0 +1,297 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ddkwork/unison/enums/paintstyle"
+)
+
+// DefaultProfilerHistory is the number of frames a Profiler created with a history size of zero or less retains.
+const DefaultProfilerHistory = 120
+
+var (
+	// drawProfiler is the profiler of the window currently being drawn, if any.
+	drawProfiler *Profiler
+	// layoutProfiler is the profiler of the window containing the panel the current layout pass was started from, if
+	// any. Looked up once per pass, rather than once per panel laid out.
+	layoutProfiler *Profiler
+)
+
+// FrameStats holds the statistics collected for a single frame.
+type FrameStats struct {
+	// Time is when the frame started drawing.
+	Time time.Time
+	// DrawDuration is the time spent drawing the frame's panels.
+	DrawDuration time.Duration
+	// LayoutDuration is the time spent in Layout.PerformLayout() since the previous frame, including any layout done as
+	// part of drawing this frame.
+	LayoutDuration time.Duration
+	// PanelDraws is the number of panels drawn in the frame.
+	PanelDraws int
+	// Layouts is the number of calls to Layout.PerformLayout() since the previous frame.
+	Layouts int
+}
+
+// PanelStats holds the statistics collected for a single panel since the profiler was last reset.
+type PanelStats struct {
+	Panel *Panel
+	// Draws is the number of times the panel has been drawn.
+	Draws int
+	// DrawDuration is the total time spent drawing the panel, including its children.
+	DrawDuration time.Duration
+	// SelfDrawDuration is the total time spent drawing the panel, excluding its children.
+	SelfDrawDuration time.Duration
+	// MaxDrawDuration is the longest time a single draw of the panel took, including its children.
+	MaxDrawDuration time.Duration
+	// Layouts is the number of times the panel's layout has been performed.
+	Layouts int
+	// LayoutDuration is the total time spent in Layout.PerformLayout() for the panel.
+	LayoutDuration time.Duration
+	lastFrame      int
+}
+
+// Cost returns the time attributable to the panel itself: its own drawing, excluding children, plus its layout.
+func (s *PanelStats) Cost() time.Duration {
+	return s.SelfDrawDuration + s.LayoutDuration
+}
+
+type profilerDrawEntry struct {
+	start    time.Time
+	children time.Duration
+}
+
+// Profiler collects drawing and layout statistics for a window. Install one with Window.SetProfiler(). Statistics for
+// panels that have not been drawn or laid out within the retained frame history are discarded, so that panels which are
+// created on the fly, such as table cells, don't accumulate without bound.
+type Profiler struct {
+	// ShowOverlay causes a frame time graph and the slowest panels to be drawn over the window's content.
+	ShowOverlay bool
+	// FrameBudget is the frame time the overlay's graph is scaled against. Defaults to the time between frames at
+	// DefaultAnimationFrameRate.
+	FrameBudget time.Duration
+	frames      []FrameStats
+	panels      map[*Panel]*PanelStats
+	stack       []profilerDrawEntry
+	current     FrameStats
+	frameCount  int
+	history     int
+}
+
+// NewProfiler creates a new Profiler that retains the given number of frames of history.
+func NewProfiler(history int) *Profiler {
+	if history <= 0 {
+		history = DefaultProfilerHistory
+	}
+	return &Profiler{
+		FrameBudget: time.Second / DefaultAnimationFrameRate,
+		panels:      make(map[*Panel]*PanelStats),
+		history:     history,
+	}
+}
+
+// Profiler returns the profiler installed on the window, if any.
+func (w *Window) Profiler() *Profiler {
+	return w.profiler
+}
+
+// SetProfiler installs a profiler on the window. Pass nil to stop profiling.
+func (w *Window) SetProfiler(profiler *Profiler) {
+	if w.profiler != profiler {
+		w.profiler = profiler
+		w.MarkForRedraw()
+	}
+}
+
+func (p *Panel) profiler() *Profiler {
+	if w := p.Window(); w != nil {
+		return w.profiler
+	}
+	return nil
+}
+
+// Reset discards all collected statistics.
+func (p *Profiler) Reset() {
+	p.frames = nil
+	p.panels = make(map[*Panel]*PanelStats)
+	p.current = FrameStats{}
+}
+
+// Frames returns the statistics for the retained frames, oldest first.
+func (p *Profiler) Frames() []FrameStats {
+	return slices.Clone(p.frames)
+}
+
+// LastFrame returns the statistics for the most recent frame.
+func (p *Profiler) LastFrame() FrameStats {
+	if len(p.frames) == 0 {
+		return FrameStats{}
+	}
+	return p.frames[len(p.frames)-1]
+}
+
+// PanelStats returns the statistics collected for the panel, or nil if there are none.
+func (p *Profiler) PanelStats(panel *Panel) *PanelStats {
+	if stats, ok := p.panels[panel]; ok {
+		clone := *stats
+		return &clone
+	}
+	return nil
+}
+
+// SlowestPanels returns up to max panel statistics, ordered from highest Cost() to lowest.
+func (p *Profiler) SlowestPanels(maximum int) []*PanelStats {
+	list := make([]*PanelStats, 0, len(p.panels))
+	for _, stats := range p.panels {
+		clone := *stats
+		list = append(list, &clone)
+	}
+	slices.SortFunc(list, func(a, b *PanelStats) int {
+		if a.Cost() > b.Cost() {
+			return -1
+		}
+		if a.Cost() < b.Cost() {
+			return 1
+		}
+		return 0
+	})
+	if maximum >= 0 && len(list) > maximum {
+		list = list[:maximum]
+	}
+	return list
+}
+
+func (p *Profiler) stats(panel *Panel) *PanelStats {
+	stats, ok := p.panels[panel]
+	if !ok {
+		stats = &PanelStats{Panel: panel}
+		p.panels[panel] = stats
+	}
+	stats.lastFrame = p.frameCount
+	return stats
+}
+
+func (p *Profiler) beginFrame() {
+	p.current.Time = time.Now()
+	p.stack = p.stack[:0]
+}
+
+func (p *Profiler) endFrame() {
+	p.current.DrawDuration = time.Since(p.current.Time)
+	p.frames = append(p.frames, p.current)
+	if len(p.frames) > p.history {
+		p.frames = slices.Delete(p.frames, 0, len(p.frames)-p.history)
+	}
+	p.current = FrameStats{}
+	p.frameCount++
+	for panel, stats := range p.panels {
+		if p.frameCount-stats.lastFrame > p.history {
+			delete(p.panels, panel)
+		}
+	}
+}
+
+func (p *Profiler) beginPanelDraw() {
+	p.stack = append(p.stack, profilerDrawEntry{start: time.Now()})
+}
+
+func (p *Profiler) endPanelDraw(panel *Panel) {
+	if len(p.stack) == 0 {
+		return
+	}
+	entry := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	elapsed := time.Since(entry.start)
+	if len(p.stack) != 0 {
+		p.stack[len(p.stack)-1].children += elapsed
+	}
+	stats := p.stats(panel)
+	stats.Draws++
+	stats.DrawDuration += elapsed
+	stats.SelfDrawDuration += elapsed - entry.children
+	stats.MaxDrawDuration = max(stats.MaxDrawDuration, elapsed)
+	p.current.PanelDraws++
+}
+
+func (p *Profiler) panelLaidOut(panel *Panel, elapsed time.Duration) {
+	stats := p.stats(panel)
+	stats.Layouts++
+	stats.LayoutDuration += elapsed
+	p.current.Layouts++
+	p.current.LayoutDuration += elapsed
+}
+
+func (p *Profiler) drawOverlay(gc *Canvas, bounds Rect) {
+	const (
+		margin      = 6
+		graphHeight = 60
+		maxPanels   = 8
+	)
+	font := MonospacedFont
+	lineHeight := font.LineHeight()
+	slowest := p.SlowestPanels(maxPanels)
+	last := p.LastFrame()
+	lines := make([]string, 0, len(slowest)+2)
+	lines = append(lines, fmt.Sprintf("frame %v, %d draws, %d layouts (%v)", last.DrawDuration.Round(time.Microsecond),
+		last.PanelDraws, last.Layouts, last.LayoutDuration.Round(time.Microsecond)))
+	for _, stats := range slowest {
+		name := stats.Panel.String()
+		if stats.Panel.RefKey != "" {
+			name += " [" + stats.Panel.RefKey + "]"
+		}
+		lines = append(lines, fmt.Sprintf("%8v %s ×%d", stats.Cost().Round(time.Microsecond), name, stats.Draws))
+	}
+	var width float32
+	for _, line := range lines {
+		width = max(width, font.SimpleWidth(line))
+	}
+	width = max(width, float32(p.history)*2)
+	box := Rect{Size: Size{
+		Width:  width + margin*2,
+		Height: graphHeight + lineHeight*float32(len(lines)) + margin*3,
+	}}
+	box.X = bounds.Right() - (box.Width + margin)
+	box.Y = bounds.Y + margin
+	gc.DrawRect(box, ARGB(0.85, 0, 0, 0).Paint(gc, box, paintstyle.Fill))
+
+	// Frame time graph, with a line marking the frame budget at half height
+	graph := Rect{Point: Point{X: box.X + margin, Y: box.Y + margin}, Size: Size{Width: width, Height: graphHeight}}
+	budget := p.FrameBudget
+	if budget <= 0 {
+		budget = time.Second / DefaultAnimationFrameRate
+	}
+	barWidth := graph.Width / float32(p.history)
+	for i, frame := range p.frames {
+		h := min(graphHeight*float32(frame.DrawDuration)/float32(2*budget), graphHeight)
+		bar := Rect{
+			Point: Point{X: graph.X + float32(p.history-len(p.frames)+i)*barWidth, Y: graph.Bottom() - h},
+			Size:  Size{Width: max(barWidth-1, 1), Height: h},
+		}
+		c := RGB(0, 200, 0)
+		if frame.DrawDuration > budget {
+			c = RGB(230, 40, 40)
+		}
+		gc.DrawRect(bar, c.Paint(gc, bar, paintstyle.Fill))
+	}
+	paint := ARGB(0.6, 255, 255, 255).Paint(gc, graph, paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	y := graph.Y + graphHeight/2
+	gc.DrawLine(graph.X, y, graph.Right(), y, paint)
+
+	textPaint := RGB(255, 255, 255).Paint(gc, box, paintstyle.Fill)
+	y = graph.Bottom() + margin + font.Baseline()
+	for _, line := range lines {
+		gc.DrawSimpleString(line, graph.X, y, font, textPaint)
+		y += lineHeight
+	}
+}
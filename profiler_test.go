@@ -0,0 +1,42 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestProfilerLayouts(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 100, Height: 100}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	prof := unison.NewProfiler(0)
+	wnd.SetProfiler(prof)
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlowLayout{})
+	child := unison.NewPanel()
+	child.SetLayout(&unison.FlowLayout{})
+	content.AddChild(child)
+	wnd.SetContent(content)
+	wnd.ValidateLayout()
+	check.Equal(t, 1, prof.PanelStats(child).Layouts)
+
+	child.MarkForLayoutAndRedraw()
+	child.ValidateLayout()
+	check.Equal(t, 2, prof.PanelStats(child).Layouts, "passes started below the root are profiled too")
+
+	detached := unison.NewPanel()
+	detached.SetLayout(&unison.FlowLayout{})
+	detached.ValidateLayout()
+	check.Nil(t, prof.PanelStats(detached), "panels outside the window are not profiled")
+}
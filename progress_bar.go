@@ -31,9 +31,10 @@ var DefaultProgressBarTheme = ProgressBarTheme{
 
 // ProgressBarTheme holds theming data for a ProgressBar.
 type ProgressBarTheme struct {
-	BackgroundInk      Ink
-	FillInk            Ink
-	EdgeInk            Ink
+	BackgroundInk Ink
+	FillInk       Ink
+	EdgeInk       Ink
+	// Deprecated: Indeterminate progress bars are now animated at the display's refresh rate. This field is ignored.
 	TickSpeed          time.Duration
 	FullTraversalSpeed time.Duration
 	PreferredBarHeight float32
@@ -46,9 +47,13 @@ type ProgressBarTheme struct {
 type ProgressBar struct {
 	Panel
 	ProgressBarTheme
-	current           float32
-	maximum           float32
-	lastAnimationTime time.Time
+	animation             *AnimationHandle
+	frameWnd              *Window
+	frameID               FrameCallbackID
+	current               float32
+	maximum               float32
+	indeterminatePosition float32
+	indeterminateElapsed  time.Duration
 }
 
 // NewProgressBar creates a new progress bar. A max of zero will create an indeterminate progress bar, i.e. one whose
@@ -61,6 +66,8 @@ func NewProgressBar(maximum float32) *ProgressBar {
 	p.Self = p
 	p.SetSizer(p.DefaultSizes)
 	p.DrawCallback = p.DefaultDraw
+	p.ParentChangedCallback = p.updateIndeterminateAnimation
+	p.FrameChangeCallback = p.updateIndeterminateAnimation
 	return p
 }
 
@@ -94,12 +101,10 @@ func (p *ProgressBar) SetMaximum(value float32) {
 	}
 	if p.maximum != value {
 		p.maximum = value
-		if p.maximum == 0 {
-			p.lastAnimationTime = time.Time{}
-		}
 		if p.current > p.maximum {
 			p.current = p.maximum
 		}
+		p.updateIndeterminateAnimation()
 		p.MarkForRedraw()
 	}
 }
@@ -132,16 +137,7 @@ func (p *ProgressBar) DefaultDraw(canvas *Canvas, _ Rect) {
 	meter.Width = 0
 	if p.maximum <= 0 {
 		meter.Width = p.IndeterminateWidth
-		if p.lastAnimationTime.IsZero() {
-			p.lastAnimationTime = time.Now()
-		} else {
-			maximum := bounds.Width - p.IndeterminateWidth
-			elapsed := time.Since(p.lastAnimationTime) % (2 * p.FullTraversalSpeed)
-			if elapsed >= p.FullTraversalSpeed {
-				elapsed = p.FullTraversalSpeed - (elapsed - p.FullTraversalSpeed)
-			}
-			meter.X = maximum * float32(elapsed) / float32(p.FullTraversalSpeed)
-		}
+		meter.X = (bounds.Width - p.IndeterminateWidth) * p.indeterminatePosition
 	} else if p.current > 0 {
 		meter.Width = bounds.Width * (p.current / p.maximum)
 	}
@@ -160,7 +156,71 @@ func (p *ProgressBar) DefaultDraw(canvas *Canvas, _ Rect) {
 		meter.InsetUniform(p.EdgeThickness / 2)
 		canvas.DrawRoundedRect(meter, p.CornerRadius, p.CornerRadius, paint)
 	}
-	if p.maximum == 0 {
-		InvokeTaskAfter(p.MarkForRedraw, p.TickSpeed)
+}
+
+// updateIndeterminateAnimation starts or stops the animation of the meter to match the state of the progress bar. While
+// the bar is indeterminate but not showing, it checks again before each frame of its window, without causing any frames
+// to be drawn by itself.
+func (p *ProgressBar) updateIndeterminateAnimation() {
+	if p.frameWnd != nil {
+		p.frameWnd.CancelAnimationFrame(p.frameID)
+		p.frameWnd = nil
+	}
+	if p.maximum <= 0 && p.showing() {
+		if p.animation == nil || !p.animation.Running() {
+			tween := NewTween(0, 1, p.FullTraversalSpeed, EaseLinear, func(value float32) { p.indeterminatePosition = value })
+			tween.Repeat = -1
+			tween.AutoReverse = true
+			p.animation = Animate(p, &indeterminateAnimation{bar: p, tween: tween, offset: p.indeterminateElapsed},
+				func(finished bool) {
+					// The animation only finishes on its own once the bar stops showing
+					if finished {
+						p.updateIndeterminateAnimation()
+					}
+				})
+		}
+		return
+	}
+	if p.animation != nil {
+		p.animation.Stop()
+		p.animation = nil
+	}
+	if w := p.Window(); w != nil && p.maximum <= 0 {
+		p.frameWnd = w
+		p.frameID = w.requestAnimationFrame(p.AsPanel(), func(_ time.Time) {
+			p.frameWnd = nil
+			p.updateIndeterminateAnimation()
+		}, false)
+	}
+}
+
+// showing returns true if the progress bar is in a window and neither it nor any of its ancestors are hidden.
+func (p *ProgressBar) showing() bool {
+	if p.Window() == nil {
+		return false
+	}
+	for panel := p.AsPanel(); panel != nil; panel = panel.parent {
+		if panel.Hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// indeterminateAnimation moves the meter of an indeterminate progress bar back and forth. It ends as soon as the bar is
+// no longer showing, so that it doesn't keep the window redrawing, and resumes from where it left off once the bar is
+// showing again.
+type indeterminateAnimation struct {
+	bar    *ProgressBar
+	tween  *Tween[float32]
+	offset time.Duration
+}
+
+// Advance implements Animation.
+func (a *indeterminateAnimation) Advance(elapsed time.Duration) bool {
+	if !a.bar.showing() {
+		return true
 	}
+	a.bar.indeterminateElapsed = a.offset + elapsed
+	return a.tween.Advance(a.bar.indeterminateElapsed)
 }
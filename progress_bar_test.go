@@ -0,0 +1,54 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+)
+
+func progressBarAnimating(p *ProgressBar) bool {
+	return p.animation != nil && p.animation.Running()
+}
+
+func TestProgressBarIndeterminateAnimation(t *testing.T) {
+	wnd, err := NewWindow("", OffscreenWindowOption(Size{Width: 200, Height: 50}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	bar := NewProgressBar(0)
+	check.False(t, progressBarAnimating(bar), "a bar that isn't in a window must not animate")
+
+	wnd.Content().AddChild(bar)
+	check.True(t, progressBarAnimating(bar))
+	bar.SetMaximum(10)
+	check.False(t, progressBarAnimating(bar))
+	_, err = wnd.Snapshot()
+	check.NoError(t, err)
+	check.False(t, progressBarAnimating(bar), "drawing must not start the animation")
+	bar.SetMaximum(0)
+	check.True(t, progressBarAnimating(bar))
+
+	bar.Hidden = true
+	advanceAnimations(wnd, time.Now())
+	check.False(t, progressBarAnimating(bar), "a hidden bar must not animate")
+	check.True(t, bar.frameWnd == wnd, "a hidden bar must check again before the next frame")
+	wnd.runFrameCallbacks()
+	check.False(t, progressBarAnimating(bar))
+	bar.Hidden = false
+	wnd.runFrameCallbacks()
+	check.True(t, progressBarAnimating(bar), "the animation must resume once the bar is showing again")
+
+	bar.RemoveFromParent()
+	check.False(t, progressBarAnimating(bar), "a bar removed from its window must stop animating")
+	wnd.Content().AddChild(bar)
+	check.True(t, progressBarAnimating(bar))
+}
@@ -0,0 +1,163 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+	"slices"
+)
+
+var _ Layout = &ResponsiveLayout{}
+
+// Breakpoint holds a layout to be used by a ResponsiveLayout when enough space is available.
+type Breakpoint struct {
+	// Name identifies the breakpoint within the layout data of children. See ResponsiveLayoutData.
+	Name string
+	// MinWidth is the minimum content width at which this breakpoint applies.
+	MinWidth float32
+	// MinHeight is the minimum content height at which this breakpoint applies.
+	MinHeight float32
+	// Layout is the layout to use while this breakpoint is active, such as a *FlexLayout or a *FlowLayout.
+	Layout Layout
+}
+
+// ResponsiveLayoutData is used as the layout data of a child of a panel using a ResponsiveLayout, allowing the child's
+// layout data and visibility to change along with the active breakpoint.
+type ResponsiveLayoutData struct {
+	// Data holds the layout data to use for the child, keyed by breakpoint name. For example, a *FlexLayoutData for a
+	// FlexLayout or an align.Enum for a FlowLayout.
+	Data map[string]any
+	// Default is the layout data to use when Data has no entry for the active breakpoint.
+	Default any
+	// HiddenIn holds the names of the breakpoints in which the child should be hidden. Hidden children are not given to
+	// the breakpoint's layout and so take up no space. If this is not empty, the ResponsiveLayout takes ownership of the
+	// child's Hidden field.
+	HiddenIn []string
+}
+
+// ResponsiveLayout is a Layout that switches between the layouts of its breakpoints based on the space available to the
+// target, allowing it to adapt as it is resized rather than clipping its content. For example, a form might use a
+// two-column FlexLayout with labels beside their fields when wide and a single-column one with labels above their
+// fields when narrow.
+//
+// The breakpoints are checked in order, and the first one whose minimums are met by the content size is used, so they
+// should be listed from largest to smallest. If none match, the last one is used. When sizing without a hint, the
+// first breakpoint determines the preferred size, while the minimum size is the smallest minimum of all breakpoints,
+// so that containers such as a Dock may shrink the target down to its narrowest arrangement.
+type ResponsiveLayout struct {
+	Breakpoints []*Breakpoint
+	current     *Breakpoint
+}
+
+// Current returns the breakpoint that was used for the most recent layout, or nil if no layout has been performed yet.
+func (r *ResponsiveLayout) Current() *Breakpoint {
+	return r.current
+}
+
+// BreakpointFor returns the breakpoint that applies to the given content size.
+func (r *ResponsiveLayout) BreakpointFor(contentSize Size) *Breakpoint {
+	for _, bp := range r.Breakpoints {
+		if contentSize.Width >= bp.MinWidth && contentSize.Height >= bp.MinHeight {
+			return bp
+		}
+	}
+	if len(r.Breakpoints) != 0 {
+		return r.Breakpoints[len(r.Breakpoints)-1]
+	}
+	return nil
+}
+
+// LayoutSizes implements Layout.
+func (r *ResponsiveLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, maxSize Size) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	avail := Size{Width: math.MaxFloat32, Height: math.MaxFloat32}
+	if hint.Width > 0 {
+		avail.Width = max(hint.Width-insets.Width(), 0)
+	}
+	if hint.Height > 0 {
+		avail.Height = max(hint.Height-insets.Height(), 0)
+	}
+	active := r.BreakpointFor(avail)
+	if active == nil {
+		return minSize, prefSize, MaxSize(prefSize)
+	}
+	first := true
+	for _, bp := range r.Breakpoints {
+		r.withBreakpoint(target, bp, func() {
+			bpMin, bpPref, bpMax := bp.Layout.LayoutSizes(target, hint)
+			if first {
+				minSize = bpMin
+				first = false
+			} else {
+				minSize.Width = min(minSize.Width, bpMin.Width)
+				minSize.Height = min(minSize.Height, bpMin.Height)
+			}
+			if bp == active {
+				prefSize = bpPref
+				maxSize = bpMax
+			}
+		})
+	}
+	return minSize, prefSize, maxSize
+}
+
+// PerformLayout implements Layout.
+func (r *ResponsiveLayout) PerformLayout(target *Panel) {
+	size := target.ContentRect(false).Size
+	bp := r.BreakpointFor(size)
+	r.current = bp
+	if bp == nil {
+		return
+	}
+	for _, child := range target.Children() {
+		if data, ok := child.LayoutData().(*ResponsiveLayoutData); ok && data != nil && len(data.HiddenIn) != 0 {
+			child.Hidden = slices.Contains(data.HiddenIn, bp.Name)
+		}
+	}
+	r.withBreakpoint(target, bp, func() { bp.Layout.PerformLayout(target) })
+}
+
+// withBreakpoint calls f with the target's children temporarily reduced to those visible in the breakpoint and their
+// layout data temporarily replaced by the data for the breakpoint.
+func (r *ResponsiveLayout) withBreakpoint(target *Panel, bp *Breakpoint, f func()) {
+	saved := target.children
+	visible := make([]*Panel, 0, len(saved))
+	var swapped []*Panel
+	var original []*ResponsiveLayoutData
+	for _, child := range saved {
+		data, ok := child.layoutData.(*ResponsiveLayoutData)
+		if !ok || data == nil {
+			visible = append(visible, child)
+			continue
+		}
+		if slices.Contains(data.HiddenIn, bp.Name) {
+			continue
+		}
+		visible = append(visible, child)
+		swapped = append(swapped, child)
+		original = append(original, data)
+		if d, exists := data.Data[bp.Name]; exists {
+			child.layoutData = d
+		} else {
+			child.layoutData = data.Default
+		}
+	}
+	defer func() {
+		target.children = saved
+		for i, child := range swapped {
+			child.layoutData = original[i]
+		}
+	}()
+	target.children = visible
+	f()
+}
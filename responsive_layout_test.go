@@ -0,0 +1,53 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestResponsiveLayout(t *testing.T) {
+	parent := unison.NewPanel()
+	layout := &unison.ResponsiveLayout{
+		Breakpoints: []*unison.Breakpoint{
+			{Name: "wide", MinWidth: 150, Layout: &unison.FlexLayout{Columns: 3}},
+			{Name: "narrow", Layout: &unison.FlexLayout{Columns: 1}},
+		},
+	}
+	parent.SetLayout(layout)
+	size := unison.Size{Width: 50, Height: 20}
+	for range 3 {
+		newSizedPanel(parent, size, size, size)
+	}
+	children := parent.Children()
+	children[2].SetLayoutData(&unison.ResponsiveLayoutData{HiddenIn: []string{"narrow"}})
+
+	minSize, prefSize, _ := parent.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 50, Height: 20}, minSize)
+	check.Equal(t, unison.Size{Width: 150, Height: 20}, prefSize)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 150, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, "wide", layout.Current().Name)
+	check.Equal(t, unison.Point{X: 100}, children[2].FrameRect().Point)
+	check.False(t, children[2].Hidden)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 100, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, "narrow", layout.Current().Name)
+	check.Equal(t, unison.Point{Y: 20}, children[1].FrameRect().Point)
+	check.True(t, children[2].Hidden)
+	_, ok := children[2].LayoutData().(*unison.ResponsiveLayoutData)
+	check.True(t, ok)
+	check.Equal(t, 3, len(parent.Children()))
+}
@@ -22,6 +22,7 @@ type rootPanel struct {
 	openMenuPanels []*menuPanel
 	menuBarPanel   *menuPanel
 	tooltipPanel   *Panel
+	overlayPanel   *Panel
 	contentPanel   *Panel
 	menuBar        *menu
 }
@@ -89,6 +90,9 @@ func (p *rootPanel) setContent(content Paneler) {
 		if p.tooltipPanel != nil {
 			index++
 		}
+		if p.overlayPanel != nil {
+			index++
+		}
 		p.AddChildAtIndex(content, index)
 	}
 	p.NeedsLayout = true
@@ -111,6 +115,27 @@ func (p *rootPanel) setTooltip(tip *Panel) {
 	}
 }
 
+// setOverlay sets a panel to be shown above the content, but below any menus and tooltips, such as a list of
+// suggestions for a field. The overlay's frame is managed by the caller.
+func (p *rootPanel) setOverlay(overlay *Panel) {
+	if p.overlayPanel != nil {
+		p.overlayPanel.MarkForRedraw()
+		p.RemoveChild(p.overlayPanel)
+	}
+	p.overlayPanel = overlay
+	if overlay != nil {
+		index := len(p.openMenuPanels)
+		if p.menuBarPanel != nil {
+			index++
+		}
+		if p.tooltipPanel != nil {
+			index++
+		}
+		p.AddChildAtIndex(overlay, index)
+		overlay.MarkForRedraw()
+	}
+}
+
 func (p *rootPanel) LayoutSizes(_ *Panel, hint Size) (minSize, prefSize, maxSize Size) {
 	minSize, prefSize, maxSize = p.contentPanel.Sizes(hint)
 	if p.menuBarPanel != nil {
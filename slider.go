@@ -0,0 +1,615 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ddkwork/toolbox/xmath"
+	"github.com/ddkwork/unison/enums/paintstyle"
+)
+
+// maxSliderTicks limits the number of tick marks a slider will draw, regardless of its tick interval.
+const maxSliderTicks = 1000
+
+// DefaultSliderTheme holds the default SliderTheme values for Sliders and RangeSliders. Modifying this data will not
+// alter existing sliders, but will alter any sliders created in the future.
+var DefaultSliderTheme = SliderTheme{
+	TrackInk:        ControlColor,
+	FillInk:         AccentColor,
+	ThumbInk:        ControlColor,
+	PressedThumbInk: ControlPressedColor,
+	EdgeInk:         ControlEdgeColor,
+	FocusedEdgeInk:  AccentColor,
+	TickInk:         ControlEdgeColor,
+	LabelInk:        OnBackgroundColor,
+	LabelFont:       SmallSystemFont,
+	TrackThickness:  4,
+	ThumbSize:       16,
+	TickLength:      4,
+	TickGap:         2,
+	LabelGap:        1,
+	PreferredLength: 150,
+}
+
+// SliderTheme holds theming data for a Slider or RangeSlider.
+type SliderTheme struct {
+	TrackInk        Ink
+	FillInk         Ink
+	ThumbInk        Ink
+	PressedThumbInk Ink
+	EdgeInk         Ink
+	FocusedEdgeInk  Ink
+	TickInk         Ink
+	LabelInk        Ink
+	LabelFont       Font
+	TrackThickness  float32
+	ThumbSize       float32
+	TickLength      float32
+	TickGap         float32
+	LabelGap        float32
+	PreferredLength float32
+}
+
+// Slider allows a value to be chosen from a range by dragging a thumb along a track.
+type Slider[T xmath.Numeric] struct {
+	sliderBase[T]
+}
+
+// RangeSlider allows a range of values to be chosen by dragging two thumbs along a track. The low value may never be
+// greater than the high value.
+type RangeSlider[T xmath.Numeric] struct {
+	sliderBase[T]
+}
+
+// sliderBase provides the implementation shared by Slider and RangeSlider, which differ only in the number of thumbs.
+type sliderBase[T xmath.Numeric] struct {
+	Panel
+	SliderTheme
+	// ChangedCallback is called when a value is changed.
+	ChangedCallback func()
+	// Format is used to produce the text of the tick labels. It is the same as the Format function of a NumericField.
+	Format         func(T) string
+	values         []T
+	minimum        T
+	maximum        T
+	step           T
+	tickInterval   T
+	horizontal     bool
+	showTickLabels bool
+	activeThumb    int
+	dragThumb      int
+	dragOffset     float32
+}
+
+// NewSlider creates a new Slider. As with a NumericField, the value is constrained to the range minimum to maximum and
+// format is used to produce any text for the value. If format is nil, the value is formatted with fmt.Sprint().
+func NewSlider[T xmath.Numeric](current, minimum, maximum T, format func(T) string, horizontal bool) *Slider[T] {
+	s := &Slider[T]{}
+	s.Self = s
+	s.init([]T{current}, minimum, maximum, format, horizontal)
+	return s
+}
+
+// Value returns the current value.
+func (s *Slider[T]) Value() T {
+	return s.values[0]
+}
+
+// SetValue sets the current value, constrained to the range of the slider.
+func (s *Slider[T]) SetValue(value T) {
+	s.setThumb(0, value)
+}
+
+// NewRangeSlider creates a new RangeSlider. As with a NumericField, the values are constrained to the range minimum to
+// maximum and format is used to produce any text for the values. If format is nil, the values are formatted with
+// fmt.Sprint().
+func NewRangeSlider[T xmath.Numeric](low, high, minimum, maximum T, format func(T) string, horizontal bool) *RangeSlider[T] {
+	s := &RangeSlider[T]{}
+	s.Self = s
+	s.init([]T{low, max(low, high)}, minimum, maximum, format, horizontal)
+	return s
+}
+
+// Values returns the current low and high values.
+func (s *RangeSlider[T]) Values() (low, high T) {
+	return s.values[0], s.values[1]
+}
+
+// SetValues sets the current low and high values, constrained to the range of the slider.
+func (s *RangeSlider[T]) SetValues(low, high T) {
+	low = min(max(low, s.minimum), s.maximum)
+	high = min(max(high, low), s.maximum)
+	if s.values[0] != low || s.values[1] != high {
+		s.values[0] = low
+		s.values[1] = high
+		s.MarkForRedraw()
+		s.notifyChanged()
+	}
+}
+
+func (s *sliderBase[T]) init(values []T, minimum, maximum T, format func(T) string, horizontal bool) {
+	if format == nil {
+		format = func(v T) string { return fmt.Sprint(v) }
+	}
+	s.SliderTheme = DefaultSliderTheme
+	s.Format = format
+	s.minimum = minimum
+	s.maximum = max(minimum, maximum)
+	for i, v := range values {
+		values[i] = min(max(v, s.minimum), s.maximum)
+	}
+	s.values = values
+	s.horizontal = horizontal
+	s.dragThumb = -1
+	s.SetFocusable(true)
+	s.SetSizer(s.DefaultSizes)
+	s.DrawCallback = s.DefaultDraw
+	s.MouseDownCallback = s.DefaultMouseDown
+	s.MouseDragCallback = s.DefaultMouseDrag
+	s.MouseUpCallback = s.DefaultMouseUp
+	s.MouseWheelCallback = s.DefaultMouseWheel
+	s.KeyDownCallback = s.DefaultKeyDown
+	s.GainedFocusCallback = s.MarkForRedraw
+	s.LostFocusCallback = s.MarkForRedraw
+}
+
+// Horizontal returns true if this is a horizontal slider.
+func (s *sliderBase[T]) Horizontal() bool {
+	return s.horizontal
+}
+
+// Min returns the minimum value allowed.
+func (s *sliderBase[T]) Min() T {
+	return s.minimum
+}
+
+// Max returns the maximum value allowed.
+func (s *sliderBase[T]) Max() T {
+	return s.maximum
+}
+
+// SetMinMax sets the minimum and maximum values, adjusting the current values to fit within them.
+func (s *sliderBase[T]) SetMinMax(minimum, maximum T) {
+	maximum = max(minimum, maximum)
+	if s.minimum != minimum || s.maximum != maximum {
+		s.minimum = minimum
+		s.maximum = maximum
+		changed := false
+		for i, v := range s.values {
+			if adjusted := min(max(v, s.minimum), s.maximum); adjusted != v {
+				s.values[i] = adjusted
+				changed = true
+			}
+		}
+		s.InvalidateLayout()
+		if changed {
+			s.notifyChanged()
+		}
+	}
+}
+
+// Step returns the step that user changes snap to. A value of 0 means no snapping will occur.
+func (s *sliderBase[T]) Step() T {
+	return s.step
+}
+
+// SetStep sets the step that user changes snap to. A value of 0 means no snapping will occur. Integer sliders always
+// snap to whole numbers.
+func (s *sliderBase[T]) SetStep(step T) {
+	s.step = max(step, 0)
+}
+
+// TickInterval returns the interval between tick marks. A value of 0 means no tick marks will be drawn.
+func (s *sliderBase[T]) TickInterval() T {
+	return s.tickInterval
+}
+
+// SetTickInterval sets the interval between tick marks, starting from the minimum value. A value of 0 means no tick
+// marks will be drawn. When set, the tick interval is also used as the amount to move by for Page Up and Page Down.
+func (s *sliderBase[T]) SetTickInterval(interval T) {
+	interval = max(interval, 0)
+	if s.tickInterval != interval {
+		s.tickInterval = interval
+		s.InvalidateLayout()
+	}
+}
+
+// ShowTickLabels returns true if the tick marks will be labeled with their values.
+func (s *sliderBase[T]) ShowTickLabels() bool {
+	return s.showTickLabels
+}
+
+// SetShowTickLabels sets whether the tick marks will be labeled with their values, using the Format function.
+func (s *sliderBase[T]) SetShowTickLabels(show bool) {
+	if s.showTickLabels != show {
+		s.showTickLabels = show
+		s.InvalidateLayout()
+	}
+}
+
+// DefaultSizes provides the default sizing.
+func (s *sliderBase[T]) DefaultSizes(hint Size) (minSize, prefSize, maxSize Size) {
+	across := s.ThumbSize
+	if s.tickInterval > 0 {
+		across += s.TickGap + s.TickLength
+		if s.showTickLabels {
+			if s.horizontal {
+				across += s.LabelGap + s.LabelFont.LineHeight()
+			} else {
+				across += s.LabelGap + s.widestTickLabel()
+			}
+		}
+	}
+	if s.horizontal {
+		minSize = Size{Width: s.ThumbSize * 2, Height: across}
+		prefSize = Size{Width: s.PreferredLength, Height: across}
+		maxSize = Size{Width: DefaultMaxSize, Height: across}
+	} else {
+		minSize = Size{Width: across, Height: s.ThumbSize * 2}
+		prefSize = Size{Width: across, Height: s.PreferredLength}
+		maxSize = Size{Width: across, Height: DefaultMaxSize}
+	}
+	if border := s.Border(); border != nil {
+		insets := border.Insets()
+		minSize.AddInsets(insets)
+		prefSize.AddInsets(insets)
+		maxSize.AddInsets(insets)
+	}
+	minSize.GrowToInteger()
+	prefSize.GrowToInteger()
+	maxSize.GrowToInteger()
+	prefSize.ConstrainForHint(hint)
+	return minSize, prefSize, maxSize
+}
+
+// DefaultDraw provides the default drawing.
+func (s *sliderBase[T]) DefaultDraw(gc *Canvas, _ Rect) {
+	r := s.ContentRect(false)
+	start, end := s.trackSpan()
+	center := s.thumbCenterAcross()
+
+	track := s.spanRect(start, end, center, s.TrackThickness)
+	gc.DrawRoundedRect(track, s.TrackThickness/2, s.TrackThickness/2, s.ink(s.TrackInk).Paint(gc, track, paintstyle.Fill))
+	gc.DrawRoundedRect(track, s.TrackThickness/2, s.TrackThickness/2, s.ink(s.EdgeInk).Paint(gc, track, paintstyle.Stroke))
+
+	var from, to float32
+	if len(s.values) == 1 {
+		from, to = s.posForValue(s.minimum), s.posForValue(s.values[0])
+	} else {
+		from, to = s.posForValue(s.values[0]), s.posForValue(s.values[len(s.values)-1])
+	}
+	if fill := s.spanRect(min(from, to), max(from, to), center, s.TrackThickness); fill.Width > 0 && fill.Height > 0 {
+		gc.DrawRoundedRect(fill, s.TrackThickness/2, s.TrackThickness/2, s.ink(s.FillInk).Paint(gc, fill, paintstyle.Fill))
+	}
+
+	if s.tickInterval > 0 {
+		paint := s.ink(s.TickInk).Paint(gc, r, paintstyle.Stroke)
+		tickStart := center + s.ThumbSize/2 + s.TickGap
+		labelStart := tickStart + s.TickLength + s.LabelGap
+		labelInk := s.ink(s.LabelInk)
+		for _, v := range s.ticks() {
+			pos := s.posForValue(v)
+			if s.horizontal {
+				gc.DrawLine(pos, tickStart, pos, tickStart+s.TickLength, paint)
+			} else {
+				gc.DrawLine(tickStart, pos, tickStart+s.TickLength, pos, paint)
+			}
+			if s.showTickLabels {
+				text := NewText(s.Format(v), &TextDecoration{Font: s.LabelFont, Foreground: labelInk})
+				size := text.Extents()
+				if s.horizontal {
+					text.Draw(gc, pos-size.Width/2, labelStart+s.LabelFont.Baseline())
+				} else {
+					text.Draw(gc, labelStart, pos-size.Height/2+s.LabelFont.Baseline())
+				}
+			}
+		}
+	}
+
+	focused := s.Focused()
+	radius := s.ThumbSize / 2
+	for i, v := range s.values {
+		pos := s.posForValue(v)
+		thumb := s.spanRect(pos-radius, pos+radius, center, s.ThumbSize)
+		thumb.InsetUniform(0.5)
+		fill := s.ThumbInk
+		if i == s.dragThumb {
+			fill = s.PressedThumbInk
+		}
+		edge := s.EdgeInk
+		thickness := float32(1)
+		if focused && i == s.activeThumb {
+			edge = s.FocusedEdgeInk
+			thickness++
+		}
+		gc.DrawOval(thumb, s.ink(fill).Paint(gc, thumb, paintstyle.Fill))
+		paint := s.ink(edge).Paint(gc, thumb, paintstyle.Stroke)
+		paint.SetStrokeWidth(thickness)
+		gc.DrawOval(thumb, paint)
+	}
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (s *sliderBase[T]) DefaultMouseDown(where Point, _, _ int, _ Modifiers) bool {
+	if !s.Enabled() {
+		return false
+	}
+	if s.Focusable() {
+		s.RequestFocus()
+	}
+	pos := s.along(where)
+	i := s.thumbNear(pos)
+	s.activeThumb = i
+	s.dragThumb = i
+	s.dragOffset = 0
+	if thumbPos := s.posForValue(s.values[i]); xmath.Abs(thumbPos-pos) <= s.ThumbSize/2 {
+		s.dragOffset = thumbPos - pos
+	} else {
+		s.setThumb(i, s.snap(s.valueForPos(pos)))
+	}
+	s.MarkForRedraw()
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (s *sliderBase[T]) DefaultMouseDrag(where Point, _ int, _ Modifiers) bool {
+	if s.dragThumb != -1 {
+		s.setThumb(s.dragThumb, s.snap(s.valueForPos(s.along(where)+s.dragOffset)))
+	}
+	return true
+}
+
+// DefaultMouseUp provides the default mouse up handling.
+func (s *sliderBase[T]) DefaultMouseUp(_ Point, _ int, _ Modifiers) bool {
+	if s.dragThumb != -1 {
+		s.dragThumb = -1
+		s.MarkForRedraw()
+	}
+	return true
+}
+
+// DefaultMouseWheel provides the default mouse wheel handling.
+func (s *sliderBase[T]) DefaultMouseWheel(_, delta Point, _ Modifiers) bool {
+	if !s.Enabled() {
+		return false
+	}
+	amount := delta.Y
+	if amount == 0 {
+		amount = delta.X
+	}
+	switch {
+	case amount > 0:
+		s.stepThumb(s.activeThumb, s.keyStep())
+	case amount < 0:
+		s.stepThumb(s.activeThumb, -s.keyStep())
+	default:
+		return false
+	}
+	return true
+}
+
+// DefaultKeyDown provides the default key down handling. For a RangeSlider, the keys act on the thumb that was most
+// recently clicked, and the space bar switches between the thumbs.
+func (s *sliderBase[T]) DefaultKeyDown(keyCode KeyCode, mod Modifiers, _ bool) bool {
+	if !s.Enabled() {
+		return false
+	}
+	if IsControlAction(keyCode, mod) {
+		if len(s.values) < 2 {
+			return false
+		}
+		s.activeThumb = (s.activeThumb + 1) % len(s.values)
+		s.MarkForRedraw()
+		return true
+	}
+	switch keyCode {
+	case KeyLeft, KeyDown:
+		s.stepThumb(s.activeThumb, -s.keyStep())
+	case KeyRight, KeyUp:
+		s.stepThumb(s.activeThumb, s.keyStep())
+	case KeyPageDown:
+		s.stepThumb(s.activeThumb, -s.pageStep())
+	case KeyPageUp:
+		s.stepThumb(s.activeThumb, s.pageStep())
+	case KeyHome:
+		s.setThumb(s.activeThumb, s.minimum)
+	case KeyEnd:
+		s.setThumb(s.activeThumb, s.maximum)
+	default:
+		return false
+	}
+	return true
+}
+
+// setThumb sets the value of the thumb at the index, constrained by the range and any neighboring thumbs.
+func (s *sliderBase[T]) setThumb(index int, value T) {
+	lower := s.minimum
+	if index > 0 {
+		lower = s.values[index-1]
+	}
+	upper := s.maximum
+	if index < len(s.values)-1 {
+		upper = s.values[index+1]
+	}
+	value = min(max(value, lower), upper)
+	if s.values[index] != value {
+		s.values[index] = value
+		s.MarkForRedraw()
+		s.notifyChanged()
+	}
+}
+
+func (s *sliderBase[T]) stepThumb(index int, amount float64) {
+	s.setThumb(index, s.snap(float64(s.values[index])+amount))
+}
+
+func (s *sliderBase[T]) notifyChanged() {
+	if s.ChangedCallback != nil {
+		s.ChangedCallback()
+	}
+}
+
+// snap converts the value to the slider's type, rounding it to the nearest step and constraining it to the range.
+func (s *sliderBase[T]) snap(value float64) T {
+	if s.step > 0 {
+		step := float64(s.step)
+		value = float64(s.minimum) + math.Round((value-float64(s.minimum))/step)*step
+	}
+	if isIntegral[T]() {
+		value = math.Round(value)
+	}
+	value = min(max(value, float64(s.minimum)), float64(s.maximum))
+	return T(value)
+}
+
+func (s *sliderBase[T]) keyStep() float64 {
+	if s.step > 0 {
+		return float64(s.step)
+	}
+	step := (float64(s.maximum) - float64(s.minimum)) / 100
+	if isIntegral[T]() {
+		step = max(math.Round(step), 1)
+	}
+	return step
+}
+
+func (s *sliderBase[T]) pageStep() float64 {
+	if s.tickInterval > 0 {
+		return float64(s.tickInterval)
+	}
+	return s.keyStep() * 10
+}
+
+func (s *sliderBase[T]) ticks() []T {
+	if s.tickInterval <= 0 {
+		return nil
+	}
+	interval := float64(s.tickInterval)
+	count := min(int((float64(s.maximum)-float64(s.minimum))/interval), maxSliderTicks)
+	ticks := make([]T, 0, count+1)
+	for i := 0; i <= count; i++ {
+		ticks = append(ticks, T(float64(s.minimum)+float64(i)*interval))
+	}
+	return ticks
+}
+
+func (s *sliderBase[T]) widestTickLabel() float32 {
+	var widest float32
+	if s.showTickLabels {
+		for _, v := range s.ticks() {
+			widest = max(widest, NewText(s.Format(v), &TextDecoration{Font: s.LabelFont}).Width())
+		}
+	}
+	return widest
+}
+
+// trackSpan returns the start and end positions of the track along the slider's axis.
+func (s *sliderBase[T]) trackSpan() (start, end float32) {
+	r := s.ContentRect(false)
+	inset := s.ThumbSize / 2
+	if s.showTickLabels && s.tickInterval > 0 {
+		if s.horizontal {
+			inset = max(inset, s.widestTickLabel()/2)
+		} else {
+			inset = max(inset, s.LabelFont.LineHeight()/2)
+		}
+	}
+	if s.horizontal {
+		return r.X + inset, max(r.Right()-inset, r.X+inset)
+	}
+	return r.Y + inset, max(r.Bottom()-inset, r.Y+inset)
+}
+
+// thumbCenterAcross returns the position of the center of the thumbs across the slider's axis.
+func (s *sliderBase[T]) thumbCenterAcross() float32 {
+	r := s.ContentRect(false)
+	if s.horizontal {
+		return r.Y + s.ThumbSize/2
+	}
+	return r.X + s.ThumbSize/2
+}
+
+// spanRect returns a rectangle running from start to end along the slider's axis, with the given thickness centered on
+// center across it.
+func (s *sliderBase[T]) spanRect(start, end, center, thickness float32) Rect {
+	if s.horizontal {
+		return NewRect(start, center-thickness/2, end-start, thickness)
+	}
+	return NewRect(center-thickness/2, start, thickness, end-start)
+}
+
+func (s *sliderBase[T]) along(pt Point) float32 {
+	if s.horizontal {
+		return pt.X
+	}
+	return pt.Y
+}
+
+// posForValue returns the position along the slider's axis for the value. Vertical sliders place their minimum at the
+// bottom.
+func (s *sliderBase[T]) posForValue(value T) float32 {
+	start, end := s.trackSpan()
+	var fraction float32
+	if s.maximum > s.minimum {
+		fraction = float32((float64(value) - float64(s.minimum)) / (float64(s.maximum) - float64(s.minimum)))
+	}
+	if s.horizontal {
+		return start + (end-start)*fraction
+	}
+	return end - (end-start)*fraction
+}
+
+// valueForPos returns the unconstrained value for the position along the slider's axis.
+func (s *sliderBase[T]) valueForPos(pos float32) float64 {
+	start, end := s.trackSpan()
+	if end <= start {
+		return float64(s.minimum)
+	}
+	fraction := float64((pos - start) / (end - start))
+	if !s.horizontal {
+		fraction = 1 - fraction
+	}
+	return float64(s.minimum) + fraction*(float64(s.maximum)-float64(s.minimum))
+}
+
+// thumbNear returns the index of the thumb nearest the position along the slider's axis. When thumbs overlap, the one
+// that can move towards the position is chosen.
+func (s *sliderBase[T]) thumbNear(pos float32) int {
+	best := 0
+	bestDistance := float32(math.MaxFloat32)
+	target := s.valueForPos(pos)
+	for i, v := range s.values {
+		distance := xmath.Abs(s.posForValue(v) - pos)
+		if distance < bestDistance || (distance == bestDistance && target > float64(v)) {
+			best = i
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// ink returns the ink, adjusted for the enabled state.
+func (s *sliderBase[T]) ink(ink Ink) Ink {
+	if s.Enabled() {
+		return ink
+	}
+	return &ColorFilteredInk{
+		OriginalInk: ink,
+		ColorFilter: Grayscale30Filter(),
+	}
+}
+
+// isIntegral returns true if T is an integer type.
+func isIntegral[T xmath.Numeric]() bool {
+	half := 0.5
+	return T(half) == 0
+}
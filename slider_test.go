@@ -0,0 +1,60 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestSlider(t *testing.T) {
+	s := unison.NewSlider(50, 0, 100, nil, true)
+	changes := 0
+	s.ChangedCallback = func() { changes++ }
+	s.SetValue(150)
+	check.Equal(t, 100, s.Value())
+	check.Equal(t, 1, changes)
+
+	check.True(t, s.DefaultKeyDown(unison.KeyLeft, 0, false))
+	check.Equal(t, 99, s.Value())
+	s.SetStep(5)
+	check.True(t, s.DefaultKeyDown(unison.KeyLeft, 0, false))
+	check.Equal(t, 95, s.Value())
+	s.SetTickInterval(25)
+	check.True(t, s.DefaultKeyDown(unison.KeyPageDown, 0, false))
+	check.Equal(t, 70, s.Value())
+	check.True(t, s.DefaultKeyDown(unison.KeyHome, 0, false))
+	check.Equal(t, 0, s.Value())
+
+	s.SetMinMax(10, 20)
+	check.Equal(t, 10, s.Value())
+	check.Equal(t, 6, changes)
+}
+
+func TestRangeSlider(t *testing.T) {
+	s := unison.NewRangeSlider(0.25, 0.75, 0, 1, nil, false)
+	s.SetValues(0.5, 0.25)
+	low, high := s.Values()
+	check.Equal(t, 0.5, low)
+	check.Equal(t, 0.5, high)
+
+	s.SetValues(0.2, 0.8)
+	check.True(t, s.DefaultKeyDown(unison.KeyEnd, 0, false))
+	low, high = s.Values()
+	check.Equal(t, 0.8, low)
+	check.Equal(t, 0.8, high)
+
+	check.True(t, s.DefaultKeyDown(unison.KeySpace, 0, false))
+	check.True(t, s.DefaultKeyDown(unison.KeyEnd, 0, false))
+	_, high = s.Values()
+	check.Equal(t, 1.0, high)
+}
@@ -0,0 +1,582 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"math"
+)
+
+var _ Layout = &SplitPanel{}
+
+// DefaultSplitPanelTheme holds the default SplitPanelTheme values for SplitPanels. Modifying this data will not alter
+// existing SplitPanels, but will alter any SplitPanels created in the future.
+var DefaultSplitPanelTheme = SplitPanelTheme{
+	GripInk:        DividerColor,
+	FocusedGripInk: AccentColor,
+	GripCount:      5,
+	GripGap:        1,
+	GripWidth:      4,
+	GripHeight:     2,
+	GripMargin:     2,
+	KeyboardStep:   1,
+	LargeStep:      10,
+}
+
+// SplitPanelTheme holds theming data for a SplitPanel.
+type SplitPanelTheme struct {
+	GripInk        Ink
+	FocusedGripInk Ink
+	GripCount      int
+	GripGap        float32
+	GripWidth      float32
+	GripHeight     float32
+	GripMargin     float32
+	KeyboardStep   float32
+	LargeStep      float32
+}
+
+// DividerSize returns the size (running across the divider) of a divider.
+func (s *SplitPanelTheme) DividerSize() float32 {
+	return s.GripWidth + s.GripMargin*2
+}
+
+// SplitPaneData may be set as the layout data of a SplitPanel's child to control how its pane is sized.
+type SplitPaneData struct {
+	// Min is the minimum size of the pane along the SplitPanel's axis. If 0, the pane's minimum size is used.
+	Min float32
+	// Max is the maximum size of the pane along the SplitPanel's axis. If 0, the pane has no maximum.
+	Max float32
+	// Weight determines the share of any space gained or lost when the SplitPanel is resized. If no pane has a weight,
+	// the last pane that is able to change size absorbs the difference.
+	Weight float32
+	// Collapsible permits the pane to be collapsed by double-clicking an adjacent divider or pressing return while an
+	// adjacent divider has the focus.
+	Collapsible bool
+}
+
+// SplitPanelState holds the divider arrangement of a SplitPanel, suitable for serialization, so that it may be
+// restored later with SplitPanel.ApplyState().
+type SplitPanelState struct {
+	Sizes     []float32 `json:"sizes"`
+	Collapsed []bool    `json:"collapsed,omitempty"`
+}
+
+// SplitPanel arranges its children in a row or column, with a divider between each adjacent pair that may be dragged
+// to adjust their sizes. When the SplitPanel has the keyboard focus, the arrow keys move the last divider that was
+// clicked, Home and End move it as far as it will go, and return toggles the collapsed state of an adjacent pane.
+type SplitPanel struct {
+	Panel
+	SplitPanelTheme
+	// DividersChangedCallback is called when the user moves a divider or collapses or expands a pane.
+	DividersChangedCallback func()
+	sizes                   map[*Panel]float32
+	collapsed               map[*Panel]float32
+	dividers                []Rect
+	horizontal              bool
+	focusedDivider          int
+	dragDivider             int
+	dragStart               float32
+	dragInitial             float32
+	dragIsValid             bool
+}
+
+// NewSplitPanel creates a new SplitPanel. Pass true for horizontal to arrange the children side by side, false to stack
+// them vertically.
+func NewSplitPanel(horizontal bool) *SplitPanel {
+	s := &SplitPanel{
+		SplitPanelTheme: DefaultSplitPanelTheme,
+		sizes:           make(map[*Panel]float32),
+		collapsed:       make(map[*Panel]float32),
+		horizontal:      horizontal,
+		dragDivider:     -1,
+	}
+	s.Self = s
+	s.SetLayout(s)
+	s.SetFocusable(true)
+	s.DrawOverCallback = s.DefaultDrawOver
+	s.UpdateCursorCallback = s.DefaultUpdateCursor
+	s.MouseDownCallback = s.DefaultMouseDown
+	s.MouseDragCallback = s.DefaultMouseDrag
+	s.MouseUpCallback = s.DefaultMouseUp
+	s.KeyDownCallback = s.DefaultKeyDown
+	s.GainedFocusCallback = s.MarkForRedraw
+	s.LostFocusCallback = s.MarkForRedraw
+	return s
+}
+
+// Horizontal returns true if the children are arranged side by side rather than stacked vertically.
+func (s *SplitPanel) Horizontal() bool {
+	return s.horizontal
+}
+
+// SetHorizontal sets whether the children are arranged side by side rather than stacked vertically. Any divider
+// positions are reset.
+func (s *SplitPanel) SetHorizontal(horizontal bool) {
+	if s.horizontal != horizontal {
+		s.horizontal = horizontal
+		s.sizes = make(map[*Panel]float32)
+		s.collapsed = make(map[*Panel]float32)
+		s.MarkForLayoutAndRedraw()
+	}
+}
+
+// DividerCount returns the number of dividers.
+func (s *SplitPanel) DividerCount() int {
+	return max(len(s.Children())-1, 0)
+}
+
+// DividerPosition returns the position of the divider at the index, relative to the start of the content area.
+func (s *SplitPanel) DividerPosition(index int) float32 {
+	if index < 0 || index >= len(s.dividers) {
+		return 0
+	}
+	return s.alongPoint(s.dividers[index].Point) - s.alongPoint(s.ContentRect(false).Point)
+}
+
+// SetDividerPosition moves the divider at the index to the position, relative to the start of the content area,
+// constrained by the limits of the panes on either side of it.
+func (s *SplitPanel) SetDividerPosition(index int, pos float32) {
+	if index >= 0 && index < len(s.dividers) {
+		s.moveDivider(index, pos-s.DividerPosition(index))
+	}
+}
+
+// Collapsed returns true if the child is collapsed.
+func (s *SplitPanel) Collapsed(child Paneler) bool {
+	_, exists := s.collapsed[child.AsPanel()]
+	return exists
+}
+
+// SetCollapsed collapses or expands the child. A collapsed child is hidden and takes up no space. Expanding it restores
+// the size it had before being collapsed.
+func (s *SplitPanel) SetCollapsed(child Paneler, collapsed bool) {
+	p := child.AsPanel()
+	if p.parent != s.AsPanel() {
+		return
+	}
+	if size, exists := s.collapsed[p]; exists {
+		if !collapsed {
+			delete(s.collapsed, p)
+			s.sizes[p] = size
+			s.MarkForLayoutAndRedraw()
+		}
+	} else if collapsed {
+		size, ok := s.sizes[p]
+		if !ok {
+			size = s.along(p.FrameRect().Size)
+		}
+		s.collapsed[p] = size
+		s.MarkForLayoutAndRedraw()
+	}
+}
+
+// State returns the current divider arrangement.
+func (s *SplitPanel) State() *SplitPanelState {
+	children := s.Children()
+	state := &SplitPanelState{Sizes: make([]float32, len(children))}
+	for i, child := range children {
+		if size, exists := s.collapsed[child]; exists {
+			if state.Collapsed == nil {
+				state.Collapsed = make([]bool, len(children))
+			}
+			state.Collapsed[i] = true
+			state.Sizes[i] = size
+		} else if size, ok := s.sizes[child]; ok {
+			state.Sizes[i] = size
+		} else {
+			state.Sizes[i] = s.along(child.FrameRect().Size)
+		}
+	}
+	return state
+}
+
+// ApplyState restores a divider arrangement previously returned by State(). Children beyond those recorded in the state
+// are left as they are.
+func (s *SplitPanel) ApplyState(state *SplitPanelState) {
+	if state == nil {
+		return
+	}
+	for i, child := range s.Children() {
+		if i >= len(state.Sizes) {
+			break
+		}
+		if i < len(state.Collapsed) && state.Collapsed[i] {
+			s.collapsed[child] = state.Sizes[i]
+		} else {
+			delete(s.collapsed, child)
+			s.sizes[child] = state.Sizes[i]
+		}
+	}
+	s.MarkForLayoutAndRedraw()
+}
+
+func (s *SplitPanel) along(size Size) float32 {
+	if s.horizontal {
+		return size.Width
+	}
+	return size.Height
+}
+
+func (s *SplitPanel) alongPoint(pt Point) float32 {
+	if s.horizontal {
+		return pt.X
+	}
+	return pt.Y
+}
+
+func (s *SplitPanel) across(size Size) float32 {
+	if s.horizontal {
+		return size.Height
+	}
+	return size.Width
+}
+
+func (s *SplitPanel) sizeOf(along, across float32) Size {
+	if s.horizontal {
+		return Size{Width: along, Height: across}
+	}
+	return Size{Width: across, Height: along}
+}
+
+// limits returns the minimum and maximum sizes of the child along the axis.
+func (s *SplitPanel) limits(child *Panel) (minimum, maximum float32) {
+	if _, exists := s.collapsed[child]; exists {
+		return 0, 0
+	}
+	minSize, _, _ := child.Sizes(Size{})
+	minimum = s.along(minSize)
+	maximum = math.MaxFloat32
+	if data, ok := child.LayoutData().(*SplitPaneData); ok && data != nil {
+		if data.Min > 0 {
+			minimum = data.Min
+		}
+		if data.Max > 0 {
+			maximum = max(data.Max, minimum)
+		}
+	}
+	return minimum, maximum
+}
+
+// LayoutSizes implements Layout.
+func (s *SplitPanel) LayoutSizes(target *Panel, _ Size) (minSize, prefSize, maxSize Size) {
+	var minAlong, prefAlong, minAcross, prefAcross float32
+	children := target.Children()
+	for _, child := range children {
+		if _, exists := s.collapsed[child]; exists {
+			continue
+		}
+		childMin, childPref, _ := child.Sizes(Size{})
+		minimum, maximum := s.limits(child)
+		minAlong += minimum
+		prefAlong += min(max(s.along(childPref), minimum), maximum)
+		minAcross = max(minAcross, s.across(childMin))
+		prefAcross = max(prefAcross, s.across(childPref))
+	}
+	if len(children) > 1 {
+		dividers := s.DividerSize() * float32(len(children)-1)
+		minAlong += dividers
+		prefAlong += dividers
+	}
+	minSize = s.sizeOf(minAlong, minAcross)
+	prefSize = s.sizeOf(prefAlong, prefAcross)
+	if b := target.Border(); b != nil {
+		insets := b.Insets()
+		minSize.AddInsets(insets)
+		prefSize.AddInsets(insets)
+	}
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (s *SplitPanel) PerformLayout(target *Panel) {
+	r := s.ContentRect(false)
+	children := target.Children()
+	sizes := s.resolveSizes(children, s.along(r.Size)-s.DividerSize()*float32(max(len(children)-1, 0)))
+	s.dividers = s.dividers[:0]
+	var pos float32
+	for i, child := range children {
+		_, collapsed := s.collapsed[child]
+		child.Hidden = collapsed
+		if s.horizontal {
+			child.SetFrameRect(NewRect(r.X+pos, r.Y, sizes[i], r.Height))
+		} else {
+			child.SetFrameRect(NewRect(r.X, r.Y+pos, r.Width, sizes[i]))
+		}
+		pos += sizes[i]
+		if i < len(children)-1 {
+			if s.horizontal {
+				s.dividers = append(s.dividers, NewRect(r.X+pos, r.Y, s.DividerSize(), r.Height))
+			} else {
+				s.dividers = append(s.dividers, NewRect(r.X, r.Y+pos, r.Width, s.DividerSize()))
+			}
+			pos += s.DividerSize()
+		}
+	}
+	if s.focusedDivider >= len(s.dividers) {
+		s.focusedDivider = max(len(s.dividers)-1, 0)
+	}
+}
+
+// resolveSizes determines the size of each child along the axis, fitting them into the available space, and records
+// the results as the children's sizes.
+func (s *SplitPanel) resolveSizes(children []*Panel, avail float32) []float32 {
+	sizes := make([]float32, len(children))
+	mins := make([]float32, len(children))
+	maxs := make([]float32, len(children))
+	weights := make([]float32, len(children))
+	var total float32
+	for i, child := range children {
+		mins[i], maxs[i] = s.limits(child)
+		size, ok := s.sizes[child]
+		if !ok {
+			_, pref, _ := child.Sizes(Size{})
+			size = s.along(pref)
+		}
+		sizes[i] = min(max(size, mins[i]), maxs[i])
+		if data, ok := child.LayoutData().(*SplitPaneData); ok && data != nil {
+			weights[i] = max(data.Weight, 0)
+		}
+		total += sizes[i]
+	}
+	extra := avail - total
+	for range children {
+		if math.Abs(float64(extra)) < 0.5 {
+			break
+		}
+		var totalWeight float32
+		last := -1
+		for i := range children {
+			if (extra > 0 && sizes[i] < maxs[i]) || (extra < 0 && sizes[i] > mins[i]) {
+				totalWeight += weights[i]
+				last = i
+			}
+		}
+		if last == -1 {
+			break
+		}
+		remaining := extra
+		for i := range children {
+			var share float32
+			switch {
+			case totalWeight > 0:
+				if (extra > 0 && sizes[i] < maxs[i]) || (extra < 0 && sizes[i] > mins[i]) {
+					share = extra * weights[i] / totalWeight
+				}
+			case i == last:
+				share = extra
+			}
+			if share != 0 {
+				size := min(max(sizes[i]+share, mins[i]), maxs[i])
+				remaining -= size - sizes[i]
+				sizes[i] = size
+			}
+		}
+		extra = remaining
+	}
+	s.sizes = make(map[*Panel]float32, len(children))
+	for i, child := range children {
+		if _, exists := s.collapsed[child]; !exists {
+			s.sizes[child] = sizes[i]
+		}
+	}
+	for child := range s.collapsed {
+		if child.parent != s.AsPanel() {
+			delete(s.collapsed, child)
+		}
+	}
+	return sizes
+}
+
+// moveDivider moves the divider at the index by delta, constrained by the limits of the panes on either side of it.
+func (s *SplitPanel) moveDivider(index int, delta float32) {
+	children := s.Children()
+	if index < 0 || index+1 >= len(children) || delta == 0 {
+		return
+	}
+	before := children[index]
+	after := children[index+1]
+	for _, child := range []*Panel{before, after} {
+		if _, exists := s.collapsed[child]; exists {
+			delete(s.collapsed, child)
+			s.sizes[child] = 0
+		}
+	}
+	beforeSize := s.along(before.FrameRect().Size)
+	afterSize := s.along(after.FrameRect().Size)
+	if before.Hidden {
+		beforeSize = 0
+	}
+	if after.Hidden {
+		afterSize = 0
+	}
+	beforeMin, beforeMax := s.limits(before)
+	afterMin, afterMax := s.limits(after)
+	delta = max(delta, max(beforeMin-beforeSize, afterSize-afterMax))
+	delta = min(delta, min(beforeMax-beforeSize, afterSize-afterMin))
+	s.sizes[before] = beforeSize + delta
+	s.sizes[after] = afterSize - delta
+	s.MarkForLayoutAndRedraw()
+	s.ValidateLayout()
+	s.notifyDividersChanged()
+}
+
+// toggleCollapse collapses or expands the first collapsible pane adjacent to the divider at the index.
+func (s *SplitPanel) toggleCollapse(index int) {
+	children := s.Children()
+	if index < 0 || index+1 >= len(children) {
+		return
+	}
+	for _, child := range []*Panel{children[index], children[index+1]} {
+		if data, ok := child.LayoutData().(*SplitPaneData); ok && data != nil && data.Collapsible {
+			s.SetCollapsed(child, !s.Collapsed(child))
+			s.notifyDividersChanged()
+			return
+		}
+	}
+}
+
+func (s *SplitPanel) notifyDividersChanged() {
+	if s.DividersChangedCallback != nil {
+		s.DividersChangedCallback()
+	}
+}
+
+func (s *SplitPanel) dividerAt(where Point) int {
+	for i, r := range s.dividers {
+		if r.ContainsPoint(where) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DefaultDrawOver draws the dividers.
+func (s *SplitPanel) DefaultDrawOver(gc *Canvas, dirty Rect) {
+	focused := s.Focused()
+	for i, r := range s.dividers {
+		if !r.Intersects(dirty) {
+			continue
+		}
+		theme := DockTheme{
+			GripInk:    s.GripInk,
+			GripCount:  s.GripCount,
+			GripGap:    s.GripGap,
+			GripWidth:  s.GripWidth,
+			GripHeight: s.GripHeight,
+			GripMargin: s.GripMargin,
+		}
+		if focused && i == s.focusedDivider {
+			theme.GripInk = s.FocusedGripInk
+		}
+		if s.horizontal {
+			theme.DrawHorizontalGripper(gc, r)
+		} else {
+			theme.DrawVerticalGripper(gc, r)
+		}
+	}
+}
+
+// DefaultUpdateCursor adjusts the cursor for any dividers it may be over.
+func (s *SplitPanel) DefaultUpdateCursor(where Point) *Cursor {
+	if s.dividerAt(where) != -1 || s.dragDivider != -1 {
+		if s.horizontal {
+			return ResizeHorizontalCursor()
+		}
+		return ResizeVerticalCursor()
+	}
+	return ArrowCursor()
+}
+
+// DefaultMouseDown provides the default mouse down handling.
+func (s *SplitPanel) DefaultMouseDown(where Point, _, clickCount int, _ Modifiers) bool {
+	i := s.dividerAt(where)
+	if i == -1 {
+		return false
+	}
+	s.focusedDivider = i
+	if s.Focusable() {
+		s.RequestFocus()
+	}
+	if clickCount == 2 {
+		s.toggleCollapse(i)
+		return true
+	}
+	s.dragDivider = i
+	s.dragStart = s.alongPoint(where)
+	s.dragInitial = s.DividerPosition(i)
+	s.dragIsValid = false
+	s.MarkForRedraw()
+	return true
+}
+
+// DefaultMouseDrag provides the default mouse drag handling.
+func (s *SplitPanel) DefaultMouseDrag(where Point, _ int, _ Modifiers) bool {
+	if s.dragDivider != -1 {
+		if !s.dragIsValid {
+			s.dragIsValid = s.IsDragGesture(where)
+		}
+		if s.dragIsValid {
+			s.SetDividerPosition(s.dragDivider, s.dragInitial+s.alongPoint(where)-s.dragStart)
+		}
+	}
+	return true
+}
+
+// DefaultMouseUp provides the default mouse up handling.
+func (s *SplitPanel) DefaultMouseUp(where Point, button int, mod Modifiers) bool {
+	if s.dragDivider != -1 {
+		if s.dragIsValid {
+			s.DefaultMouseDrag(where, button, mod)
+		}
+		s.dragDivider = -1
+	}
+	return true
+}
+
+// DefaultKeyDown provides the default key down handling. Keys are only handled while the SplitPanel itself has the
+// keyboard focus, not when they have bubbled up from a focused descendant.
+func (s *SplitPanel) DefaultKeyDown(keyCode KeyCode, mod Modifiers, _ bool) bool {
+	if !s.Focused() || s.focusedDivider < 0 || s.focusedDivider >= len(s.dividers) {
+		return false
+	}
+	decrease, increase := KeyUp, KeyDown
+	if s.horizontal {
+		decrease, increase = KeyLeft, KeyRight
+	}
+	step := s.KeyboardStep
+	if mod.ShiftDown() {
+		step = s.LargeStep
+	}
+	switch keyCode {
+	case decrease:
+		s.moveDivider(s.focusedDivider, -step)
+	case increase:
+		s.moveDivider(s.focusedDivider, step)
+	case KeyHome:
+		s.moveDivider(s.focusedDivider, -math.MaxFloat32)
+	case KeyEnd:
+		s.moveDivider(s.focusedDivider, math.MaxFloat32)
+	case KeyReturn, KeyNumPadEnter:
+		s.toggleCollapse(s.focusedDivider)
+	case KeyTab:
+		if !mod.ControlDown() || len(s.dividers) < 2 {
+			return false
+		}
+		if mod.ShiftDown() {
+			s.focusedDivider = (s.focusedDivider + len(s.dividers) - 1) % len(s.dividers)
+		} else {
+			s.focusedDivider = (s.focusedDivider + 1) % len(s.dividers)
+		}
+		s.MarkForRedraw()
+	default:
+		return false
+	}
+	return true
+}
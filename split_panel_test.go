@@ -0,0 +1,84 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestSplitPanel(t *testing.T) {
+	split := unison.NewSplitPanel(true)
+	for range 3 {
+		newSizedPanel(split.AsPanel(), unison.Size{Width: 20, Height: 20}, unison.Size{Width: 100, Height: 50},
+			unison.Size{Width: 100, Height: 50})
+	}
+	children := split.Children()
+	children[0].SetLayoutData(&unison.SplitPaneData{Max: 150, Collapsible: true})
+	divider := split.DividerSize()
+
+	minSize, prefSize, _ := split.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 60 + divider*2, Height: 20}, minSize)
+	check.Equal(t, unison.Size{Width: 300 + divider*2, Height: 50}, prefSize)
+
+	split.SetFrameRect(unison.Rect{Size: unison.Size{Width: 400 + divider*2, Height: 50}})
+	split.ValidateLayout()
+	check.Equal(t, float32(100), split.DividerPosition(0))
+	check.Equal(t, float32(200), children[2].FrameRect().Width)
+
+	split.SetDividerPosition(0, 500)
+	check.Equal(t, float32(150), children[0].FrameRect().Width)
+	check.Equal(t, float32(50), children[1].FrameRect().Width)
+
+	state := split.State()
+	check.Equal(t, []float32{150, 50, 200}, state.Sizes)
+
+	split.SetCollapsed(children[0], true)
+	split.ValidateLayout()
+	check.True(t, children[0].Hidden)
+	check.Equal(t, float32(0), split.DividerPosition(0))
+	check.Equal(t, float32(350), children[2].FrameRect().Width)
+	check.Equal(t, []bool{true, false, false}, split.State().Collapsed)
+
+	split.ApplyState(state)
+	split.ValidateLayout()
+	check.False(t, split.Collapsed(children[0]))
+	check.False(t, children[0].Hidden)
+	check.Equal(t, float32(150), children[0].FrameRect().Width)
+	check.Equal(t, float32(200), children[2].FrameRect().Width)
+}
+
+func TestSplitPanelKeyboard(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 300, Height: 100}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	split := unison.NewSplitPanel(true)
+	split.KeyboardStep = 10
+	child := newLayoutChild(split.AsPanel(), nil)
+	child.SetFocusable(true)
+	newLayoutChild(split.AsPanel(), nil)
+	wnd.SetContent(split)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+	start := split.DividerPosition(0)
+
+	child.RequestFocus()
+	wnd.InjectKeyDown(unison.KeyRight, 0, false)
+	wnd.InjectKeyDown(unison.KeyEnd, 0, false)
+	wnd.ValidateLayout()
+	check.Equal(t, start, split.DividerPosition(0), "keys bubbling up from a focused child must not move the divider")
+
+	split.RequestFocus()
+	wnd.InjectKeyDown(unison.KeyRight, 0, false)
+	wnd.ValidateLayout()
+	check.Equal(t, start+10, split.DividerPosition(0))
+}
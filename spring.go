@@ -0,0 +1,107 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/toolbox/xmath"
+)
+
+var _ Animation = &Spring[float32]{}
+
+// Default values used by NewSpring.
+const (
+	DefaultSpringStiffness     = 170
+	DefaultSpringDamping       = 26
+	DefaultSpringMass          = 1
+	DefaultSpringRestThreshold = 0.01
+)
+
+const springStep = time.Second / 240
+
+// Spring animates a value toward a target using damped spring physics. Unlike a Tween, it has no fixed duration and
+// finishes once the value has come to rest at the target. The target may be changed while the spring is running, in
+// which case the current velocity is preserved, giving natural motion when the destination changes mid-flight.
+type Spring[T Animatable] struct {
+	Target    T
+	Stiffness float32
+	Damping   float32
+	Mass      float32
+	// RestThreshold is the distance from the target and speed below which the spring is considered to be at rest.
+	RestThreshold float32
+	// UpdateCallback, if set, is called with the new value each time the spring advances.
+	UpdateCallback func(value T)
+	value          [4]float32
+	velocity       [4]float32
+	last           time.Duration
+}
+
+// NewSpring creates a new Spring with default physical properties.
+func NewSpring[T Animatable](value, target T, updateCallback func(value T)) *Spring[T] {
+	return &Spring[T]{
+		Target:         target,
+		Stiffness:      DefaultSpringStiffness,
+		Damping:        DefaultSpringDamping,
+		Mass:           DefaultSpringMass,
+		RestThreshold:  DefaultSpringRestThreshold,
+		UpdateCallback: updateCallback,
+		value:          animatableComponents(value),
+	}
+}
+
+// Value returns the current value.
+func (s *Spring[T]) Value() T {
+	return animatableFromComponents[T](s.value)
+}
+
+// SetValue sets the current value and clears the velocity.
+func (s *Spring[T]) SetValue(value T) {
+	s.value = animatableComponents(value)
+	s.velocity = [4]float32{}
+}
+
+// Advance implements Animation.
+func (s *Spring[T]) Advance(elapsed time.Duration) bool {
+	if elapsed < s.last {
+		s.last = 0
+	}
+	target := animatableComponents(s.Target)
+	mass := s.Mass
+	if mass <= 0 {
+		mass = DefaultSpringMass
+	}
+	// Integrate in small fixed steps so that the result doesn't depend on the frame rate
+	for s.last < elapsed {
+		step := min(springStep, elapsed-s.last)
+		dt := float32(step.Seconds())
+		for i := range s.value {
+			force := -s.Stiffness*(s.value[i]-target[i]) - s.Damping*s.velocity[i]
+			s.velocity[i] += force / mass * dt
+			s.value[i] += s.velocity[i] * dt
+		}
+		s.last += step
+	}
+	atRest := true
+	for i := range s.value {
+		if xmath.Abs(s.value[i]-target[i]) > s.RestThreshold || xmath.Abs(s.velocity[i]) > s.RestThreshold {
+			atRest = false
+			break
+		}
+	}
+	if atRest {
+		s.value = target
+		s.velocity = [4]float32{}
+	}
+	if s.UpdateCallback != nil {
+		s.UpdateCallback(s.Value())
+	}
+	return atRest
+}
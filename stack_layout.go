@@ -0,0 +1,119 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/selfalign"
+)
+
+var _ Layout = &StackLayout{}
+
+// StackLayout is a Layout that places all of its children on top of each other within the same content area, with
+// later children drawn over earlier ones. Children may be given a *StackLayoutData as their layout data to control
+// their alignment and margins. The preferred size is the largest preferred size of the children.
+type StackLayout struct {
+	// HAlign is the horizontal alignment of children, unless overridden by their layout data. Use align.Fill to make
+	// children fill the available width.
+	HAlign align.Enum
+	// VAlign is the vertical alignment of children, unless overridden by their layout data. Use align.Fill to make
+	// children fill the available height.
+	VAlign align.Enum
+}
+
+// StackLayoutData is used to control how a child is placed by a StackLayout.
+type StackLayoutData struct {
+	// Margin is the space left between the child and the edges of the content area.
+	Margin Insets
+	// HAlign overrides the StackLayout's HAlign value for this child.
+	HAlign selfalign.Enum
+	// VAlign overrides the StackLayout's VAlign value for this child.
+	VAlign selfalign.Enum
+}
+
+// LayoutSizes implements Layout.
+func (s *StackLayout) LayoutSizes(target *Panel, hint Size) (minSize, prefSize, maxSize Size) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	for _, child := range target.Children() {
+		data := stackLayoutDataFor(child)
+		childHint := hint
+		if childHint.Width > 0 {
+			childHint.Width = max(childHint.Width-(insets.Width()+data.Margin.Width()), 0)
+		}
+		if childHint.Height > 0 {
+			childHint.Height = max(childHint.Height-(insets.Height()+data.Margin.Height()), 0)
+		}
+		childMin, childPref, _ := child.Sizes(childHint)
+		childMin.AddInsets(data.Margin)
+		childPref.AddInsets(data.Margin)
+		minSize.Max(childMin)
+		prefSize.Max(childPref)
+	}
+	minSize.AddInsets(insets)
+	prefSize.AddInsets(insets)
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (s *StackLayout) PerformLayout(target *Panel) {
+	var insets Insets
+	if b := target.Border(); b != nil {
+		insets = b.Insets()
+	}
+	content := Rect{Size: target.ContentRect(true).Size}
+	content.Inset(insets)
+	for _, child := range target.Children() {
+		data := stackLayoutDataFor(child)
+		area := content
+		area.Inset(data.Margin)
+		r := area
+		hAlign := resolveSelfAlign(data.HAlign, s.HAlign)
+		minSize, prefSize, maxSize := child.Sizes(Size{})
+		if hAlign == align.Fill {
+			r.Width = max(min(area.Width, maxSize.Width), minSize.Width)
+		} else {
+			r.Width = min(prefSize.Width, area.Width)
+		}
+		if r.Width != prefSize.Width {
+			minSize, prefSize, maxSize = child.Sizes(Size{Width: r.Width})
+		}
+		vAlign := resolveSelfAlign(data.VAlign, s.VAlign)
+		if vAlign == align.Fill {
+			r.Height = max(min(area.Height, maxSize.Height), minSize.Height)
+		} else {
+			r.Height = min(prefSize.Height, area.Height)
+		}
+		switch hAlign {
+		case align.Middle:
+			r.X += (area.Width - r.Width) / 2
+		case align.End:
+			r.X += area.Width - r.Width
+		default:
+		}
+		switch vAlign {
+		case align.Middle:
+			r.Y += (area.Height - r.Height) / 2
+		case align.End:
+			r.Y += area.Height - r.Height
+		default:
+		}
+		child.SetFrameRect(r)
+	}
+}
+
+func stackLayoutDataFor(child *Panel) StackLayoutData {
+	if data, ok := child.LayoutData().(*StackLayoutData); ok && data != nil {
+		return *data
+	}
+	return StackLayoutData{}
+}
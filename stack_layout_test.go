@@ -0,0 +1,52 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/selfalign"
+)
+
+func TestStackLayout(t *testing.T) {
+	parent := unison.NewPanel()
+	parent.SetLayout(&unison.StackLayout{HAlign: align.Fill, VAlign: align.Middle})
+	a := newLayoutChild(parent, nil)
+	b := newLayoutChild(parent, &unison.StackLayoutData{
+		Margin: unison.NewUniformInsets(5),
+		HAlign: selfalign.End,
+		VAlign: selfalign.Start,
+	})
+	c := newSizedPanel(parent, unison.Size{Width: 10, Height: 10}, unison.Size{Width: 30, Height: 30},
+		unison.Size{Width: 100, Height: 60})
+	c.SetLayoutData(&unison.StackLayoutData{VAlign: selfalign.Fill})
+	d := newSizedPanel(parent, unison.Size{Width: 120, Height: 10}, unison.Size{Width: 150, Height: 20},
+		unison.Size{Width: 150, Height: 20})
+	d.SetLayoutData(&unison.StackLayoutData{Margin: unison.Insets{Left: 50, Right: 50}})
+
+	minSize, prefSize, maxSize := parent.Sizes(unison.Size{})
+	check.Equal(t, unison.Size{Width: 220, Height: 20}, minSize, "the largest minimum of the children, margins included")
+	check.Equal(t, unison.Size{Width: 250, Height: 30}, prefSize,
+		"the largest preferred size of the children, margins included")
+	check.Equal(t, unison.MaxSize(prefSize), maxSize)
+
+	parent.SetFrameRect(unison.Rect{Size: unison.Size{Width: 200, Height: 100}})
+	parent.ValidateLayout()
+	check.Equal(t, unison.Rect{Point: unison.Point{Y: 40}, Size: unison.Size{Width: 200, Height: 20}}, a.FrameRect())
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 145, Y: 5}, Size: unison.Size{Width: 50, Height: 20}},
+		b.FrameRect(), "the child's alignment overrides the layout's, within its margins")
+	check.Equal(t, unison.Rect{Size: unison.Size{Width: 100, Height: 60}}, c.FrameRect(),
+		"filling is limited by the child's maximum size")
+	check.Equal(t, unison.Rect{Point: unison.Point{X: 50, Y: 40}, Size: unison.Size{Width: 120, Height: 20}},
+		d.FrameRect(), "filling never goes below the child's minimum size")
+}
@@ -65,6 +65,29 @@ func (s *surface) prepareCanvas(size Size, _ Rect, scaleX, scaleY float32) (*Can
 	return c, nil
 }
 
+func (s *surface) prepareRasterCanvas(size Size, scaleX, scaleY float32) (*Canvas, error) {
+	if s.size != size || scaleX != s.scaleX || scaleY != s.scaleY {
+		s.partialDispose()
+		s.size = size
+		s.scaleX = scaleX
+		s.scaleY = scaleY
+	}
+	if s.surface == nil {
+		width := max(int(size.Width*scaleX), 1)
+		height := max(int(size.Height*scaleY), 1)
+		if s.surface = skia.SurfaceMakeRasterN32PreMul(width, height, defaultSurfaceProps()); s.surface == nil {
+			return nil, errs.New("unable to create raster rendering surface")
+		}
+	}
+	c := &Canvas{
+		canvas:  skia.SurfaceGetCanvas(s.surface),
+		surface: s,
+	}
+	c.RestoreToCount(1)
+	c.SetMatrix(NewScaleMatrix(scaleX, scaleY))
+	return c, nil
+}
+
 func (s *surface) partialDispose() {
 	if s.surface != nil {
 		skia.SurfaceUnref(s.surface)
@@ -0,0 +1,799 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ddkwork/unison/enums/align"
+	"github.com/ddkwork/unison/enums/paintstyle"
+	"github.com/ddkwork/unison/enums/side"
+)
+
+var (
+	_ Layout = &TabPanel{}
+	_ Layout = &tabPanelHeader{}
+)
+
+// Tab defines the methods a panel must implement to be shown within a TabPanel. A Dockable satisfies this interface. If
+// the tab also implements TabCloser, a close button is shown on the tab, and if it implements TabModifier, a
+// modification indicator is shown along with its title.
+type Tab interface {
+	Paneler
+	// TitleIcon returns an Drawable representing this tab.
+	TitleIcon(suggestedSize Size) Drawable
+	// Title returns the title of this tab.
+	Title() string
+	// Tooltip returns the tooltip of this tab.
+	Tooltip() string
+}
+
+// TabModifier defines the method a Tab may implement to have a modification indicator shown on its tab.
+type TabModifier interface {
+	// Modified returns true if the tab's content has been modified.
+	Modified() bool
+}
+
+// DefaultTabPanelTheme holds the default TabPanelTheme values for TabPanels. Modifying this data will not alter existing
+// TabPanels, but will alter any TabPanels created in the future.
+var DefaultTabPanelTheme = TabPanelTheme{
+	BackgroundInk:   BackgroundColor,
+	TabInk:          ControlColor,
+	OnTabInk:        OnControlColor,
+	EdgeInk:         ControlEdgeColor,
+	TabFocusedInk:   TabFocusedColor,
+	OnTabFocusedInk: OnTabFocusedColor,
+	TabCurrentInk:   TabCurrentColor,
+	OnTabCurrentInk: OnTabCurrentColor,
+	DropAreaInk:     DropAreaColor,
+	TabBorder:       NewEmptyBorder(Insets{Top: 2, Left: 4, Bottom: 2, Right: 4}),
+	ContentBorder:   NewLineBorder(DividerColor, 0, NewUniformInsets(1), false),
+	HeaderInset:     4,
+	TabGap:          2,
+	TabInsertSize:   3,
+	Gap:             4,
+	LabelTheme:      defaultLabelTheme(),
+	ButtonTheme:     defaultButtonTheme(),
+}
+
+// TabPanelTheme holds theming data for a TabPanel.
+type TabPanelTheme struct {
+	BackgroundInk   Ink
+	TabInk          Ink
+	OnTabInk        Ink
+	EdgeInk         Ink
+	TabFocusedInk   Ink
+	OnTabFocusedInk Ink
+	TabCurrentInk   Ink
+	OnTabCurrentInk Ink
+	DropAreaInk     Ink
+	TabBorder       Border
+	ContentBorder   Border
+	HeaderInset     float32
+	TabGap          float32
+	TabInsertSize   float32
+	Gap             float32
+	LabelTheme      LabelTheme
+	ButtonTheme     ButtonTheme
+}
+
+// TabPanel shows one of its tabs at a time, along with a header containing a tab for each of them on one of its sides.
+// Tabs that don't fit within the header are made available through an overflow menu. Tabs may be reordered by dragging
+// them within the header, and when the header has the keyboard focus, the arrow keys, Home and End select tabs. Ctrl-Tab
+// and Ctrl-Shift-Tab cycle through the tabs from anywhere within the TabPanel.
+type TabPanel struct {
+	Panel
+	TabPanelTheme
+	// SelectionChangedCallback is called when the current tab changes. previous will be nil if there was no current tab
+	// before, and current will be nil if there no longer is one.
+	SelectionChangedCallback func(previous, current Tab)
+	header                   *tabPanelHeader
+	content                  *Panel
+	tabs                     []Tab
+	current                  int
+	side                     side.Enum
+}
+
+// NewTabPanel creates a new, empty TabPanel with its tabs along the top.
+func NewTabPanel() *TabPanel {
+	t := &TabPanel{
+		TabPanelTheme: DefaultTabPanelTheme,
+		content:       NewPanel(),
+		current:       -1,
+	}
+	t.Self = t
+	t.SetLayout(t)
+	t.header = newTabPanelHeader(t)
+	t.content.SetBorder(t.ContentBorder)
+	t.content.SetLayout(&StackLayout{HAlign: align.Fill, VAlign: align.Fill})
+	t.AddChild(t.header)
+	t.AddChild(t.content)
+	t.KeyDownCallback = t.DefaultKeyDown
+	return t
+}
+
+// Side returns the side the tabs are placed on.
+func (t *TabPanel) Side() side.Enum {
+	return t.side
+}
+
+// SetSide sets the side the tabs are placed on.
+func (t *TabPanel) SetSide(s side.Enum) {
+	if t.side != s {
+		t.side = s
+		t.header.MarkForLayoutRecursively()
+		t.MarkForLayoutAndRedraw()
+	}
+}
+
+// Tabs returns the tabs, in display order.
+func (t *TabPanel) Tabs() []Tab {
+	return slices.Clone(t.tabs)
+}
+
+// IndexOfTab returns the index of the tab, or -1 if it isn't within this TabPanel.
+func (t *TabPanel) IndexOfTab(tab Tab) int {
+	if tab != nil {
+		for i, one := range t.tabs {
+			if one.AsPanel() == tab.AsPanel() {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// AddTab adds a tab to the end. If there was no current tab, it becomes the current one.
+func (t *TabPanel) AddTab(tab Tab) {
+	t.InsertTab(tab, -1)
+}
+
+// InsertTab inserts a tab at the index. Passing in a negative value for the index will add it to the end. If there was
+// no current tab, it becomes the current one.
+func (t *TabPanel) InsertTab(tab Tab, index int) {
+	if tab == nil || t.IndexOfTab(tab) != -1 {
+		return
+	}
+	if index < 0 || index > len(t.tabs) {
+		index = len(t.tabs)
+	}
+	var previous Tab
+	if t.current >= 0 {
+		previous = t.tabs[t.current]
+	}
+	t.tabs = slices.Insert(t.tabs, index, tab)
+	t.header.insertTab(newTabPanelTab(t, tab), index)
+	p := tab.AsPanel()
+	p.Hidden = true
+	t.content.AddChild(p)
+	if previous == nil {
+		t.SetCurrentTabIndex(index)
+	} else {
+		t.current = t.IndexOfTab(previous)
+	}
+	t.MarkForLayoutAndRedraw()
+}
+
+// RemoveTab removes a tab. If it was the current tab, the tab that follows it, or if there is none, the tab preceding
+// it, becomes the current tab.
+func (t *TabPanel) RemoveTab(tab Tab) {
+	i := t.IndexOfTab(tab)
+	if i == -1 {
+		return
+	}
+	wasCurrent := i == t.current
+	if i < t.current {
+		t.current--
+	}
+	t.tabs = slices.Delete(t.tabs, i, i+1)
+	t.header.removeTab(i)
+	p := tab.AsPanel()
+	t.content.RemoveChild(p)
+	p.Hidden = false
+	if wasCurrent {
+		t.current = -1
+		t.selectTab(min(i, len(t.tabs)-1), tab)
+	}
+	t.MarkForLayoutAndRedraw()
+}
+
+// MoveTab moves a tab to a new index.
+func (t *TabPanel) MoveTab(tab Tab, index int) {
+	from := t.IndexOfTab(tab)
+	if from == -1 {
+		return
+	}
+	index = max(min(index, len(t.tabs)-1), 0)
+	if from == index {
+		return
+	}
+	var current Tab
+	if t.current >= 0 {
+		current = t.tabs[t.current]
+	}
+	t.tabs = slices.Insert(slices.Delete(t.tabs, from, from+1), index, tab)
+	t.header.moveTab(from, index)
+	t.current = t.IndexOfTab(current)
+	t.header.MarkForLayoutAndRedraw()
+}
+
+// CurrentTab returns the current tab, or nil if there are no tabs.
+func (t *TabPanel) CurrentTab() Tab {
+	if t.current >= 0 && t.current < len(t.tabs) {
+		return t.tabs[t.current]
+	}
+	return nil
+}
+
+// CurrentTabIndex returns the index of the current tab, or -1 if there are no tabs.
+func (t *TabPanel) CurrentTabIndex() int {
+	return t.current
+}
+
+// SetCurrentTab makes the tab the current one.
+func (t *TabPanel) SetCurrentTab(tab Tab) {
+	if i := t.IndexOfTab(tab); i != -1 {
+		t.SetCurrentTabIndex(i)
+	}
+}
+
+// SetCurrentTabIndex makes the tab at the index the current one.
+func (t *TabPanel) SetCurrentTabIndex(index int) {
+	if index < 0 || index >= len(t.tabs) || index == t.current {
+		return
+	}
+	t.selectTab(index, t.CurrentTab())
+}
+
+// selectTab makes the tab at the index the current one, or if the index is out of range, leaves no current tab.
+func (t *TabPanel) selectTab(index int, previous Tab) {
+	var current Tab
+	if index >= 0 && index < len(t.tabs) {
+		current = t.tabs[index]
+	} else {
+		index = -1
+	}
+	t.current = index
+	for i, tab := range t.tabs {
+		tab.AsPanel().Hidden = i != index
+	}
+	t.content.MarkForLayoutAndRedraw()
+	t.header.MarkForLayoutAndRedraw()
+	if t.SelectionChangedCallback != nil && previous != current {
+		t.SelectionChangedCallback(previous, current)
+	}
+}
+
+// AttemptClose attempts to close the tab. Only tabs that implement TabCloser may be closed. If the tab's AttemptClose()
+// method returns true, the tab is removed, if it hasn't already removed itself. Returns true if the tab was closed.
+func (t *TabPanel) AttemptClose(tab Tab) bool {
+	if closer, ok := tab.(TabCloser); ok && t.IndexOfTab(tab) != -1 && closer.MayAttemptClose() {
+		if closer.AttemptClose() {
+			t.RemoveTab(tab)
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateTab updates the title, icon, modification indicator and close button of the tab from its current state.
+func (t *TabPanel) UpdateTab(tab Tab) {
+	if i := t.IndexOfTab(tab); i != -1 {
+		t.header.tabs[i].update()
+	}
+}
+
+// DefaultKeyDown provides the default key down handling, cycling through the tabs with Ctrl-Tab and Ctrl-Shift-Tab.
+func (t *TabPanel) DefaultKeyDown(keyCode KeyCode, mod Modifiers, _ bool) bool {
+	if keyCode == KeyTab && mod.ControlDown() && len(t.tabs) > 1 {
+		if mod.ShiftDown() {
+			t.SetCurrentTabIndex((t.current + len(t.tabs) - 1) % len(t.tabs))
+		} else {
+			t.SetCurrentTabIndex((t.current + 1) % len(t.tabs))
+		}
+		return true
+	}
+	return false
+}
+
+// LayoutSizes implements Layout.
+func (t *TabPanel) LayoutSizes(target *Panel, _ Size) (minSize, prefSize, maxSize Size) {
+	headerMin, headerPref, _ := t.header.Sizes(Size{})
+	contentMin, contentPref, _ := t.content.Sizes(Size{})
+	if t.side.Horizontal() {
+		minSize = Size{Width: contentMin.Width + headerPref.Width, Height: max(contentMin.Height, headerMin.Height)}
+		prefSize = Size{Width: contentPref.Width + headerPref.Width, Height: max(contentPref.Height, headerPref.Height)}
+	} else {
+		minSize = Size{Width: max(contentMin.Width, headerMin.Width), Height: contentMin.Height + headerPref.Height}
+		prefSize = Size{Width: max(contentPref.Width, headerPref.Width), Height: contentPref.Height + headerPref.Height}
+	}
+	if b := target.Border(); b != nil {
+		insets := b.Insets()
+		minSize.AddInsets(insets)
+		prefSize.AddInsets(insets)
+	}
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+// PerformLayout implements Layout.
+func (t *TabPanel) PerformLayout(_ *Panel) {
+	r := t.ContentRect(false)
+	_, headerPref, _ := t.header.Sizes(Size{})
+	headerRect := r
+	contentRect := r
+	switch t.side {
+	case side.Left:
+		headerRect.Width = min(headerPref.Width, r.Width)
+		contentRect.X += headerRect.Width
+		contentRect.Width -= headerRect.Width
+	case side.Bottom:
+		headerRect.Height = min(headerPref.Height, r.Height)
+		contentRect.Height -= headerRect.Height
+		headerRect.Y = contentRect.Bottom()
+	case side.Right:
+		headerRect.Width = min(headerPref.Width, r.Width)
+		contentRect.Width -= headerRect.Width
+		headerRect.X = contentRect.Right()
+	default:
+		headerRect.Height = min(headerPref.Height, r.Height)
+		contentRect.Y += headerRect.Height
+		contentRect.Height -= headerRect.Height
+	}
+	t.header.SetFrameRect(headerRect)
+	t.content.SetFrameRect(contentRect)
+}
+
+type tabPanelHeader struct {
+	Panel
+	owner          *TabPanel
+	overflowButton *Button
+	// tabs holds the tabs in the same order as the owner's tabs. The order of the header's children is not relied upon,
+	// since the overflow button is also one of them.
+	tabs            []*tabPanelTab
+	dragTab         *tabPanelTab
+	dragInsertIndex int
+}
+
+func newTabPanelHeader(owner *TabPanel) *tabPanelHeader {
+	h := &tabPanelHeader{
+		owner:           owner,
+		overflowButton:  NewButton(),
+		dragInsertIndex: -1,
+	}
+	h.Self = h
+	h.SetLayout(h)
+	h.SetFocusable(true)
+	h.DrawCallback = h.draw
+	h.KeyDownCallback = h.keyDown
+	h.GainedFocusCallback = h.MarkForRedraw
+	h.LostFocusCallback = h.MarkForRedraw
+	h.overflowButton.ButtonTheme = owner.ButtonTheme
+	h.overflowButton.SetFocusable(false)
+	h.overflowButton.ClickCallback = h.handleOverflowPopup
+	h.AddChild(h.overflowButton)
+	return h
+}
+
+func (h *tabPanelHeader) insertTab(pt *tabPanelTab, index int) {
+	h.tabs = slices.Insert(h.tabs, index, pt)
+	h.AddChild(pt)
+}
+
+func (h *tabPanelHeader) removeTab(index int) {
+	h.RemoveChild(h.tabs[index])
+	h.tabs = slices.Delete(h.tabs, index, index+1)
+}
+
+func (h *tabPanelHeader) moveTab(from, to int) {
+	pt := h.tabs[from]
+	h.tabs = slices.Insert(slices.Delete(h.tabs, from, from+1), to, pt)
+}
+
+// along returns the extent of the size along the header's axis.
+func (h *tabPanelHeader) along(size Size) float32 {
+	if h.owner.side.Horizontal() {
+		return size.Height
+	}
+	return size.Width
+}
+
+// alongPoint returns the coordinate of the point along the header's axis.
+func (h *tabPanelHeader) alongPoint(pt Point) float32 {
+	if h.owner.side.Horizontal() {
+		return pt.Y
+	}
+	return pt.X
+}
+
+// across returns the extent of the size across the header's axis.
+func (h *tabPanelHeader) across(size Size) float32 {
+	if h.owner.side.Horizontal() {
+		return size.Width
+	}
+	return size.Height
+}
+
+func (h *tabPanelHeader) sizeOf(along, across float32) Size {
+	if h.owner.side.Horizontal() {
+		return Size{Width: across, Height: along}
+	}
+	return Size{Width: along, Height: across}
+}
+
+func (h *tabPanelHeader) LayoutSizes(_ *Panel, _ Size) (minSize, prefSize, maxSize Size) {
+	var prefAlong, minAlong, across float32
+	tabs := h.tabs
+	for i, pt := range tabs {
+		_, size, _ := pt.Sizes(Size{})
+		prefAlong += h.along(size)
+		minAlong = max(minAlong, h.along(size))
+		across = max(across, h.across(size))
+		if i != 0 {
+			prefAlong += h.owner.TabGap
+		}
+	}
+	if len(tabs) > 1 {
+		_, size, _ := h.overflowButton.Sizes(Size{})
+		minAlong += h.owner.TabGap + h.along(size)
+	}
+	prefAlong += h.owner.HeaderInset * 2
+	minAlong += h.owner.HeaderInset * 2
+	prefSize = h.sizeOf(prefAlong, across)
+	minSize = h.sizeOf(minAlong, across)
+	return minSize, prefSize, MaxSize(prefSize)
+}
+
+func (h *tabPanelHeader) PerformLayout(_ *Panel) {
+	r := h.ContentRect(false)
+	tabs := h.tabs
+	sizes := make([]Size, len(tabs))
+	var across float32
+	for i, pt := range tabs {
+		_, sizes[i], _ = pt.Sizes(Size{})
+		across = max(across, h.across(sizes[i]))
+	}
+	avail := h.along(r.Size) - h.owner.HeaderInset*2
+	total := float32(0)
+	for i := range tabs {
+		if i != 0 {
+			total += h.owner.TabGap
+		}
+		total += h.along(sizes[i])
+	}
+	hidden := make([]bool, len(tabs))
+	var overflowSize Size
+	if total > avail && len(tabs) > 1 {
+		count := 0
+		for i := len(tabs) - 1; i >= 0 && total > avail; i-- {
+			if i == h.owner.current {
+				continue
+			}
+			hidden[i] = true
+			count++
+			total -= h.along(sizes[i]) + h.owner.TabGap
+			h.overflowButton.Text = "»" + strconv.Itoa(count)
+			h.overflowButton.MarkForLayoutAndRedraw()
+			_, overflowSize, _ = h.overflowButton.Sizes(Size{})
+			avail = h.along(r.Size) - (h.owner.HeaderInset*2 + h.owner.TabGap + h.along(overflowSize))
+		}
+	}
+	pos := h.owner.HeaderInset
+	for i, pt := range tabs {
+		pt.Hidden = hidden[i]
+		if hidden[i] {
+			continue
+		}
+		var frame Rect
+		if h.owner.side.Horizontal() {
+			frame = NewRect(r.X, r.Y+pos, r.Width, sizes[i].Height)
+		} else {
+			frame = NewRect(r.X+pos, r.Y+r.Height-sizes[i].Height, sizes[i].Width, sizes[i].Height)
+			if h.owner.side == side.Bottom {
+				frame.Y = r.Y
+			}
+		}
+		frame.Align()
+		pt.SetFrameRect(frame)
+		pos += h.along(sizes[i]) + h.owner.TabGap
+	}
+	h.overflowButton.Hidden = overflowSize.Width == 0
+	if !h.overflowButton.Hidden {
+		var frame Rect
+		if h.owner.side.Horizontal() {
+			frame = NewRect(r.X+(r.Width-overflowSize.Width)/2, r.Y+pos, overflowSize.Width, overflowSize.Height)
+		} else {
+			frame = NewRect(r.X+pos, r.Y+(r.Height-overflowSize.Height)/2, overflowSize.Width, overflowSize.Height)
+		}
+		frame.Align()
+		h.overflowButton.SetFrameRect(frame)
+	}
+}
+
+func (h *tabPanelHeader) draw(gc *Canvas, rect Rect) {
+	gc.DrawRect(rect, h.owner.BackgroundInk.Paint(gc, rect, paintstyle.Fill))
+	if h.dragInsertIndex < 0 {
+		return
+	}
+	var pos float32
+	tabs := h.visibleTabs()
+	if h.dragInsertIndex < len(tabs) {
+		pos = h.alongPoint(tabs[h.dragInsertIndex].FrameRect().Point) - (h.owner.TabGap+h.owner.TabInsertSize)/2
+	} else if len(tabs) != 0 {
+		r := tabs[len(tabs)-1].FrameRect()
+		pos = h.alongPoint(r.Point) + h.along(r.Size) + (h.owner.TabGap-h.owner.TabInsertSize)/2
+	}
+	r := h.ContentRect(false)
+	if h.owner.side.Horizontal() {
+		r.Y = pos
+		r.Height = h.owner.TabInsertSize
+	} else {
+		r.X = pos
+		r.Width = h.owner.TabInsertSize
+	}
+	gc.DrawRect(r, h.owner.DropAreaInk.Paint(gc, r, paintstyle.Fill))
+}
+
+func (h *tabPanelHeader) visibleTabs() []*tabPanelTab {
+	tabs := h.tabs
+	visible := make([]*tabPanelTab, 0, len(tabs))
+	for _, pt := range tabs {
+		if !pt.Hidden {
+			visible = append(visible, pt)
+		}
+	}
+	return visible
+}
+
+// updateDrag determines where the tab being dragged would be inserted, given a point in the header's coordinates.
+func (h *tabPanelHeader) updateDrag(where Point) {
+	tabs := h.visibleTabs()
+	h.dragInsertIndex = len(tabs)
+	at := h.alongPoint(where)
+	for i, pt := range tabs {
+		r := pt.FrameRect()
+		if at < h.alongPoint(r.Point)+h.along(r.Size)/2 {
+			h.dragInsertIndex = i
+			break
+		}
+	}
+	h.MarkForRedraw()
+}
+
+func (h *tabPanelHeader) finishDrag() {
+	if h.dragTab != nil && h.dragInsertIndex >= 0 {
+		tabs := h.visibleTabs()
+		from := h.owner.IndexOfTab(h.dragTab.tab)
+		to := len(h.owner.tabs)
+		if h.dragInsertIndex < len(tabs) {
+			to = h.owner.IndexOfTab(tabs[h.dragInsertIndex].tab)
+		}
+		if from < to {
+			to--
+		}
+		h.owner.MoveTab(h.dragTab.tab, to)
+	}
+	h.dragTab = nil
+	h.dragInsertIndex = -1
+	h.MarkForRedraw()
+}
+
+func (h *tabPanelHeader) keyDown(keyCode KeyCode, _ Modifiers, _ bool) bool {
+	previous, next := KeyLeft, KeyRight
+	if h.owner.side.Horizontal() {
+		previous, next = KeyUp, KeyDown
+	}
+	count := len(h.owner.tabs)
+	if count == 0 {
+		return false
+	}
+	switch keyCode {
+	case previous:
+		h.owner.SetCurrentTabIndex(max(h.owner.current-1, 0))
+	case next:
+		h.owner.SetCurrentTabIndex(min(h.owner.current+1, count-1))
+	case KeyHome:
+		h.owner.SetCurrentTabIndex(0)
+	case KeyEnd:
+		h.owner.SetCurrentTabIndex(count - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *tabPanelHeader) handleOverflowPopup() {
+	tabs := h.tabs
+	m := DefaultMenuFactory().NewMenu(PopupMenuTemporaryBaseID, "", nil)
+	defer m.Dispose()
+	for i, pt := range tabs {
+		if pt.Hidden {
+			m.InsertItem(-1, m.Factory().NewItem(PopupMenuTemporaryBaseID+i+1, pt.tab.Title(), KeyBinding{}, nil, func(item MenuItem) {
+				h.owner.SetCurrentTab(tabs[item.ID()-(PopupMenuTemporaryBaseID+1)].tab)
+			}))
+		}
+	}
+	m.Popup(h.overflowButton.RectToRoot(h.overflowButton.ContentRect(true)), 0)
+}
+
+type tabPanelTab struct {
+	Panel
+	owner   *TabPanel
+	tab     Tab
+	title   *Label
+	button  *Button
+	pressed bool
+}
+
+func newTabPanelTab(owner *TabPanel, tab Tab) *tabPanelTab {
+	t := &tabPanelTab{
+		owner: owner,
+		tab:   tab,
+		title: NewLabel(),
+	}
+	t.Self = t
+	t.DrawCallback = t.draw
+	t.SetBorder(owner.TabBorder)
+	flex := &FlexLayout{
+		Columns:  1,
+		HSpacing: owner.Gap,
+	}
+	t.SetLayout(flex)
+	t.title.LabelTheme = owner.LabelTheme
+	t.title.SetLayoutData(&FlexLayoutData{HGrab: true, VAlign: align.Middle})
+	t.AddChild(t.title)
+	if _, ok := tab.(TabCloser); ok {
+		t.button = NewButton()
+		t.button.ButtonTheme = owner.ButtonTheme
+		t.button.SetFocusable(false)
+		fSize := owner.LabelTheme.Font.Baseline()
+		t.button.Drawable = &DrawableSVG{
+			SVG:  CircledXSVG,
+			Size: Size{Width: fSize, Height: fSize},
+		}
+		t.button.SetLayoutData(&FlexLayoutData{HAlign: align.End, VAlign: align.Middle})
+		t.AddChild(t.button)
+		t.button.ClickCallback = func() { owner.AttemptClose(tab) }
+		flex.Columns++
+	}
+	t.update()
+	t.MouseDownCallback = t.mouseDown
+	t.MouseDragCallback = t.mouseDrag
+	t.MouseUpCallback = t.mouseUp
+	t.UpdateTooltipCallback = t.updateTooltip
+	return t
+}
+
+func (t *tabPanelTab) update() {
+	var buffer strings.Builder
+	if modifier, ok := t.tab.(TabModifier); ok && modifier.Modified() {
+		buffer.WriteByte('*')
+	}
+	buffer.WriteString(t.tab.Title())
+	title := buffer.String()
+	fSize := t.title.Font.Baseline()
+	drawable := t.tab.TitleIcon(Size{Width: fSize, Height: fSize})
+	if title != t.title.Text || drawable != t.title.Drawable {
+		t.title.Text = title
+		t.title.Drawable = drawable
+		t.title.InvalidateLayout()
+	}
+	if t.button != nil {
+		if closer, ok := t.tab.(TabCloser); ok {
+			t.button.SetEnabled(closer.MayAttemptClose())
+		}
+	}
+}
+
+func (t *tabPanelTab) draw(gc *Canvas, _ Rect) {
+	var bg, fg Ink
+	switch {
+	case t.pressed:
+		bg = t.owner.TabFocusedInk
+		fg = t.owner.OnTabFocusedInk
+	case t.owner.CurrentTab() == t.tab:
+		if t.owner.header.Focused() {
+			bg = t.owner.TabFocusedInk
+			fg = t.owner.OnTabFocusedInk
+		} else {
+			bg = t.owner.TabCurrentInk
+			fg = t.owner.OnTabCurrentInk
+		}
+	default:
+		bg = t.owner.TabInk
+		fg = t.owner.OnTabInk
+	}
+	t.title.OnBackgroundInk = fg
+	if t.button != nil {
+		t.button.BackgroundInk = fg
+	}
+	r := t.ContentRect(true)
+	p := t.shape(r)
+	gc.DrawPath(p, bg.Paint(gc, r, paintstyle.Fill))
+	gc.DrawPath(p, t.owner.EdgeInk.Paint(gc, r, paintstyle.Stroke))
+}
+
+// shape returns the outline of the tab, with rounded corners on the side away from the content and the side facing
+// the content left open.
+func (t *tabPanelTab) shape(r Rect) *Path {
+	s := t.owner.side
+	length := r.Width
+	depth := r.Height
+	if s.Horizontal() {
+		length, depth = depth, length
+	}
+	pt := func(along, into float32) (x, y float32) {
+		switch s {
+		case side.Left:
+			return into, along
+		case side.Bottom:
+			return along, r.Height - into
+		case side.Right:
+			return r.Width - into, along
+		default:
+			return along, into
+		}
+	}
+	p := NewPath()
+	p.MoveTo(pt(0, depth))
+	p.LineTo(pt(0, 6))
+	x1, y1 := pt(0, 6)
+	x2, y2 := pt(0, 1)
+	x3, y3 := pt(6, 1)
+	p.CubicTo(x1, y1, x2, y2, x3, y3)
+	cornerStart := length - 7
+	p.LineTo(pt(cornerStart, 1))
+	end := length - 1
+	x1, y1 = pt(cornerStart, 1)
+	x2, y2 = pt(end, 1)
+	x3, y3 = pt(end, 7)
+	p.CubicTo(x1, y1, x2, y2, x3, y3)
+	p.LineTo(pt(end, depth))
+	p.Close()
+	return p
+}
+
+func (t *tabPanelTab) updateTooltip(_ Point, suggestedAvoidInRoot Rect) Rect {
+	if tip := t.tab.Tooltip(); tip != "" {
+		t.Tooltip = NewTooltipWithText(tip)
+	} else {
+		t.Tooltip = nil
+	}
+	return suggestedAvoidInRoot
+}
+
+func (t *tabPanelTab) mouseDown(_ Point, _, _ int, _ Modifiers) bool {
+	t.pressed = true
+	t.MarkForRedraw()
+	return true
+}
+
+func (t *tabPanelTab) mouseDrag(where Point, _ int, _ Modifiers) bool {
+	h := t.owner.header
+	if h.dragTab == nil && len(t.owner.tabs) > 1 && t.IsDragGesture(where) {
+		h.dragTab = t
+	}
+	if h.dragTab == t {
+		h.updateDrag(t.PointTo(where, h.AsPanel()))
+	}
+	return true
+}
+
+func (t *tabPanelTab) mouseUp(where Point, _ int, _ Modifiers) bool {
+	if t.owner.header.dragTab == t {
+		t.owner.header.finishDrag()
+	} else if t.ContentRect(true).ContainsPoint(where) {
+		t.owner.SetCurrentTab(t.tab)
+	}
+	t.pressed = false
+	t.MarkForRedraw()
+	return true
+}
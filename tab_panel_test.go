@@ -0,0 +1,179 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+type testTab struct {
+	unison.Panel
+	title string
+}
+
+func newTestTab(title string) *testTab {
+	t := &testTab{title: title}
+	t.Self = t
+	return t
+}
+
+func (t *testTab) TitleIcon(_ unison.Size) unison.Drawable {
+	return nil
+}
+
+func (t *testTab) Title() string {
+	return t.title
+}
+
+func (t *testTab) Tooltip() string {
+	return ""
+}
+
+func tabTitles(tp *unison.TabPanel) []string {
+	tabs := tp.Tabs()
+	titles := make([]string, len(tabs))
+	for i, tab := range tabs {
+		titles[i] = tab.Title()
+	}
+	return titles
+}
+
+func TestTabPanel(t *testing.T) {
+	tp := unison.NewTabPanel()
+	var changes []string
+	tp.SelectionChangedCallback = func(previous, current unison.Tab) {
+		title := func(tab unison.Tab) string {
+			if tab == nil {
+				return "-"
+			}
+			return tab.Title()
+		}
+		changes = append(changes, title(previous)+">"+title(current))
+	}
+	header := tp.Children()[0]
+	a := newTestTab("a")
+	b := newTestTab("b")
+	c := newTestTab("c")
+	d := newTestTab("d")
+	tp.AddTab(a)
+	tp.AddTab(b)
+	tp.AddTab(c)
+	tp.InsertTab(d, 0)
+	tp.AddTab(a)
+	check.Equal(t, []string{"d", "a", "b", "c"}, tabTitles(tp))
+	check.Equal(t, 1, tp.CurrentTabIndex(), "inserting before the current tab must not change it")
+	check.Equal(t, []string{"->a"}, changes)
+	check.Equal(t, 5, len(header.Children()), "one child per tab, plus the overflow button")
+	check.False(t, a.Hidden)
+	check.True(t, b.Hidden)
+
+	tp.MoveTab(a, 10)
+	check.Equal(t, []string{"d", "b", "c", "a"}, tabTitles(tp))
+	check.Equal(t, 3, tp.CurrentTabIndex())
+	tp.MoveTab(b, -1)
+	check.Equal(t, []string{"b", "d", "c", "a"}, tabTitles(tp))
+
+	tp.SetCurrentTab(c)
+	check.Equal(t, 2, tp.CurrentTabIndex())
+	tp.RemoveTab(c)
+	check.Equal(t, a, tp.CurrentTab(), "removing the current tab selects the one that follows it")
+	check.False(t, c.Hidden, "removed tabs are no longer hidden")
+	tp.RemoveTab(a)
+	check.Equal(t, d, tp.CurrentTab(), "removing the last tab while current selects the one preceding it")
+	tp.RemoveTab(b)
+	check.Equal(t, 0, tp.CurrentTabIndex())
+	check.Equal(t, d, tp.CurrentTab())
+	tp.RemoveTab(d)
+	check.Equal(t, -1, tp.CurrentTabIndex())
+	check.Nil(t, tp.CurrentTab())
+	check.Equal(t, []string{"->a", "a>c", "c>a", "a>d", "d>-"}, changes)
+	check.Equal(t, 1, len(header.Children()))
+}
+
+func TestTabPanelKeyboard(t *testing.T) {
+	tp := unison.NewTabPanel()
+	a := newTestTab("a")
+	b := newTestTab("b")
+	c := newTestTab("c")
+	tp.AddTab(a)
+	tp.AddTab(b)
+	tp.AddTab(c)
+	header := tp.Children()[0]
+	check.True(t, header.KeyDownCallback(unison.KeyRight, 0, false))
+	check.Equal(t, b, tp.CurrentTab())
+	check.True(t, header.KeyDownCallback(unison.KeyEnd, 0, false))
+	check.Equal(t, c, tp.CurrentTab())
+	check.True(t, header.KeyDownCallback(unison.KeyRight, 0, false))
+	check.Equal(t, c, tp.CurrentTab(), "selection must not move past the last tab")
+	check.True(t, header.KeyDownCallback(unison.KeyHome, 0, false))
+	check.Equal(t, a, tp.CurrentTab())
+	check.False(t, header.KeyDownCallback(unison.KeyDown, 0, false))
+
+	check.True(t, tp.DefaultKeyDown(unison.KeyTab, unison.ControlModifier|unison.ShiftModifier, false))
+	check.Equal(t, c, tp.CurrentTab(), "Ctrl-Shift-Tab wraps around to the last tab")
+	check.True(t, tp.DefaultKeyDown(unison.KeyTab, unison.ControlModifier, false))
+	check.Equal(t, a, tp.CurrentTab(), "Ctrl-Tab wraps around to the first tab")
+	check.False(t, tp.DefaultKeyDown(unison.KeyTab, 0, false))
+}
+
+func TestTabPanelDrag(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 400, Height: 200}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	tp := unison.NewTabPanel()
+	tp.AddTab(newTestTab("a"))
+	tp.AddTab(newTestTab("b"))
+	tp.AddTab(newTestTab("c"))
+	wnd.SetContent(tp)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+
+	// tabRects returns the rects of the visible tabs in the header, in display order.
+	tabRects := func() []unison.Rect {
+		var rects []unison.Rect
+		for _, child := range tp.Children()[0].Children() {
+			if _, isButton := child.Self.(*unison.Button); !child.Hidden && !isButton {
+				rects = append(rects, child.RectToRoot(child.ContentRect(true)))
+			}
+		}
+		slices.SortFunc(rects, func(r1, r2 unison.Rect) int { return int(r1.X - r2.X) })
+		return rects
+	}
+	drag := func(from int, to unison.Point) {
+		rects := tabRects()
+		check.Equal(t, 3, len(rects))
+		wnd.InjectDrag(rects[from].Center(), to, unison.ButtonLeft, 4, 0)
+		wnd.ValidateLayout()
+	}
+
+	// Dropping after the last tab moves the first tab to the end
+	rects := tabRects()
+	drag(0, unison.Point{X: rects[2].Right() - 1, Y: rects[2].CenterY()})
+	check.Equal(t, []string{"b", "c", "a"}, tabTitles(tp))
+
+	// Dropping before the first tab moves the last tab to the start
+	rects = tabRects()
+	drag(2, unison.Point{X: rects[0].X + 1, Y: rects[0].CenterY()})
+	check.Equal(t, []string{"a", "b", "c"}, tabTitles(tp))
+
+	// Dropping a tab just before the tab that follows it leaves the order unchanged
+	rects = tabRects()
+	drag(1, unison.Point{X: rects[2].X + 1, Y: rects[2].CenterY()})
+	check.Equal(t, []string{"a", "b", "c"}, tabTitles(tp))
+
+	// Dropping the first tab before the last tab moves it to the middle
+	rects = tabRects()
+	drag(0, unison.Point{X: rects[2].X + 1, Y: rects[2].CenterY()})
+	check.Equal(t, []string{"b", "a", "c"}, tabTitles(tp))
+}
@@ -1504,8 +1504,7 @@ func (t *Table[T]) SyncToModel() {
 	rect := t.FrameRect()
 	rect.Size = pref
 	t.SetFrameRect(rect)
-	t.MarkForRedraw()
-	t.MarkForLayoutRecursivelyUpward()
+	t.InvalidateLayout()
 }
 
 func (t *Table[T]) countOpenRowChildrenRecursively(row T) int {
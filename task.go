@@ -10,6 +10,7 @@
 package unison
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -20,8 +21,16 @@ import (
 var (
 	taskQueueLock sync.Mutex
 	taskQueue     []func()
+	// afterFunc calls f on its own goroutine once the duration has elapsed. Tests substitute a fake clock here.
+	afterFunc = func(d time.Duration, f func()) stoppable { return time.AfterFunc(d, f) }
 )
 
+// stoppable is the part of *time.Timer used to cancel a delayed call. Stop returns false if the call has already been
+// made or stopped.
+type stoppable interface {
+	Stop() bool
+}
+
 // InvokeTask calls a function on the UI thread. The function is put into the system event queue and will be run at the
 // next opportunity.
 func InvokeTask(f func()) {
@@ -33,7 +42,79 @@ func InvokeTask(f func()) {
 
 // InvokeTaskAfter schedules a function to be run on the UI thread after waiting for the specified duration.
 func InvokeTaskAfter(f func(), after time.Duration) {
-	time.AfterFunc(after, func() { InvokeTask(f) })
+	afterFunc(after, func() { InvokeTask(f) })
+}
+
+// InvokeTaskAfterWithContext schedules a function to be run on the UI thread after waiting for the specified duration,
+// unless the context is done before then.
+func InvokeTaskAfterWithContext(ctx context.Context, f func(), after time.Duration) {
+	if ctx.Err() != nil {
+		return
+	}
+	stopCh := make(chan func() bool, 1)
+	timer := afterFunc(after, func() {
+		// Release the context's reference to the timer, since it is no longer needed
+		(<-stopCh)()
+		InvokeTask(func() {
+			if ctx.Err() == nil {
+				f()
+			}
+		})
+	})
+	stopCh <- context.AfterFunc(ctx, func() { timer.Stop() })
+}
+
+// InvokeTaskAndWait calls a function on the UI thread and waits for it to complete. If the function panics, the panic
+// is returned as an error. This must not be called from the UI thread, as it would deadlock.
+func InvokeTaskAndWait(f func()) error {
+	_, err := InvokeTaskForResult(context.Background(), func() (struct{}, error) {
+		f()
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// InvokeTaskForResult calls a function on the UI thread and waits for its result. If the context is done before the
+// function has started, the function will not be called and the context's error is returned. If the context is done
+// while the function is running, this returns immediately with the context's error, although the function will still
+// run to completion on the UI thread. If the function panics, the panic is returned as an error. This must not be
+// called from the UI thread, as it would deadlock.
+func InvokeTaskForResult[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	ch := make(chan result, 1)
+	InvokeTask(func() {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			ch <- result{value: zero, err: err}
+			return
+		}
+		var r result
+		toolbox.CallWithHandler(func() { r.value, r.err = f() }, func(err error) { r.err = err })
+		ch <- r
+	})
+	select {
+	case r := <-ch:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// RunPendingTasks runs the tasks that are currently waiting in the UI task queue. Tasks that get queued while this is
+// running are left for a subsequent call. This is normally handled by the event loop started by Start(), but is useful
+// when driving windows without it, such as offscreen windows in tests. Must be called on the UI thread.
+func RunPendingTasks() {
+	taskQueueLock.Lock()
+	pending := taskQueue
+	taskQueue = nil
+	taskQueueLock.Unlock()
+	for _, f := range pending {
+		toolbox.CallWithHandler(f, uiTaskRecovery)
+	}
 }
 
 func processNextTask(recoveryHandler errs.RecoveryHandler) {
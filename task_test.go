@@ -0,0 +1,87 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+// runTasksUntil stands in for the UI event loop, running queued tasks until done is closed.
+func runTasksUntil(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			unison.RunPendingTasks()
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestInvokeTaskForResult(t *testing.T) {
+	done := make(chan struct{})
+	var value int
+	var err error
+	go func() {
+		defer close(done)
+		value, err = unison.InvokeTaskForResult(context.Background(), func() (int, error) { return 42, nil })
+	}()
+	runTasksUntil(done)
+	check.NoError(t, err)
+	check.Equal(t, 42, value)
+
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		err = unison.InvokeTaskAndWait(func() { panic("boom") })
+	}()
+	runTasksUntil(done)
+	check.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err = unison.InvokeTaskForResult(ctx, func() (int, error) {
+			called = true
+			return 0, nil
+		})
+	}()
+	runTasksUntil(done)
+	check.True(t, errors.Is(err, context.Canceled))
+	unison.RunPendingTasks()
+	check.False(t, called)
+}
+
+func TestDebouncer(t *testing.T) {
+	count := 0
+	done := make(chan struct{})
+	d := unison.NewDebouncer(20*time.Millisecond, func() {
+		count++
+		close(done)
+	})
+	for i := 0; i < 5; i++ {
+		d.Trigger()
+	}
+	runTasksUntil(done)
+	time.Sleep(40 * time.Millisecond)
+	unison.RunPendingTasks()
+	check.Equal(t, 1, count)
+	check.False(t, d.Pending())
+}
@@ -0,0 +1,200 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ddkwork/toolbox/i18n"
+)
+
+// LastTimeOfDay is the latest time of day a TimeField can hold.
+const LastTimeOfDay = 24*time.Hour - time.Second
+
+// TimeField holds a time of day, expressed as the time since midnight, that can be edited. The up and down arrow keys,
+// and the mouse wheel while the field has the focus, step the time by Step. Page up and page down step it by an hour.
+type TimeField struct {
+	*Field
+	// Conventions controls how times are presented and parsed. Call SetValue() after changing it to update the text.
+	Conventions DateConventions
+	// Step is the amount the time changes by for each step. A value of 0 disables stepping.
+	Step        time.Duration
+	minimum     time.Duration
+	maximum     time.Duration
+	showSeconds bool
+}
+
+// NewTimeField creates a new TimeField holding the time of day. Seconds are only shown if showSeconds is true. The Step
+// is initially 15 minutes, or 1 second if seconds are shown.
+func NewTimeField(timeOfDay time.Duration, showSeconds bool) *TimeField {
+	f := &TimeField{
+		Field:       NewField(),
+		Conventions: DefaultDateConventions,
+		Step:        15 * time.Minute,
+		maximum:     LastTimeOfDay,
+		showSeconds: showSeconds,
+	}
+	if showSeconds {
+		f.Step = time.Second
+	}
+	f.Self = f
+	f.LostFocusCallback = f.DefaultFocusLost
+	f.MouseWheelCallback = f.DefaultMouseWheel
+	f.KeyDownCallback = f.DefaultKeyDown
+	f.ValidateCallback = f.DefaultValidate
+	f.adjustMinimumTextWidth()
+	f.SetValue(timeOfDay)
+	return f
+}
+
+// Value returns the time of day in the field, constrained to the minimum and maximum. If the text is not a valid time,
+// the minimum is returned.
+func (f *TimeField) Value() time.Duration {
+	v, _ := f.Conventions.ParseTime(f.Text()) //nolint:errcheck // Default value in case of error is acceptable
+	return min(max(v, f.minimum), f.maximum)
+}
+
+// SetValue sets the time of day in the field, constrained to the minimum and maximum.
+func (f *TimeField) SetValue(timeOfDay time.Duration) {
+	text := f.Conventions.FormatTime(min(max(timeOfDay, f.minimum), f.maximum), f.showSeconds)
+	if text != f.Text() {
+		f.SetText(text)
+	}
+}
+
+// Min returns the earliest time of day allowed.
+func (f *TimeField) Min() time.Duration {
+	return f.minimum
+}
+
+// Max returns the latest time of day allowed.
+func (f *TimeField) Max() time.Duration {
+	return f.maximum
+}
+
+// SetMinMax sets the earliest and latest times of day allowed, which are limited to the range 0 to LastTimeOfDay.
+func (f *TimeField) SetMinMax(minimum, maximum time.Duration) {
+	minimum = min(max(minimum, 0), LastTimeOfDay)
+	maximum = min(max(maximum, minimum), LastTimeOfDay)
+	if f.minimum != minimum || f.maximum != maximum {
+		f.minimum = minimum
+		f.maximum = maximum
+		f.Validate()
+	}
+}
+
+// ShowSeconds returns true if seconds are shown.
+func (f *TimeField) ShowSeconds() bool {
+	return f.showSeconds
+}
+
+// SetShowSeconds sets whether seconds are shown.
+func (f *TimeField) SetShowSeconds(show bool) {
+	if f.showSeconds != show {
+		v := f.Value()
+		f.showSeconds = show
+		f.adjustMinimumTextWidth()
+		f.SetValue(v)
+	}
+}
+
+// StepValue changes the time of day by the given number of steps, first aligning it to a multiple of Step.
+func (f *TimeField) StepValue(steps int) {
+	f.stepBy(steps, f.Step)
+}
+
+// DefaultFocusLost is the default implementation for the LostFocusCallback.
+func (f *TimeField) DefaultFocusLost() {
+	if _, err := f.Conventions.ParseTime(f.Text()); err == nil {
+		f.SetValue(f.Value())
+	}
+	f.Field.DefaultFocusLost()
+}
+
+// DefaultMouseWheel is the default implementation for the MouseWheelCallback. The value is only stepped while the field
+// has the focus, so that scrolling past it doesn't alter it.
+func (f *TimeField) DefaultMouseWheel(_, delta Point, _ Modifiers) bool {
+	if f.Step <= 0 || !f.Enabled() || !f.Focused() || delta.Y == 0 {
+		return false
+	}
+	if delta.Y > 0 {
+		f.StepValue(1)
+	} else {
+		f.StepValue(-1)
+	}
+	return true
+}
+
+// DefaultKeyDown is the default implementation for the KeyDownCallback.
+func (f *TimeField) DefaultKeyDown(keyCode KeyCode, mod Modifiers, repeat bool) bool {
+	if f.Step > 0 && f.Enabled() && !mod.OSMenuCmdModifierDown() {
+		switch keyCode {
+		case KeyUp:
+			f.StepValue(1)
+			return true
+		case KeyDown:
+			f.StepValue(-1)
+			return true
+		case KeyPageUp:
+			f.stepBy(1, time.Hour)
+			return true
+		case KeyPageDown:
+			f.stepBy(-1, time.Hour)
+			return true
+		default:
+		}
+	}
+	return f.Field.DefaultKeyDown(keyCode, mod, repeat)
+}
+
+// DefaultValidate is the default implementation for the ValidateCallback.
+func (f *TimeField) DefaultValidate() bool {
+	if text := f.tooltipTextForValidation(); text != "" {
+		f.Tooltip = NewTooltipWithText(text)
+		return false
+	}
+	f.Tooltip = nil
+	return true
+}
+
+func (f *TimeField) tooltipTextForValidation() string {
+	v, err := f.Conventions.ParseTime(strings.TrimSpace(f.Text()))
+	if err != nil {
+		return err.Error()
+	}
+	if v < f.minimum {
+		return fmt.Sprintf(i18n.Text("Time must be no earlier than %s"), f.Conventions.FormatTime(f.minimum,
+			f.showSeconds))
+	}
+	if v > f.maximum {
+		return fmt.Sprintf(i18n.Text("Time must be no later than %s"), f.Conventions.FormatTime(f.maximum,
+			f.showSeconds))
+	}
+	return ""
+}
+
+func (f *TimeField) stepBy(steps int, step time.Duration) {
+	if step <= 0 || steps == 0 {
+		return
+	}
+	v := f.Value()
+	aligned := v.Truncate(step)
+	if aligned != v && steps < 0 {
+		steps++
+	}
+	f.SetValue(aligned + time.Duration(steps)*step)
+}
+
+func (f *TimeField) adjustMinimumTextWidth() {
+	f.SetMinimumTextWidthUsing(f.Conventions.FormatTime(22*time.Hour+58*time.Minute+58*time.Second, f.showSeconds),
+		f.Conventions.FormatTime(10*time.Hour+58*time.Minute+58*time.Second, f.showSeconds))
+}
@@ -0,0 +1,105 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+)
+
+var _ TimedAnimation = &Timeline{}
+
+// Timeline groups animations together, starting each one at a specific offset from the start of the timeline. The
+// timeline finishes once all of its animations have finished.
+type Timeline struct {
+	entries []*timelineEntry
+	end     time.Duration
+}
+
+type timelineEntry struct {
+	animation Animation
+	offset    time.Duration
+	done      bool
+}
+
+// NewTimeline creates a new, empty Timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Add the animation to the timeline, starting it at the given offset from the start of the timeline. Returns the
+// timeline, to allow chaining.
+func (t *Timeline) Add(animation Animation, offset time.Duration) *Timeline {
+	t.entries = append(t.entries, &timelineEntry{
+		animation: animation,
+		offset:    offset,
+	})
+	if t.end >= 0 {
+		if timed, ok := animation.(TimedAnimation); ok {
+			if d := timed.TotalDuration(); d >= 0 {
+				t.end = max(t.end, offset+d)
+			} else {
+				t.end = -1
+			}
+		} else {
+			t.end = -1
+		}
+	}
+	return t
+}
+
+// Append the animation to the timeline, starting it when all of the timed animations previously added have finished.
+// Animations without a known duration, such as a Spring, start at the same point as they would have without them.
+// Returns the timeline, to allow chaining.
+func (t *Timeline) Append(animation Animation) *Timeline {
+	var offset time.Duration
+	for _, entry := range t.entries {
+		if timed, ok := entry.animation.(TimedAnimation); ok {
+			if d := timed.TotalDuration(); d >= 0 {
+				offset = max(offset, entry.offset+d)
+			}
+		}
+	}
+	return t.Add(animation, offset)
+}
+
+// With adds the animation to the timeline, starting it at the same offset as the most recently added animation.
+// Returns the timeline, to allow chaining.
+func (t *Timeline) With(animation Animation) *Timeline {
+	var offset time.Duration
+	if len(t.entries) != 0 {
+		offset = t.entries[len(t.entries)-1].offset
+	}
+	return t.Add(animation, offset)
+}
+
+// TotalDuration implements TimedAnimation. Returns a negative value if the timeline contains any animations whose
+// duration is not known.
+func (t *Timeline) TotalDuration() time.Duration {
+	return t.end
+}
+
+// Advance implements Animation.
+func (t *Timeline) Advance(elapsed time.Duration) bool {
+	done := true
+	for _, entry := range t.entries {
+		if elapsed < entry.offset {
+			entry.done = false
+			done = false
+			continue
+		}
+		if !entry.done {
+			entry.done = entry.animation.Advance(elapsed - entry.offset)
+			if !entry.done {
+				done = false
+			}
+		}
+	}
+	return done
+}
@@ -0,0 +1,364 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/unison/enums/paintstyle"
+)
+
+// DefaultToolbarTheme holds the default ToolbarTheme values for Toolbars. Modifying this data will not alter existing
+// Toolbars, but will alter any Toolbars created in the future.
+var DefaultToolbarTheme = ToolbarTheme{
+	BackgroundInk:   BackgroundColor,
+	SeparatorInk:    DividerColor,
+	ButtonTheme:     DefaultSVGButtonTheme,
+	Insets:          Insets{Top: 2, Left: 4, Bottom: 2, Right: 4},
+	Gap:             2,
+	SeparatorMargin: 4,
+}
+
+// ToolbarTheme holds theming data for a Toolbar.
+type ToolbarTheme struct {
+	BackgroundInk Ink
+	SeparatorInk  Ink
+	// ButtonTheme is applied to the buttons created for actions.
+	ButtonTheme ButtonTheme
+	Insets      Insets
+	// Gap is the space between adjacent items.
+	Gap float32
+	// SeparatorMargin is the space on either side of a separator line, in addition to the Gap.
+	SeparatorMargin float32
+	// ShowTitles causes action titles to be shown next to their icons. Actions without an icon always show their
+	// title.
+	ShowTitles bool
+}
+
+// Toolbar is a horizontal row of buttons created from Actions, optionally broken up with separators and spacers. The
+// enabled state of each button, and the selected state of toggle buttons, is refreshed from its action just before each
+// frame the toolbar's window draws, and may also be refreshed explicitly with UpdateItems(). Items that don't fit are
+// moved into a menu shown by an overflow button at the end of the toolbar.
+type Toolbar struct {
+	Panel
+	ToolbarTheme
+	// MenuFactory is used to create the overflow menu.
+	MenuFactory    MenuFactory
+	items          []*toolbarItem
+	overflowButton *Button
+	refreshWnd     *Window
+	refreshID      FrameCallbackID
+}
+
+type toolbarItem struct {
+	panel   *Panel
+	button  *Button
+	action  *Action
+	checked func() bool
+	// width is the fixed width of a spacer, or 0 for a flexible spacer. Unused for other item types.
+	width     float32
+	separator bool
+	spacer    bool
+	tipTitle  string
+}
+
+// NewToolbar creates a new, empty, Toolbar.
+func NewToolbar() *Toolbar {
+	t := &Toolbar{
+		ToolbarTheme: DefaultToolbarTheme,
+		MenuFactory:  DefaultMenuFactory(),
+	}
+	t.Self = t
+	t.SetLayout(t)
+	t.DrawCallback = t.DefaultDraw
+	t.overflowButton = NewSVGButton(ChevronRightSVG)
+	t.overflowButton.ButtonTheme = t.ButtonTheme
+	t.overflowButton.HideBase = true
+	t.overflowButton.SetFocusable(false)
+	t.overflowButton.ClickCallback = t.showOverflowMenu
+	t.overflowButton.Hidden = true
+	t.AddChild(t.overflowButton)
+	return t
+}
+
+// AddAction appends a button for the action, returning the button so that it may be further customized. The icon may
+// be nil, in which case the action's title is shown instead.
+func (t *Toolbar) AddAction(action *Action, icon *SVG) *Button {
+	return t.addButton(action, icon, nil)
+}
+
+// AddToggleAction appends a button for an action that toggles some state on and off, returning the button so that it
+// may be further customized. The checked function should return the current state, which is reflected by the button's
+// selected state and by the check mark on the item in the overflow menu. The icon may be nil, in which case the
+// action's title is shown instead.
+func (t *Toolbar) AddToggleAction(action *Action, icon *SVG, checked func() bool) *Button {
+	return t.addButton(action, icon, checked)
+}
+
+// AddSeparator appends a separator line.
+func (t *Toolbar) AddSeparator() {
+	s := NewSeparator()
+	s.LineInk = t.SeparatorInk
+	s.Vertical = true
+	s.SetBorder(NewEmptyBorder(Insets{Left: t.SeparatorMargin, Right: t.SeparatorMargin}))
+	t.addItem(&toolbarItem{panel: s.AsPanel(), separator: true})
+}
+
+// AddSpacer appends empty space of the given width. A width of 0 or less creates a flexible spacer, which shares any
+// space left over after the other items have been laid out with the other flexible spacers.
+func (t *Toolbar) AddSpacer(width float32) {
+	p := NewPanel()
+	t.addItem(&toolbarItem{panel: p, width: max(width, 0), spacer: true})
+}
+
+// RemoveAllItems removes all items from the toolbar.
+func (t *Toolbar) RemoveAllItems() {
+	for _, item := range t.items {
+		item.panel.RemoveFromParent()
+	}
+	t.items = nil
+	t.MarkForLayoutAndRedraw()
+}
+
+// OverflowActions returns the actions whose buttons did not fit during the last layout and are only available via the
+// overflow menu.
+func (t *Toolbar) OverflowActions() []*Action {
+	var actions []*Action
+	for _, item := range t.items {
+		if item.action != nil && item.panel.Hidden {
+			actions = append(actions, item.action)
+		}
+	}
+	return actions
+}
+
+// UpdateItems refreshes the title, tooltip, enabled state and toggle state of each button from its action.
+func (t *Toolbar) UpdateItems() {
+	for _, item := range t.items {
+		if item.action == nil {
+			continue
+		}
+		b := item.button
+		if b.Text != "" && b.Text != item.action.Title {
+			b.Text = item.action.Title
+			b.MarkForLayoutAndRedraw()
+		}
+		if item.tipTitle != item.action.Title || b.Tooltip == nil {
+			item.tipTitle = item.action.Title
+			if item.action.KeyBinding.ShouldOmit() {
+				b.Tooltip = NewTooltipWithText(item.action.Title)
+			} else {
+				b.Tooltip = NewTooltipWithSecondaryText(item.action.Title, item.action.KeyBinding.String())
+			}
+		}
+		b.SetEnabled(item.action.Enabled(b))
+		if item.checked != nil {
+			b.SetSelected(item.checked())
+		}
+	}
+}
+
+// DefaultDraw provides the default drawing.
+func (t *Toolbar) DefaultDraw(gc *Canvas, rect Rect) {
+	gc.DrawRect(rect, t.BackgroundInk.Paint(gc, rect, paintstyle.Fill))
+}
+
+// LayoutSizes implements Layout.
+func (t *Toolbar) LayoutSizes(_ *Panel, _ Size) (minSize, prefSize, maxSize Size) {
+	_, overflowSize, _ := t.overflowButton.Sizes(Size{})
+	across := overflowSize.Height
+	for i, item := range t.items {
+		size := t.itemSize(item)
+		prefSize.Width += size.Width
+		if i != 0 {
+			prefSize.Width += t.Gap
+		}
+		across = max(across, size.Height)
+	}
+	minSize.Width = overflowSize.Width
+	prefSize.Width = max(prefSize.Width, minSize.Width)
+	minSize.Height = across
+	prefSize.Height = across
+	minSize.AddInsets(t.Insets)
+	prefSize.AddInsets(t.Insets)
+	if border := t.Border(); border != nil {
+		insets := border.Insets()
+		minSize.AddInsets(insets)
+		prefSize.AddInsets(insets)
+	}
+	return minSize, prefSize, Size{Width: DefaultMaxSize, Height: prefSize.Height}
+}
+
+// PerformLayout implements Layout.
+func (t *Toolbar) PerformLayout(_ *Panel) {
+	r := t.ContentRect(false)
+	r.Inset(t.Insets)
+	sizes := make([]Size, len(t.items))
+	var total float32
+	for i, item := range t.items {
+		sizes[i] = t.itemSize(item)
+		total += sizes[i].Width
+		if i != 0 {
+			total += t.Gap
+		}
+	}
+	visible := len(t.items)
+	_, overflowSize, _ := t.overflowButton.Sizes(Size{})
+	if total > r.Width {
+		avail := r.Width - (overflowSize.Width + t.Gap)
+		for visible > 0 && total > avail {
+			visible--
+			total -= sizes[visible].Width
+			if visible != 0 {
+				total -= t.Gap
+			}
+		}
+		// Don't leave a separator or spacer dangling at the end of the visible items
+		for visible > 0 && t.items[visible-1].action == nil {
+			visible--
+			total -= sizes[visible].Width
+			if visible != 0 {
+				total -= t.Gap
+			}
+		}
+	}
+	flexible := 0
+	for _, item := range t.items[:visible] {
+		if item.spacer && item.width == 0 {
+			flexible++
+		}
+	}
+	var extra float32
+	if flexible != 0 && visible == len(t.items) {
+		extra = max(r.Width-total, 0) / float32(flexible)
+	}
+	x := r.X
+	for i, item := range t.items {
+		item.panel.Hidden = i >= visible
+		if item.panel.Hidden {
+			continue
+		}
+		width := sizes[i].Width
+		if item.spacer && item.width == 0 {
+			width += extra
+		}
+		frame := NewRect(x, r.Y+(r.Height-sizes[i].Height)/2, width, sizes[i].Height)
+		if item.separator {
+			frame.Y = r.Y
+			frame.Height = r.Height
+		}
+		frame.Align()
+		item.panel.SetFrameRect(frame)
+		x += width + t.Gap
+	}
+	t.overflowButton.Hidden = visible == len(t.items)
+	if !t.overflowButton.Hidden {
+		frame := NewRect(r.Right()-overflowSize.Width, r.Y+(r.Height-overflowSize.Height)/2, overflowSize.Width,
+			overflowSize.Height)
+		frame.Align()
+		t.overflowButton.SetFrameRect(frame)
+	}
+	t.scheduleRefresh()
+}
+
+// scheduleRefresh arranges for the items to be refreshed from their actions before the next frame of the toolbar's
+// window. The request doesn't cause a frame to be drawn by itself, so an idle window stays idle.
+func (t *Toolbar) scheduleRefresh() {
+	if t.refreshWnd != nil {
+		t.refreshWnd.CancelAnimationFrame(t.refreshID)
+		t.refreshWnd = nil
+	}
+	if w := t.Window(); w != nil {
+		t.refreshWnd = w
+		t.refreshID = w.requestAnimationFrame(t.AsPanel(), t.refresh, false)
+	}
+}
+
+func (t *Toolbar) refresh(_ time.Time) {
+	t.refreshWnd = nil
+	t.UpdateItems()
+	t.scheduleRefresh()
+}
+
+func (t *Toolbar) addButton(action *Action, icon *SVG, checked func() bool) *Button {
+	var b *Button
+	if icon != nil {
+		b = NewSVGButton(icon)
+		drawable := b.Drawable
+		b.ButtonTheme = t.ButtonTheme
+		b.Drawable = drawable
+		if t.ShowTitles {
+			b.Text = action.Title
+		}
+	} else {
+		b = NewButton()
+		b.ButtonTheme = t.ButtonTheme
+		b.Text = action.Title
+	}
+	b.HideBase = checked == nil
+	b.Sticky = checked != nil
+	b.SetFocusable(false)
+	b.ClickCallback = func() {
+		action.Execute(b)
+		t.UpdateItems()
+	}
+	t.addItem(&toolbarItem{panel: b.AsPanel(), button: b, action: action, checked: checked})
+	t.UpdateItems()
+	return b
+}
+
+func (t *Toolbar) addItem(item *toolbarItem) {
+	t.items = append(t.items, item)
+	// The overflow button is kept as the last child
+	t.AddChildAtIndex(item.panel, len(t.items)-1)
+	t.MarkForLayoutAndRedraw()
+}
+
+func (t *Toolbar) itemSize(item *toolbarItem) Size {
+	if item.spacer {
+		return Size{Width: item.width}
+	}
+	_, size, _ := item.panel.Sizes(Size{})
+	return size
+}
+
+func (t *Toolbar) showOverflowMenu() {
+	m := t.MenuFactory.NewMenu(PopupMenuTemporaryBaseID, "", nil)
+	defer m.Dispose()
+	for i, item := range t.items {
+		if !item.panel.Hidden {
+			continue
+		}
+		switch {
+		case item.separator:
+			if m.Count() != 0 {
+				m.InsertSeparator(-1, true)
+			}
+		case item.action != nil:
+			m.InsertItem(-1, t.newOverflowMenuItem(m.Factory(), PopupMenuTemporaryBaseID+i+1, item))
+		}
+	}
+	if m.Count() != 0 {
+		m.Popup(t.overflowButton.RectToRoot(t.overflowButton.ContentRect(true)), 0)
+	}
+}
+
+func (t *Toolbar) newOverflowMenuItem(f MenuFactory, id int, item *toolbarItem) MenuItem {
+	action := item.action
+	return f.NewItem(id, action.Title, action.KeyBinding, func(mi MenuItem) bool {
+		if item.checked != nil {
+			mi.SetCheckState(CheckStateFromBool(item.checked()))
+		}
+		return action.Enabled(mi)
+	}, func(mi MenuItem) {
+		action.Execute(mi)
+		t.UpdateItems()
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func prefWidth(p *unison.Panel) float32 {
+	_, pref, _ := p.Sizes(unison.Size{})
+	return pref.Width
+}
+
+func layoutToolbar(tb *unison.Toolbar, width float32) {
+	tb.SetFrameRect(unison.Rect{Size: unison.Size{Width: width, Height: 30}})
+	tb.ValidateLayout()
+}
+
+func TestToolbarFlexibleSpacers(t *testing.T) {
+	tb := unison.NewToolbar()
+	tb.Insets = unison.Insets{}
+	a := tb.AddAction(&unison.Action{ID: 1, Title: "One"}, nil)
+	tb.AddSpacer(0)
+	b := tb.AddAction(&unison.Action{ID: 2, Title: "Two"}, nil)
+	tb.AddSpacer(0)
+	c := tb.AddAction(&unison.Action{ID: 3, Title: "Three"}, nil)
+	gap := tb.Gap
+	used := prefWidth(a.AsPanel()) + prefWidth(b.AsPanel()) + prefWidth(c.AsPanel()) + 4*gap
+	check.Equal(t, used, prefWidth(tb.AsPanel()), "flexible spacers take no space in the preferred size")
+
+	layoutToolbar(tb, used+100)
+	check.Equal(t, float32(0), a.FrameRect().X)
+	check.Equal(t, a.FrameRect().Right()+gap+50+gap, b.FrameRect().X, "extra space is shared between the spacers")
+	check.Equal(t, used+100, c.FrameRect().Right())
+	check.Equal(t, 0, len(tb.OverflowActions()))
+
+	layoutToolbar(tb, used-1)
+	check.True(t, c.Hidden)
+	check.False(t, b.Hidden, "the spacer left dangling after b is hidden instead of b")
+	check.True(t, tb.Children()[3].Hidden)
+	check.False(t, tb.Children()[1].Hidden)
+}
+
+func TestToolbarOverflow(t *testing.T) {
+	tb := unison.NewToolbar()
+	tb.Insets = unison.Insets{}
+	actionA := &unison.Action{ID: 1, Title: "One"}
+	actionB := &unison.Action{ID: 2, Title: "Two"}
+	actionC := &unison.Action{ID: 3, Title: "Three"}
+	a := tb.AddAction(actionA, nil)
+	tb.AddSeparator()
+	b := tb.AddAction(actionB, nil)
+	tb.AddSpacer(10)
+	c := tb.AddAction(actionC, nil)
+	children := tb.Children()
+	separator := children[1]
+	spacer := children[3]
+	overflow := children[len(children)-1]
+	gap := tb.Gap
+	throughSpacer := prefWidth(a.AsPanel()) + gap + prefWidth(separator) + gap + prefWidth(b.AsPanel()) + gap + 10
+	overflowWidth := gap + prefWidth(overflow)
+
+	layoutToolbar(tb, throughSpacer+gap+prefWidth(c.AsPanel()))
+	check.True(t, overflow.Hidden)
+	check.Equal(t, 0, len(tb.OverflowActions()))
+
+	// Only c needs to move into the overflow menu, but the spacer would then be left dangling at the end
+	layoutToolbar(tb, throughSpacer+overflowWidth)
+	check.False(t, b.Hidden)
+	check.True(t, spacer.Hidden)
+	check.True(t, c.Hidden)
+	check.False(t, overflow.Hidden)
+	check.Equal(t, throughSpacer+overflowWidth, overflow.FrameRect().Right())
+	check.Equal(t, []*unison.Action{actionC}, tb.OverflowActions())
+
+	// Only a and the separator fit, but the separator would then be left dangling at the end
+	layoutToolbar(tb, prefWidth(a.AsPanel())+gap+prefWidth(separator)+overflowWidth)
+	check.False(t, a.Hidden)
+	check.True(t, separator.Hidden)
+	check.True(t, b.Hidden)
+	check.Equal(t, []*unison.Action{actionB, actionC}, tb.OverflowActions())
+
+	layoutToolbar(tb, 1)
+	check.True(t, a.Hidden)
+	check.False(t, overflow.Hidden)
+	check.Equal(t, []*unison.Action{actionA, actionB, actionC}, tb.OverflowActions())
+}
+
+func TestToolbarRefreshesBeforeEachFrame(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 200, Height: 40}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	enabled := true
+	tb := unison.NewToolbar()
+	b := tb.AddAction(&unison.Action{
+		ID:              1,
+		Title:           "One",
+		EnabledCallback: func(_ *unison.Action, _ any) bool { return enabled },
+	}, nil)
+	wnd.SetContent(tb)
+	wnd.ValidateLayout()
+	check.True(t, b.Enabled())
+
+	enabled = false
+	check.True(t, b.Enabled(), "the button is only refreshed when a frame is drawn")
+	_, _ = wnd.Snapshot() //nolint:errcheck // Only the frame callbacks matter here
+	check.False(t, b.Enabled())
+	enabled = true
+	_, _ = wnd.Snapshot() //nolint:errcheck // Only the frame callbacks matter here
+	check.True(t, b.Enabled())
+}
@@ -0,0 +1,101 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+)
+
+var _ TimedAnimation = &Tween[float32]{}
+
+// Tween animates a value from one point to another over a fixed duration.
+type Tween[T Animatable] struct {
+	From     T
+	To       T
+	Duration time.Duration
+	// Delay is the amount of time to wait before the first pass starts.
+	Delay time.Duration
+	// Easing is applied to the progress of each pass. If nil, EaseLinear is used.
+	Easing Easing
+	// Repeat is the number of additional passes to make after the first. A negative value repeats forever.
+	Repeat int
+	// AutoReverse causes every other pass to run from To back to From.
+	AutoReverse bool
+	// UpdateCallback, if set, is called with the new value each time the tween advances.
+	UpdateCallback func(value T)
+	value          T
+}
+
+// NewTween creates a new Tween.
+func NewTween[T Animatable](from, to T, duration time.Duration, easing Easing, updateCallback func(value T)) *Tween[T] {
+	return &Tween[T]{
+		From:           from,
+		To:             to,
+		Duration:       duration,
+		Easing:         easing,
+		UpdateCallback: updateCallback,
+		value:          from,
+	}
+}
+
+// Value returns the most recently computed value.
+func (t *Tween[T]) Value() T {
+	return t.value
+}
+
+// TotalDuration implements TimedAnimation.
+func (t *Tween[T]) TotalDuration() time.Duration {
+	if t.Repeat < 0 {
+		return -1
+	}
+	return t.Delay + t.Duration*time.Duration(t.Repeat+1)
+}
+
+// Advance implements Animation.
+func (t *Tween[T]) Advance(elapsed time.Duration) bool {
+	elapsed -= t.Delay
+	if elapsed < 0 {
+		t.set(t.From)
+		return false
+	}
+	done := false
+	var pass int
+	var progress float32
+	if t.Duration <= 0 {
+		pass = max(t.Repeat, 0)
+		progress = 1
+		done = true
+	} else {
+		pass = int(elapsed / t.Duration)
+		progress = float32(elapsed%t.Duration) / float32(t.Duration)
+		if t.Repeat >= 0 && pass > t.Repeat {
+			pass = t.Repeat
+			progress = 1
+			done = true
+		}
+	}
+	easing := t.Easing
+	if easing == nil {
+		easing = EaseLinear
+	}
+	if t.AutoReverse && pass%2 == 1 {
+		t.set(Lerp(t.To, t.From, easing(progress)))
+	} else {
+		t.set(Lerp(t.From, t.To, easing(progress)))
+	}
+	return done
+}
+
+func (t *Tween[T]) set(value T) {
+	t.value = value
+	if t.UpdateCallback != nil {
+		t.UpdateCallback(value)
+	}
+}
@@ -0,0 +1,586 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison/enums/align"
+)
+
+// UIDescription describes a panel and its children, so that a panel tree can be built at runtime from a JSON document
+// with BuildUI(). For example:
+//
+//	{
+//	  "type": "Panel",
+//	  "layout": {"type": "flex", "columns": 2, "hSpacing": 4, "vSpacing": 4},
+//	  "border": {"type": "empty", "insets": {"top": 10, "left": 10, "bottom": 10, "right": 10}},
+//	  "children": [
+//	    {"type": "Label", "props": {"text": "Name:"}, "layoutData": {"hAlign": "end"}},
+//	    {"type": "Field", "ref": "name", "props": {"tooltip": "Your full name"},
+//	     "layoutData": {"hAlign": "fill", "hGrab": true}},
+//	    {"type": "CheckBox", "ref": "remember", "props": {"text": "Remember me", "checked": true},
+//	     "theme": {"onBackgroundInk": "#336699"}, "layoutData": {"hSpan": 2}}
+//	  ]
+//	}
+type UIDescription struct {
+	// Type is the name the widget type was registered with. See RegisterUIType().
+	Type string `json:"type"`
+	// RefKey is assigned to the panel's RefKey field and may be used to retrieve the panel via UI.Lookup().
+	RefKey string `json:"ref,omitempty"`
+	// Properties are set on the widget. Properties specific to the widget type are handled first, then the remaining
+	// ones are matched, ignoring case, to a method named "Set" followed by the property name, or failing that, to an
+	// exported field, such as those of the widget's embedded theme. The special property "tooltip" takes the text of
+	// a tooltip.
+	Properties UIProperties `json:"props,omitempty"`
+	// Theme overrides the values of the widget's theme fields, such as "font" or "onBackgroundInk". Inks are specified
+	// as colors, fonts as font descriptors, and durations as strings like "150ms".
+	Theme UIProperties `json:"theme,omitempty"`
+	// Border, if set, describes the border to use. Its "type" may be "empty" or "line". Both take "insets", while line
+	// borders also take "ink", "cornerRadius" and "noInset".
+	Border json.RawMessage `json:"border,omitempty"`
+	// Layout, if set, describes the layout to use. Its "type" is the name the layout type was registered with, and its
+	// remaining values are the fields of the layout. See RegisterUILayout().
+	Layout json.RawMessage `json:"layout,omitempty"`
+	// LayoutData, if set, is the layout data for the parent's layout.
+	LayoutData json.RawMessage `json:"layoutData,omitempty"`
+	// Children are the child panels.
+	Children []*UIDescription `json:"children,omitempty"`
+}
+
+// UIProperties holds properties by name, in their raw JSON form.
+type UIProperties map[string]json.RawMessage
+
+// Take removes the named property, if present, decoding it into target, which must be a pointer. Returns true if the
+// property was present.
+func (p UIProperties) Take(name string, target any) (bool, error) {
+	for key, raw := range p {
+		if strings.EqualFold(key, name) {
+			delete(p, key)
+			if err := decodeUIValue(raw, reflect.ValueOf(target).Elem()); err != nil {
+				return true, errs.NewWithCausef(err, "invalid value for property '%s'", key)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UIType defines how a widget type is created from a UIDescription.
+type UIType struct {
+	// Create a new widget. Any properties specific to the widget type should be removed from props with
+	// UIProperties.Take(); the remaining ones will be applied generically.
+	Create func(ui *UI, props UIProperties) (Paneler, error)
+	// AddChild, if not nil, is called to add each child to the widget, rather than Panel.AddChild().
+	AddChild func(parent, child Paneler) error
+}
+
+// UILayoutType defines how a layout type is created from a UIDescription.
+type UILayoutType struct {
+	// Create a new layout. Its exported fields will then be filled in from the layout description.
+	Create func() Layout
+	// LayoutData, if not nil, decodes the layout data for a child.
+	LayoutData func(raw json.RawMessage) (any, error)
+	// Finish, if not nil, is called once the target's children have been added, with the raw layout description.
+	Finish func(layout Layout, target *Panel, raw json.RawMessage) error
+}
+
+var (
+	uiTypes = map[string]UIType{
+		"Panel":          {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewPanel(), nil }},
+		"Label":          {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewLabel(), nil }},
+		"Button":         {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewButton(), nil }},
+		"CheckBox":       {Create: createUICheckBox},
+		"RadioButton":    {Create: createUIRadioButton},
+		"Field":          {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewField(), nil }},
+		"MultiLineField": {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewMultiLineField(), nil }},
+		"PopupMenu":      {Create: createUIPopupMenu},
+		"ProgressBar":    {Create: createUIProgressBar},
+		"Separator":      {Create: func(_ *UI, _ UIProperties) (Paneler, error) { return NewSeparator(), nil }},
+		"ScrollPanel":    {Create: createUIScrollPanel, AddChild: addUIScrollPanelChild},
+		"Table":          {Create: createUITable},
+	}
+	uiLayoutTypes = map[string]UILayoutType{
+		"flex": {
+			Create: func() Layout { return &FlexLayout{} },
+			LayoutData: func(raw json.RawMessage) (any, error) {
+				data := &FlexLayoutData{HSpan: 1, VSpan: 1, VAlign: align.Middle}
+				if err := json.Unmarshal(raw, data); err != nil {
+					return nil, err
+				}
+				return data, nil
+			},
+		},
+		"flow": {
+			Create: func() Layout { return &FlowLayout{} },
+			LayoutData: func(raw json.RawMessage) (any, error) {
+				var data align.Enum
+				if err := json.Unmarshal(raw, &data); err != nil {
+					return nil, err
+				}
+				return data, nil
+			},
+		},
+		"flexbox": {
+			Create:     func() Layout { return &FlexBoxLayout{} },
+			LayoutData: decodeUILayoutData[FlexBoxLayoutData],
+		},
+		"grid": {
+			Create:     func() Layout { return &GridLayout{} },
+			LayoutData: decodeUILayoutData[GridLayoutData],
+		},
+		"stack": {
+			Create:     func() Layout { return &StackLayout{} },
+			LayoutData: decodeUILayoutData[StackLayoutData],
+		},
+		"constraint": {
+			Create: func() Layout { return &ConstraintLayout{} },
+			Finish: finishUIConstraintLayout,
+		},
+	}
+	uiInkType      = reflect.TypeFor[Ink]()
+	uiFontType     = reflect.TypeFor[Font]()
+	uiDurationType = reflect.TypeFor[time.Duration]()
+)
+
+// RegisterUIType makes a widget type available to BuildUI() under the given name, replacing any existing type with that
+// name. Should be called during initialization, before any UI is built.
+func RegisterUIType(name string, t UIType) {
+	uiTypes[name] = t
+}
+
+// RegisterUILayout makes a layout type available to BuildUI() under the given name, replacing any existing layout type
+// with that name. Should be called during initialization, before any UI is built.
+func RegisterUILayout(name string, t UILayoutType) {
+	uiLayoutTypes[name] = t
+}
+
+// UI holds the result of building a panel tree from a UIDescription.
+type UI struct {
+	Root   Paneler
+	refs   map[string]*Panel
+	groups map[string]*Group
+}
+
+// BuildUIFromJSON parses a JSON UIDescription and builds the panel tree it describes.
+func BuildUIFromJSON(data []byte) (*UI, error) {
+	var desc UIDescription
+	if err := json.Unmarshal(data, &desc); err != nil {
+		return nil, errs.NewWithCause("unable to parse UI description", err)
+	}
+	return BuildUI(&desc)
+}
+
+// BuildUIFromFS reads a JSON UIDescription from the file system and builds the panel tree it describes.
+func BuildUIFromFS(fileSystem fs.FS, filePath string) (*UI, error) {
+	data, err := fs.ReadFile(fileSystem, filePath)
+	if err != nil {
+		return nil, errs.NewWithCause(filePath, err)
+	}
+	var ui *UI
+	if ui, err = BuildUIFromJSON(data); err != nil {
+		return nil, errs.NewWithCause(filePath, err)
+	}
+	return ui, nil
+}
+
+// BuildUI builds the panel tree described by desc. Must be called on the UI thread.
+func BuildUI(desc *UIDescription) (*UI, error) {
+	ui := &UI{
+		refs:   make(map[string]*Panel),
+		groups: make(map[string]*Group),
+	}
+	root, err := ui.build(desc, nil, desc.Type)
+	if err != nil {
+		return nil, err
+	}
+	ui.Root = root
+	return ui, nil
+}
+
+// Lookup returns the panel with the given RefKey, or nil if there is none.
+func (ui *UI) Lookup(refKey string) *Panel {
+	return ui.refs[refKey]
+}
+
+// RefKeys returns the RefKeys of the panels in the UI, sorted.
+func (ui *UI) RefKeys() []string {
+	keys := make([]string, 0, len(ui.refs))
+	for k := range ui.refs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Group returns the radio button group with the given name, creating it if necessary.
+func (ui *UI) Group(name string) *Group {
+	g, ok := ui.groups[name]
+	if !ok {
+		g = NewGroup()
+		ui.groups[name] = g
+	}
+	return g
+}
+
+// UILookup returns the widget with the given RefKey, if it exists and is of type T.
+func UILookup[T Paneler](ui *UI, refKey string) (widget T, ok bool) {
+	if p := ui.Lookup(refKey); p != nil {
+		widget, ok = p.Self.(T)
+	}
+	return widget, ok
+}
+
+func (ui *UI) build(desc *UIDescription, parentLayout *UILayoutType, path string) (Paneler, error) {
+	t, ok := uiTypes[desc.Type]
+	if !ok {
+		return nil, errs.Newf("%s: unknown widget type '%s'", path, desc.Type)
+	}
+	props := make(UIProperties, len(desc.Properties))
+	for k, v := range desc.Properties {
+		props[k] = v
+	}
+	widget, err := t.Create(ui, props)
+	if err != nil {
+		return nil, errs.NewWithCausef(err, "%s: unable to create widget", path)
+	}
+	panel := widget.AsPanel()
+	if desc.RefKey != "" {
+		if _, exists := ui.refs[desc.RefKey]; exists {
+			return nil, errs.Newf("%s: duplicate ref '%s'", path, desc.RefKey)
+		}
+		panel.RefKey = desc.RefKey
+		ui.refs[desc.RefKey] = panel
+	}
+	var tooltip string
+	if _, err = props.Take("tooltip", &tooltip); err != nil {
+		return nil, errs.NewWithCause(path, err)
+	}
+	if tooltip != "" {
+		panel.Tooltip = NewTooltipWithText(tooltip)
+	}
+	if err = applyUIProperties(widget, props, true); err != nil {
+		return nil, errs.NewWithCause(path, err)
+	}
+	if err = applyUIProperties(widget, desc.Theme, false); err != nil {
+		return nil, errs.NewWithCause(path, err)
+	}
+	if len(desc.Border) != 0 {
+		var border Border
+		if border, err = decodeUIBorder(desc.Border); err != nil {
+			return nil, errs.NewWithCause(path, err)
+		}
+		panel.SetBorder(border)
+	}
+	if len(desc.LayoutData) != 0 {
+		if parentLayout == nil || parentLayout.LayoutData == nil {
+			return nil, errs.Newf("%s: layout data specified, but the parent's layout doesn't use it", path)
+		}
+		var data any
+		if data, err = parentLayout.LayoutData(desc.LayoutData); err != nil {
+			return nil, errs.NewWithCausef(err, "%s: invalid layout data", path)
+		}
+		panel.SetLayoutData(data)
+	}
+	var layoutType *UILayoutType
+	var layout Layout
+	if len(desc.Layout) != 0 {
+		if layoutType, layout, err = decodeUILayout(desc.Layout); err != nil {
+			return nil, errs.NewWithCause(path, err)
+		}
+		panel.SetLayout(layout)
+	}
+	for i, childDesc := range desc.Children {
+		var child Paneler
+		if child, err = ui.build(childDesc, layoutType, fmt.Sprintf("%s/%d:%s", path, i, childDesc.Type)); err != nil {
+			return nil, err
+		}
+		if t.AddChild != nil {
+			if err = t.AddChild(widget, child); err != nil {
+				return nil, errs.NewWithCause(path, err)
+			}
+		} else {
+			panel.AddChild(child)
+		}
+	}
+	if layoutType != nil && layoutType.Finish != nil {
+		if err = layoutType.Finish(layout, panel, desc.Layout); err != nil {
+			return nil, errs.NewWithCause(path, err)
+		}
+	}
+	return widget, nil
+}
+
+func decodeUILayout(raw json.RawMessage) (*UILayoutType, Layout, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, nil, errs.NewWithCause("invalid layout", err)
+	}
+	layoutType, ok := uiLayoutTypes[header.Type]
+	if !ok {
+		return nil, nil, errs.Newf("unknown layout type '%s'", header.Type)
+	}
+	layout := layoutType.Create()
+	if err := json.Unmarshal(raw, layout); err != nil {
+		return nil, nil, errs.NewWithCausef(err, "invalid %s layout", header.Type)
+	}
+	return &layoutType, layout, nil
+}
+
+func decodeUILayoutData[T any](raw json.RawMessage) (any, error) {
+	var data T
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func finishUIConstraintLayout(layout Layout, target *Panel, raw json.RawMessage) error {
+	var desc struct {
+		Constraints []string `json:"constraints"`
+	}
+	if err := json.Unmarshal(raw, &desc); err != nil {
+		return errs.NewWithCause("invalid constraint layout", err)
+	}
+	if c, ok := layout.(*ConstraintLayout); ok {
+		return c.AddParsed(target, desc.Constraints...)
+	}
+	return nil
+}
+
+func decodeUIBorder(raw json.RawMessage) (Border, error) {
+	var desc struct {
+		Type         string  `json:"type"`
+		Ink          string  `json:"ink"`
+		Insets       Insets  `json:"insets"`
+		CornerRadius float32 `json:"cornerRadius"`
+		NoInset      bool    `json:"noInset"`
+	}
+	if err := json.Unmarshal(raw, &desc); err != nil {
+		return nil, errs.NewWithCause("invalid border", err)
+	}
+	switch desc.Type {
+	case "empty":
+		return NewEmptyBorder(desc.Insets), nil
+	case "line":
+		ink := Ink(ControlEdgeColor)
+		if desc.Ink != "" {
+			c, err := ColorDecode(desc.Ink)
+			if err != nil {
+				return nil, errs.NewWithCause("invalid border ink", err)
+			}
+			ink = c
+		}
+		return NewLineBorder(ink, desc.CornerRadius, desc.Insets, desc.NoInset), nil
+	default:
+		return nil, errs.Newf("unknown border type '%s'", desc.Type)
+	}
+}
+
+// applyUIProperties sets the properties on the widget, in name order. When useSetters is true, a method named "Set"
+// followed by the property name is preferred over a field.
+func applyUIProperties(widget Paneler, props UIProperties, useSetters bool) error {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	target := reflect.ValueOf(widget)
+	for _, key := range keys {
+		raw := props[key]
+		if useSetters {
+			if method := findUISetter(target, key); method.IsValid() {
+				arg := reflect.New(method.Type().In(0)).Elem()
+				if err := decodeUIValue(raw, arg); err != nil {
+					return errs.NewWithCausef(err, "invalid value for property '%s'", key)
+				}
+				method.Call([]reflect.Value{arg})
+				continue
+			}
+		}
+		if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Struct {
+			return errs.Newf("unknown property '%s'", key)
+		}
+		field := target.Elem().FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, key) })
+		if !field.IsValid() || !field.CanSet() {
+			return errs.Newf("unknown property '%s'", key)
+		}
+		if err := decodeUIValue(raw, field); err != nil {
+			return errs.NewWithCausef(err, "invalid value for property '%s'", key)
+		}
+	}
+	return nil
+}
+
+func findUISetter(target reflect.Value, key string) reflect.Value {
+	t := target.Type()
+	for i := range t.NumMethod() {
+		m := t.Method(i)
+		if strings.EqualFold(m.Name, "Set"+key) && m.Type.NumIn() == 2 && m.Type.NumOut() == 0 {
+			return target.Method(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// decodeUIValue decodes the raw JSON into the settable value, handling the types which have no JSON representation of
+// their own.
+func decodeUIValue(raw json.RawMessage, value reflect.Value) error {
+	switch value.Type() {
+	case uiInkType:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		c, err := ColorDecode(s)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(c))
+	case uiFontType:
+		var fd FontDescriptor
+		if err := json.Unmarshal(raw, &fd); err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(fd.Font()))
+	case uiDurationType:
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			value.SetInt(int64(d))
+			return nil
+		}
+		return json.Unmarshal(raw, value.Addr().Interface())
+	default:
+		if value.CanAddr() {
+			return json.Unmarshal(raw, value.Addr().Interface())
+		}
+		ptr := reflect.New(value.Type())
+		if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+			return err
+		}
+		value.Set(ptr.Elem())
+	}
+	return nil
+}
+
+func createUICheckBox(_ *UI, props UIProperties) (Paneler, error) {
+	c := NewCheckBox()
+	var checked bool
+	if _, err := props.Take("checked", &checked); err != nil {
+		return nil, err
+	}
+	c.State = CheckStateFromBool(checked)
+	return c, nil
+}
+
+func createUIRadioButton(ui *UI, props UIProperties) (Paneler, error) {
+	r := NewRadioButton()
+	var group string
+	if _, err := props.Take("group", &group); err != nil {
+		return nil, err
+	}
+	if group != "" {
+		ui.Group(group).Add(r.AsGroupPanel())
+	}
+	return r, nil
+}
+
+func createUIPopupMenu(_ *UI, props UIProperties) (Paneler, error) {
+	p := NewPopupMenu[string]()
+	var items []string
+	if _, err := props.Take("items", &items); err != nil {
+		return nil, err
+	}
+	p.AddItem(items...)
+	var selected string
+	found, err := props.Take("selected", &selected)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		p.Select(selected)
+	}
+	return p, nil
+}
+
+func createUIProgressBar(_ *UI, props UIProperties) (Paneler, error) {
+	var maximum float32
+	if _, err := props.Take("maximum", &maximum); err != nil {
+		return nil, err
+	}
+	return NewProgressBar(maximum), nil
+}
+
+func createUIScrollPanel(_ *UI, props UIProperties) (Paneler, error) {
+	s := NewScrollPanel()
+	if _, err := props.Take("widthBehavior", &s.widthBehavior); err != nil {
+		return nil, err
+	}
+	if _, err := props.Take("heightBehavior", &s.heightBehavior); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func addUIScrollPanelChild(parent, child Paneler) error {
+	s, ok := parent.(*ScrollPanel)
+	if !ok {
+		return errs.New("parent is not a ScrollPanel")
+	}
+	if s.content != nil {
+		return errs.New("a ScrollPanel may only have one child")
+	}
+	s.SetContent(child, s.widthBehavior, s.heightBehavior)
+	if t, ok := child.(*Table[*UITableRow]); ok && t.header != nil {
+		s.SetColumnHeader(t.header)
+	}
+	return nil
+}
+
+func createUITable(_ *UI, props UIProperties) (Paneler, error) {
+	var columns []string
+	if _, err := props.Take("columns", &columns); err != nil {
+		return nil, err
+	}
+	var cells [][]string
+	if _, err := props.Take("rows", &cells); err != nil {
+		return nil, err
+	}
+	t := NewTable[*UITableRow](&SimpleTableModel[*UITableRow]{})
+	t.Columns = make([]ColumnInfo, len(columns))
+	headers := make([]TableColumnHeader[*UITableRow], len(columns))
+	for i, title := range columns {
+		t.Columns[i] = ColumnInfo{ID: i, Minimum: 20, Maximum: 10000}
+		headers[i] = NewTableColumnHeader[*UITableRow](title, "")
+	}
+	if len(headers) != 0 {
+		NewTableHeader(t, headers...)
+	}
+	rows := make([]*UITableRow, len(cells))
+	for i, one := range cells {
+		rows[i] = NewUITableRow(one...)
+	}
+	t.SetRootRows(rows)
+	t.EventuallySizeColumnsToFit(true)
+	return t, nil
+}
@@ -0,0 +1,101 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/align"
+)
+
+type uiTestWidget struct {
+	unison.Panel
+	Label string
+	count int
+}
+
+func (w *uiTestWidget) SetCount(count int) {
+	w.count = count
+}
+
+func TestBuildUI(t *testing.T) {
+	unison.RegisterUIType("TestWidget", unison.UIType{
+		Create: func(_ *unison.UI, _ unison.UIProperties) (unison.Paneler, error) {
+			w := &uiTestWidget{}
+			w.Self = w
+			return w, nil
+		},
+	})
+	ui, err := unison.BuildUIFromJSON([]byte(`{
+		"type": "Panel",
+		"ref": "root",
+		"layout": {"type": "flex", "columns": 2, "hSpacing": 4},
+		"border": {"type": "empty", "insets": {"top": 1, "left": 2, "bottom": 3, "right": 4}},
+		"children": [
+			{"type": "TestWidget", "ref": "widget", "props": {"label": "hello", "count": 3}, "layoutData": {"hAlign": "fill", "hGrab": true}},
+			{"type": "Panel", "ref": "other"}
+		]
+	}`))
+	check.NoError(t, err)
+	check.Equal(t, []string{"other", "root", "widget"}, ui.RefKeys())
+	check.Equal(t, ui.Lookup("root"), ui.Root.AsPanel())
+	check.Equal(t, 2, len(ui.Root.AsPanel().Children()))
+	w, ok := unison.UILookup[*uiTestWidget](ui, "widget")
+	check.True(t, ok)
+	check.Equal(t, "hello", w.Label)
+	check.Equal(t, 3, w.count)
+	data, ok := w.LayoutData().(*unison.FlexLayoutData)
+	check.True(t, ok)
+	check.Equal(t, align.Fill, data.HAlign)
+	check.Equal(t, align.Middle, data.VAlign)
+	check.Equal(t, 1, data.HSpan)
+	check.True(t, data.HGrab)
+	layout, ok := ui.Root.AsPanel().Layout().(*unison.FlexLayout)
+	check.True(t, ok)
+	check.Equal(t, 2, layout.Columns)
+	check.Equal(t, float32(4), layout.HSpacing)
+	_, ok = unison.UILookup[*uiTestWidget](ui, "other")
+	check.False(t, ok)
+
+	ui, err = unison.BuildUIFromJSON([]byte(`{
+		"type": "ScrollPanel",
+		"ref": "scroller",
+		"children": [
+			{"type": "Table", "ref": "table", "props": {"columns": ["Name", "Size"], "rows": [["a", "1"], ["b", "2"]]}}
+		]
+	}`))
+	check.NoError(t, err)
+	table, ok := unison.UILookup[*unison.Table[*unison.UITableRow]](ui, "table")
+	check.True(t, ok)
+	check.Equal(t, 2, len(table.Columns))
+	check.Equal(t, 2, table.LastRowIndex()+1)
+	check.Equal(t, "2", table.RowFromIndex(1).CellDataForSort(1))
+	scroller, ok := unison.UILookup[*unison.ScrollPanel](ui, "scroller")
+	check.True(t, ok)
+	check.NotNil(t, scroller.ColumnHeader())
+
+	_, err = unison.BuildUIFromJSON([]byte(`{"type": "Panel", "layout": {"type": "flow"}, "children": [{"type": "Panel", "layoutData": 1}]}`))
+	check.Error(t, err)
+	_, err = unison.BuildUI(&unison.UIDescription{Type: "Panel", Properties: unison.UIProperties{"bogus": json.RawMessage("1")}})
+	check.Error(t, err)
+	_, err = unison.BuildUI(&unison.UIDescription{Type: "Missing"})
+	check.Error(t, err)
+	_, err = unison.BuildUI(&unison.UIDescription{
+		Type: "Panel",
+		Children: []*unison.UIDescription{
+			{Type: "Panel", RefKey: "dup"},
+			{Type: "Panel", RefKey: "dup"},
+		},
+	})
+	check.Error(t, err)
+}
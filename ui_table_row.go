@@ -0,0 +1,108 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"github.com/google/uuid"
+)
+
+var _ TableRowData[*UITableRow] = &UITableRow{}
+
+// UITableRow is the row type used by the Tables that BuildUI() creates. Each row holds the text of its cells and may
+// have child rows. A "Table" description takes a "columns" property with the column titles and a "rows" property with
+// the text of each row's cells. When a Table is the child of a ScrollPanel, its header is used as the ScrollPanel's
+// column header. Applications that need to present their own data types in a Table should register their own widget
+// type with RegisterUIType().
+type UITableRow struct {
+	// Cells holds the text for each column.
+	Cells    []string
+	parent   *UITableRow
+	children []*UITableRow
+	id       uuid.UUID
+	open     bool
+}
+
+// NewUITableRow creates a new UITableRow with the text for each of its cells.
+func NewUITableRow(cells ...string) *UITableRow {
+	return &UITableRow{
+		Cells: cells,
+		id:    uuid.New(),
+	}
+}
+
+// CloneForTarget implements TableRowData.
+func (r *UITableRow) CloneForTarget(target Paneler, newParent *UITableRow) *UITableRow {
+	clone := NewUITableRow(r.Cells...)
+	clone.parent = newParent
+	clone.open = r.open
+	if len(r.children) != 0 {
+		clone.children = make([]*UITableRow, len(r.children))
+		for i, child := range r.children {
+			clone.children[i] = child.CloneForTarget(target, clone)
+		}
+	}
+	return clone
+}
+
+// UUID implements TableRowData.
+func (r *UITableRow) UUID() uuid.UUID {
+	return r.id
+}
+
+// Parent implements TableRowData.
+func (r *UITableRow) Parent() *UITableRow {
+	return r.parent
+}
+
+// SetParent implements TableRowData.
+func (r *UITableRow) SetParent(parent *UITableRow) {
+	r.parent = parent
+}
+
+// CanHaveChildren implements TableRowData.
+func (r *UITableRow) CanHaveChildren() bool {
+	return len(r.children) != 0
+}
+
+// Children implements TableRowData.
+func (r *UITableRow) Children() []*UITableRow {
+	return r.children
+}
+
+// SetChildren implements TableRowData.
+func (r *UITableRow) SetChildren(children []*UITableRow) {
+	r.children = children
+}
+
+// CellDataForSort implements TableRowData.
+func (r *UITableRow) CellDataForSort(col int) string {
+	if col < 0 || col >= len(r.Cells) {
+		return ""
+	}
+	return r.Cells[col]
+}
+
+// ColumnCell implements TableRowData.
+func (r *UITableRow) ColumnCell(_, col int, foreground, _ Ink, _, _, _ bool) Paneler {
+	label := NewLabel()
+	label.Text = r.CellDataForSort(col)
+	label.OnBackgroundInk = foreground
+	return label
+}
+
+// IsOpen implements TableRowData.
+func (r *UITableRow) IsOpen() bool {
+	return r.open
+}
+
+// SetOpen implements TableRowData.
+func (r *UITableRow) SetOpen(open bool) {
+	r.open = open
+}
@@ -0,0 +1,206 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package uitest provides helpers for testing user interfaces built with unison.
+package uitest
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/enums/thememode"
+)
+
+// UpdateGoldensEnvVar is the name of the environment variable that, when set to a non-empty value, has the same effect
+// as passing the -update-goldens flag to the test binary.
+const UpdateGoldensEnvVar = "UNISON_UPDATE_GOLDENS"
+
+var updateGoldens = flag.Bool("update-goldens", false, "rewrite golden images rather than comparing against them")
+
+// GoldenOptions holds the options used when rendering and comparing a panel against a golden image.
+type GoldenOptions struct {
+	// Dir is the directory that golden images are stored in. Defaults to "testdata/golden".
+	Dir string
+	// Size is the logical size to render the panel at. If empty, the panel's preferred size is used.
+	Size unison.Size
+	// Scale is the backing scale to render with. Defaults to 1.
+	Scale float32
+	// ThemeMode is the theme mode to render with. The previous mode is restored afterward. Defaults to
+	// thememode.Light, since thememode.Auto follows the host OS and would make the rendering machine-dependent.
+	ThemeMode thememode.Enum
+	// Tolerance is the maximum difference permitted in any single color channel before a pixel is considered to be
+	// different.
+	Tolerance uint8
+	// MaxDifferentPixels is the number of pixels that may differ before the comparison fails.
+	MaxDifferentPixels int
+}
+
+// UpdatingGoldens returns true if golden images should be rewritten rather than compared against.
+func UpdatingGoldens() bool {
+	return *updateGoldens || os.Getenv(UpdateGoldensEnvVar) != ""
+}
+
+// Render draws the panel into an offscreen window with the given logical size, backing scale and theme mode, then
+// returns the resulting image. If size is empty, the panel's preferred size is used. The panel is removed from the
+// offscreen window before returning.
+func Render(panel unison.Paneler, size unison.Size, scale float32, mode thememode.Enum) (*unison.Image, error) {
+	if scale <= 0 {
+		scale = 1
+	}
+	if size.Width < 1 || size.Height < 1 {
+		_, size, _ = panel.AsPanel().Sizes(unison.Size{})
+		size.GrowToInteger()
+		size.Width = max(size.Width, 1)
+		size.Height = max(size.Height, 1)
+	}
+	previousMode := unison.CurrentColorMode()
+	setThemeMode(mode)
+	defer setThemeMode(previousMode)
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(size, scale))
+	if err != nil {
+		return nil, err
+	}
+	defer wnd.Dispose()
+	wnd.SetContent(panel)
+	return wnd.Snapshot()
+}
+
+func setThemeMode(mode thememode.Enum) {
+	if unison.CurrentColorMode() != mode {
+		unison.SetThemeMode(mode)
+		unison.RunPendingTasks()
+	}
+}
+
+// CheckGolden renders the panel and compares it against the golden image with the given name, which should not include
+// a file extension. If golden images are being updated, the golden image is rewritten instead. On a mismatch, the
+// rendered image is written next to the golden image with an "_actual.png" suffix, along with a diff image with a
+// "_diff.png" suffix when the dimensions match, and the test is marked as failed.
+func CheckGolden(t testing.TB, name string, panel unison.Paneler, options *GoldenOptions) {
+	t.Helper()
+	var opts GoldenOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Dir == "" {
+		opts.Dir = filepath.Join("testdata", "golden")
+	}
+	if opts.ThemeMode == thememode.Auto {
+		opts.ThemeMode = thememode.Light
+	}
+	img, err := Render(panel, opts.Size, opts.Scale, opts.ThemeMode)
+	if err != nil {
+		t.Fatalf("unable to render %s: %v", name, err)
+	}
+	var actual []byte
+	if actual, err = img.ToPNG(); err != nil {
+		t.Fatalf("unable to encode %s: %v", name, err)
+	}
+	goldenPath := filepath.Join(opts.Dir, name+".png")
+	actualPath := filepath.Join(opts.Dir, name+"_actual.png")
+	diffPath := filepath.Join(opts.Dir, name+"_diff.png")
+	if UpdatingGoldens() {
+		if err = os.MkdirAll(opts.Dir, 0o750); err != nil {
+			t.Fatalf("unable to create %s: %v", opts.Dir, err)
+		}
+		if err = os.WriteFile(goldenPath, actual, 0o640); err != nil {
+			t.Fatalf("unable to write %s: %v", goldenPath, err)
+		}
+		_ = os.Remove(actualPath) //nolint:errcheck // Don't care if this fails
+		_ = os.Remove(diffPath)   //nolint:errcheck // Don't care if this fails
+		return
+	}
+	var expected []byte
+	if expected, err = os.ReadFile(goldenPath); err != nil {
+		t.Fatalf("unable to read golden image %s (use -update-goldens to create it): %v", goldenPath, err)
+	}
+	var count int
+	var diff image.Image
+	if count, diff, err = Compare(expected, actual, opts.Tolerance); err != nil {
+		t.Fatalf("unable to compare %s: %v", name, err)
+	}
+	if count <= opts.MaxDifferentPixels {
+		_ = os.Remove(actualPath) //nolint:errcheck // Don't care if this fails
+		_ = os.Remove(diffPath)   //nolint:errcheck // Don't care if this fails
+		return
+	}
+	if err = os.WriteFile(actualPath, actual, 0o640); err != nil {
+		t.Errorf("unable to write %s: %v", actualPath, err)
+	}
+	// No diff image is produced when the dimensions differ, so point at the rendered image instead
+	see := actualPath
+	if diff == nil {
+		_ = os.Remove(diffPath) //nolint:errcheck // Don't care if this fails
+	} else {
+		var buffer bytes.Buffer
+		if err = png.Encode(&buffer, diff); err == nil {
+			err = os.WriteFile(diffPath, buffer.Bytes(), 0o640)
+		}
+		if err != nil {
+			t.Errorf("unable to write %s: %v", diffPath, err)
+		} else {
+			see = diffPath
+		}
+	}
+	t.Errorf("%s differs from its golden image in %d pixels (%d permitted); see %s", name, count, opts.MaxDifferentPixels,
+		see)
+}
+
+// Compare decodes two PNG images and compares them pixel by pixel. A pixel is considered different if any of its
+// color channels differs by more than tolerance. Returns the number of differing pixels and, if there are any, an image
+// highlighting them in red over a faded copy of the expected image. Images with different dimensions are considered to
+// differ in every pixel.
+func Compare(expectedPNG, actualPNG []byte, tolerance uint8) (count int, diff image.Image, err error) {
+	var expected, actual image.Image
+	if expected, err = png.Decode(bytes.NewReader(expectedPNG)); err != nil {
+		return 0, nil, errs.NewWithCause("unable to decode expected image", err)
+	}
+	if actual, err = png.Decode(bytes.NewReader(actualPNG)); err != nil {
+		return 0, nil, errs.NewWithCause("unable to decode actual image", err)
+	}
+	eb := expected.Bounds()
+	ab := actual.Bounds()
+	if eb.Dx() != ab.Dx() || eb.Dy() != ab.Dy() {
+		return max(eb.Dx()*eb.Dy(), ab.Dx()*ab.Dy()), nil, nil
+	}
+	d := image.NewNRGBA(image.Rect(0, 0, eb.Dx(), eb.Dy()))
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			ec := color.NRGBAModel.Convert(expected.At(eb.Min.X+x, eb.Min.Y+y)).(color.NRGBA) //nolint:errcheck // Always succeeds
+			ac := color.NRGBAModel.Convert(actual.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)   //nolint:errcheck // Always succeeds
+			if channelDiff(ec.R, ac.R) > tolerance || channelDiff(ec.G, ac.G) > tolerance ||
+				channelDiff(ec.B, ac.B) > tolerance || channelDiff(ec.A, ac.A) > tolerance {
+				count++
+				d.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				gray := uint8((uint32(ec.R) + uint32(ec.G) + uint32(ec.B)) / 3)
+				d.SetNRGBA(x, y, color.NRGBA{R: gray, G: gray, B: gray, A: 64})
+			}
+		}
+	}
+	if count == 0 {
+		return 0, nil, nil
+	}
+	return count, d, nil
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,108 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package uitest_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+	"github.com/ddkwork/unison/uitest"
+)
+
+func TestCompare(t *testing.T) {
+	a := encode(t, 4, 4, func(x, y int) color.NRGBA { return color.NRGBA{R: 100, G: 100, B: 100, A: 255} })
+	b := encode(t, 4, 4, func(x, y int) color.NRGBA {
+		if x == 1 && y == 2 {
+			return color.NRGBA{R: 110, G: 100, B: 100, A: 255}
+		}
+		return color.NRGBA{R: 102, G: 100, B: 100, A: 255}
+	})
+	count, diff, err := uitest.Compare(a, a, 0)
+	check.NoError(t, err)
+	check.Equal(t, 0, count)
+	check.Nil(t, diff)
+
+	count, diff, err = uitest.Compare(a, b, 0)
+	check.NoError(t, err)
+	check.Equal(t, 16, count)
+	check.NotNil(t, diff)
+
+	count, diff, err = uitest.Compare(a, b, 2)
+	check.NoError(t, err)
+	check.Equal(t, 1, count)
+	check.Equal(t, color.NRGBA{R: 255, A: 255}, diff.At(1, 2))
+
+	count, _, err = uitest.Compare(a, b, 10)
+	check.NoError(t, err)
+	check.Equal(t, 0, count)
+
+	c := encode(t, 2, 3, func(x, y int) color.NRGBA { return color.NRGBA{A: 255} })
+	count, _, err = uitest.Compare(a, c, 255)
+	check.NoError(t, err)
+	check.Equal(t, 16, count)
+}
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckGoldenSizeMismatch(t *testing.T) {
+	if uitest.UpdatingGoldens() {
+		t.Skip("golden images are being updated")
+	}
+	dir := t.TempDir()
+	golden := encode(t, 2, 2, func(x, y int) color.NRGBA { return color.NRGBA{A: 255} })
+	check.NoError(t, os.WriteFile(filepath.Join(dir, "panel.png"), golden, 0o640))
+	diffPath := filepath.Join(dir, "panel_diff.png")
+	check.NoError(t, os.WriteFile(diffPath, golden, 0o640))
+
+	tb := &recordingTB{TB: t}
+	uitest.CheckGolden(tb, "panel", unison.NewPanel(), &uitest.GoldenOptions{
+		Dir:  dir,
+		Size: unison.Size{Width: 4, Height: 3},
+	})
+	check.Equal(t, 1, len(tb.errors))
+	actualPath := filepath.Join(dir, "panel_actual.png")
+	check.True(t, strings.HasSuffix(tb.errors[0], "see "+actualPath), "no diff image exists to point at: %s",
+		tb.errors[0])
+	_, err := os.Stat(actualPath)
+	check.NoError(t, err)
+	_, err = os.Stat(diffPath)
+	check.True(t, os.IsNotExist(err), "a stale diff image must not be left behind")
+}
+
+func encode(t *testing.T, width, height int, pixel func(x, y int) color.NRGBA) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, pixel(x, y))
+		}
+	}
+	var buffer bytes.Buffer
+	check.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
@@ -65,41 +65,49 @@ type Window struct {
 	// DragIntoWindowWillStart is called just prior to a drag into the window starting.
 	DragIntoWindowWillStart func()
 	// DragIntoWindowFinished is called just after a drag into the window completes, whether a drop occurs or not.
-	DragIntoWindowFinished func()
-	title                  string
-	titleIcons             []*Image
-	wnd                    *glfw.Window
-	surface                *surface
-	data                   map[string]any
-	root                   *rootPanel
-	focus                  *Panel
-	cursor                 *Cursor
-	lastMouseDownPanel     *Panel
-	lastMouseOverPanel     *Panel
-	lastKeyDownPanel       *Panel
-	lastTooltip            *Panel
-	lastTooltipShownAt     time.Time
-	lastDrawDuration       time.Duration
-	tooltipSequence        int
-	modalResultCode        int
-	lastButton             int
-	lastButtonCount        int
-	lastButtonTime         time.Time
-	lastContentRect        Rect
-	firstButtonLocation    Point
-	dragDataLocation       Point
-	dragDataPanel          *Panel
-	dragData               *DragData
-	lastKeyModifiers       Modifiers
-	valid                  bool
-	focused                bool
-	transient              bool
-	notResizable           bool
-	undecorated            bool
-	floating               bool
-	inModal                bool
-	inMouseDown            bool
-	cursorHidden           bool
+	DragIntoWindowFinished     func()
+	title                      string
+	titleIcons                 []*Image
+	wnd                        *glfw.Window
+	offscreen                  *offscreenWindow
+	recorder                   *InputRecorder
+	profiler                   *Profiler
+	surface                    *surface
+	data                       map[string]any
+	root                       *rootPanel
+	focus                      *Panel
+	cursor                     *Cursor
+	lastMouseDownPanel         *Panel
+	lastMouseOverPanel         *Panel
+	lastKeyDownPanel           *Panel
+	lastTooltip                *Panel
+	lastTooltipShownAt         time.Time
+	lastDrawDuration           time.Duration
+	lastFrameTime              time.Time
+	lastFrameCallbacksDuration time.Duration
+	frameCallbacks             []*frameCallback
+	lastFrameCallbackID        FrameCallbackID
+	tooltipSequence            int
+	modalResultCode            int
+	lastButton                 int
+	lastButtonCount            int
+	lastButtonTime             time.Time
+	lastContentRect            Rect
+	firstButtonLocation        Point
+	dragDataLocation           Point
+	dragDataPanel              *Panel
+	dragData                   *DragData
+	lastKeyModifiers           Modifiers
+	valid                      bool
+	focused                    bool
+	transient                  bool
+	notResizable               bool
+	undecorated                bool
+	floating                   bool
+	inModal                    bool
+	inMouseDown                bool
+	cursorHidden               bool
+	frameScheduled             bool
 }
 
 // WindowOption holds an option for window creation.
@@ -155,7 +163,7 @@ func AllWindowsToFront() {
 		copy(list, windowList)
 		for i := len(list) - 1; i >= 0; i-- {
 			list[i].Show()
-			if i == 0 {
+			if i == 0 && list[i].hasNativeWindow() {
 				list[i].wnd.Focus()
 			}
 		}
@@ -203,6 +211,21 @@ func NewWindow(title string, options ...WindowOption) (*Window, error) {
 	for _, option := range options {
 		mylog.Check(option(w))
 	}
+	if w.offscreen != nil {
+		w.offscreen.init()
+	} else {
+		w.createNativeWindow()
+		windowList = append(windowList, w)
+		windowMap[w.wnd] = w
+	}
+	w.valid = true
+	w.root = newRootPanel(w)
+	w.ValidateLayout()
+	w.SetTitleIcons(w.titleIcons)
+	return w, nil
+}
+
+func (w *Window) createNativeWindow() {
 	glfw.WindowHint(glfw.Visible, glfw.False)
 	glfw.WindowHint(glfw.Resizable, glfwEnabled(!w.notResizable))
 	glfw.WindowHint(glfw.Decorated, glfwEnabled(!w.undecorated))
@@ -213,7 +236,7 @@ func NewWindow(title string, options ...WindowOption) (*Window, error) {
 	glfw.WindowHint(glfw.ScaleToMonitor, glfw.False)
 
 	toolbox.CallWithHandler(func() {
-		w.wnd = mylog.Check2(glfw.CreateWindow(1, 1, title, nil, nil))
+		w.wnd = mylog.Check2(glfw.CreateWindow(1, 1, w.title, nil, nil))
 	}, func(panicErr error) {
 		panic(panicErr)
 	})
@@ -248,19 +271,10 @@ func NewWindow(title string, options ...WindowOption) (*Window, error) {
 	})
 	w.wnd.SetMouseButtonCallback(w.mouseButtonCallback)
 	w.wnd.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
-		where := w.convertMouseLocation(x, y)
-		if w.inMouseDown {
-			w.mouseDrag(where, w.lastButton, w.lastKeyModifiers)
-		} else {
-			w.mouseMove(where, w.lastKeyModifiers)
-		}
+		w.mouseMoved(w.convertMouseLocation(x, y), w.lastKeyModifiers)
 	})
 	w.wnd.SetCursorEnterCallback(func(_ *glfw.Window, entered bool) {
-		if entered {
-			w.mouseEnter(w.MouseLocation(), w.lastKeyModifiers)
-		} else {
-			w.mouseExit()
-		}
+		w.mouseEnterOrExit(entered, w.MouseLocation(), w.lastKeyModifiers)
 	})
 	w.wnd.SetScrollCallback(func(_ *glfw.Window, xoff, yoff float64) {
 		w.mouseWheel(w.MouseLocation(), Point{X: float32(xoff), Y: float32(yoff)}, w.lastKeyModifiers)
@@ -275,16 +289,9 @@ func NewWindow(title string, options ...WindowOption) (*Window, error) {
 	// file drop capability... so we'll just live with that for now.
 	w.wnd.SetDropCallback(func(_ *glfw.Window, files []string) {
 		if w.okToProcess() {
-			w.fileDrop(files)
+			w.fileDrop(w.MouseLocation(), files)
 		}
 	})
-	w.valid = true
-	windowList = append(windowList, w)
-	windowMap[w.wnd] = w
-	w.root = newRootPanel(w)
-	w.ValidateLayout()
-	w.SetTitleIcons(w.titleIcons)
-	return w, nil
 }
 
 func (w *Window) commonKeyCallbackForGLFW(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {
@@ -312,16 +319,20 @@ func glfwEnabled(enabled bool) int {
 }
 
 func (w *Window) mouseButtonCallback(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	w.mouseButton(w.MouseLocation(), int(button), action == glfw.Press, Modifiers(mods))
+}
+
+func (w *Window) mouseButton(where Point, button int, pressed bool, mod Modifiers) {
 	if !w.okToProcess() {
-		modalStack[len(modalStack)-1].mouseButtonCallback(nil, button, action, mods)
+		modal := modalStack[len(modalStack)-1]
+		modal.mouseButton(modal.MouseLocation(), button, pressed, mod)
 		return
 	}
-	w.lastKeyModifiers = Modifiers(mods)
-	where := w.MouseLocation()
-	if action == glfw.Press {
+	w.lastKeyModifiers = mod
+	if pressed {
 		maxDelay, maxMouseDrift := DoubleClickParameters()
 		now := time.Now()
-		if int(button) == w.lastButton && time.Since(w.lastButtonTime) <= maxDelay &&
+		if button == w.lastButton && time.Since(w.lastButtonTime) <= maxDelay &&
 			xmath.Abs(where.X-w.firstButtonLocation.X) <= maxMouseDrift &&
 			xmath.Abs(where.Y-w.firstButtonLocation.Y) <= maxMouseDrift {
 			w.lastButtonCount++
@@ -330,12 +341,18 @@ func (w *Window) mouseButtonCallback(_ *glfw.Window, button glfw.MouseButton, ac
 			w.lastButtonCount = 1
 			w.firstButtonLocation = where
 		}
-		w.lastButton = int(button)
+		w.lastButton = button
 		w.lastButtonTime = now
 		w.inMouseDown = true
+		if w.recorder != nil {
+			w.recorder.mouseButton(where, button, w.lastButtonCount, true, mod)
+		}
 		w.mouseDown(where, w.lastButton, w.lastButtonCount, w.lastKeyModifiers)
 	} else if w.inMouseDown {
-		w.lastButton = int(button)
+		if w.recorder != nil {
+			w.recorder.mouseButton(where, button, 0, false, mod)
+		}
+		w.lastButton = button
 		w.inMouseDown = false
 		w.mouseUp(where, w.lastButton, w.lastKeyModifiers)
 	}
@@ -374,8 +391,11 @@ func (w *Window) resized() {
 }
 
 func (w *Window) gainedFocus() {
+	if w.recorder != nil {
+		w.recorder.focusChange(true)
+	}
 	w.focused = true
-	if len(windowList) != 0 && windowList[0] != w {
+	if w.offscreen == nil && len(windowList) != 0 && windowList[0] != w {
 		w.removeFromWindowList()
 		windowList = append(windowList, nil)
 		copy(windowList[1:], windowList)
@@ -395,6 +415,9 @@ func (w *Window) gainedFocus() {
 }
 
 func (w *Window) lostFocus() {
+	if w.recorder != nil {
+		w.recorder.focusChange(false)
+	}
 	w.restoreHiddenCursor()
 	w.focused = false
 	w.ClearTooltip()
@@ -455,6 +478,10 @@ func (w *Window) IsValid() bool {
 	return w != nil && w.valid
 }
 
+func (w *Window) hasNativeWindow() bool {
+	return w.IsValid() && w.wnd != nil
+}
+
 func (w *Window) String() string {
 	return fmt.Sprintf("Window[%s]", w.title)
 }
@@ -492,18 +519,22 @@ func (w *Window) Dispose() {
 	if w.inModal {
 		w.StopModal(ModalResponseDiscard)
 	}
+	w.frameCallbacks = nil
 	if w.root.contentPanel != nil {
 		w.root.contentPanel.RemoveFromParent()
 	}
 	w.removeFromWindowList()
 	delete(windowMap, w.wnd)
+	native := w.wnd != nil
 	if w.IsValid() {
 		w.valid = false
 		w.surface.dispose()
-		w.wnd.Destroy()
-		w.wnd = nil
+		if native {
+			w.wnd.Destroy()
+			w.wnd = nil
+		}
 	}
-	if len(windowMap) == 0 && quitAfterLastWindowClosed() {
+	if native && len(windowMap) == 0 && quitAfterLastWindowClosed() {
 		quitting()
 	}
 	if active != nil && active == w && len(windowList) != 0 {
@@ -520,7 +551,7 @@ func (w *Window) Title() string {
 func (w *Window) SetTitle(title string) {
 	if w.title != title {
 		w.title = title
-		if w.IsValid() {
+		if w.hasNativeWindow() {
 			w.wnd.SetTitle(title)
 		}
 	}
@@ -541,7 +572,7 @@ func (w *Window) SetTitleIcons(images []*Image) {
 		w.titleIcons = append(w.titleIcons, img)
 		imgs = append(imgs, nrgba)
 	}
-	if w.IsValid() {
+	if w.hasNativeWindow() {
 		w.wnd.SetIcon(imgs)
 	}
 }
@@ -641,6 +672,10 @@ func (w *Window) Pack() {
 	_, pref, _ := w.root.Sizes(Size{})
 	rect := w.ContentRect()
 	rect.Size = pref
+	if w.offscreen != nil {
+		w.SetContentRect(rect)
+		return
+	}
 	w.SetContentRect(BestDisplayForRect(rect).FitRectOnto(rect))
 }
 
@@ -778,6 +813,9 @@ func collectFocusables(current, target *Panel, focusables []*Panel) (match int,
 
 // IsVisible returns true if the window is currently being shown.
 func (w *Window) IsVisible() bool {
+	if w.offscreen != nil {
+		return w.IsValid() && w.offscreen.visible
+	}
 	if w.IsValid() {
 		return w.wnd.GetAttrib(glfw.Visible) == glfw.True
 	}
@@ -787,6 +825,10 @@ func (w *Window) IsVisible() bool {
 // Show makes the window visible, if it was previously hidden. If the window is already visible or is in full screen
 // mode, this function does nothing.
 func (w *Window) Show() {
+	if w.offscreen != nil {
+		w.offscreen.visible = w.IsValid()
+		return
+	}
 	if w.IsValid() {
 		w.wnd.Show()
 		// For some reason, Linux is ignoring some window positioning calls prior to showing, so immediately reissue the
@@ -798,6 +840,10 @@ func (w *Window) Show() {
 // Hide hides the window, if it was previously visible. If the window is already hidden or is in full screen mode, this
 // function does nothing.
 func (w *Window) Hide() {
+	if w.offscreen != nil {
+		w.offscreen.visible = false
+		return
+	}
 	if w.IsValid() {
 		w.wnd.Hide()
 	}
@@ -806,6 +852,15 @@ func (w *Window) Hide() {
 // ToFront attempts to bring the window to the foreground and give it the keyboard focus. If it is hidden, it will be
 // made visible first.
 func (w *Window) ToFront() {
+	if w.offscreen != nil {
+		if w.IsValid() {
+			w.Show()
+			if !w.focused {
+				w.gainedFocus()
+			}
+		}
+		return
+	}
 	if w.IsValid() {
 		w.Show()
 		w.focused = true // Don't wait for the focus event to set this, as Linux delays the notification too much
@@ -815,14 +870,14 @@ func (w *Window) ToFront() {
 
 // Minimize performs the minimize function on the window.
 func (w *Window) Minimize() {
-	if w.IsValid() {
+	if w.hasNativeWindow() {
 		w.wnd.Iconify()
 	}
 }
 
 // Zoom performs the zoom function on the window.
 func (w *Window) Zoom() {
-	if w.IsValid() {
+	if w.hasNativeWindow() {
 		w.wnd.Maximize()
 	}
 }
@@ -834,6 +889,9 @@ func (w *Window) Resizable() bool {
 
 // MouseLocation returns the current mouse location relative to this window.
 func (w *Window) MouseLocation() Point {
+	if w.offscreen != nil {
+		return w.offscreen.mouseLocation
+	}
 	if w.IsValid() {
 		return w.convertMouseLocation(w.wnd.GetCursorPos())
 	}
@@ -842,6 +900,9 @@ func (w *Window) MouseLocation() Point {
 
 // BackingScale returns the scale of the backing store for this window.
 func (w *Window) BackingScale() (x, y float32) {
+	if w.offscreen != nil {
+		return w.offscreen.scale, w.offscreen.scale
+	}
 	if w.IsValid() {
 		return w.wnd.GetContentScale()
 	}
@@ -852,9 +913,23 @@ func (w *Window) BackingScale() (x, y float32) {
 func (w *Window) Draw(c *Canvas) {
 	if w.root != nil {
 		mylog.Call(func() {
+			if w.profiler != nil {
+				w.profiler.beginFrame()
+				drawProfiler = w.profiler
+				defer func() { drawProfiler = nil }()
+			}
 			w.root.ValidateLayout()
 			c.DrawPaint(BackgroundColor.Paint(c, w.LocalContentRect(), paintstyle.Fill))
 			w.root.Draw(c, w.LocalContentRect())
+			if w.profiler != nil {
+				drawProfiler = nil
+				w.profiler.endFrame()
+				if w.profiler.ShowOverlay {
+					c.Save()
+					w.profiler.drawOverlay(c, w.LocalContentRect())
+					c.Restore()
+				}
+			}
 			if w.InDrag() {
 				c.Save()
 				c.Translate(w.dragDataLocation.X+w.dragData.Offset.X, w.dragDataLocation.Y+w.dragData.Offset.Y)
@@ -869,6 +944,13 @@ func (w *Window) Draw(c *Canvas) {
 
 func (w *Window) draw() {
 	RebuildDynamicColors()
+	w.runFrameCallbacks()
+	if w.offscreen != nil {
+		if w.IsValid() {
+			mylog.Check(w.drawOffscreen())
+		}
+		return
+	}
 	if w.IsValid() {
 		sx, sy := w.BackingScale()
 		w.wnd.MakeContextCurrent()
@@ -888,7 +970,7 @@ func (w *Window) draw() {
 	}
 }
 
-// LastDrawDuration returns the duration of the window's most recent draw.
+// LastDrawDuration returns the duration of the window's most recent draw, excluding any time spent in frame callbacks.
 func (w *Window) LastDrawDuration() time.Duration {
 	return w.lastDrawDuration
 }
@@ -912,14 +994,14 @@ func (w *Window) FlushDrawing() {
 
 // HideCursor hides the cursor.
 func (w *Window) HideCursor() {
-	if w.IsValid() {
+	if w.hasNativeWindow() {
 		w.wnd.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
 	}
 }
 
 // ShowCursor shows the cursor.
 func (w *Window) ShowCursor() {
-	if w.IsValid() {
+	if w.hasNativeWindow() {
 		w.wnd.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
 	}
 }
@@ -1005,7 +1087,7 @@ func (w *Window) updateCursor(target *Panel, where Point) {
 	if w.cursor != cursor {
 		w.cursor = cursor
 		w.restoreHiddenCursor()
-		if w.IsValid() {
+		if w.hasNativeWindow() {
 			w.wnd.SetCursor(w.cursor)
 		}
 	}
@@ -1090,6 +1172,28 @@ func (w *Window) mouseUp(where Point, button int, mod Modifiers) {
 	w.lastMouseDownPanel = nil
 }
 
+func (w *Window) mouseMoved(where Point, mod Modifiers) {
+	if w.recorder != nil {
+		w.recorder.mouseMove(where, mod, w.inMouseDown)
+	}
+	if w.inMouseDown {
+		w.mouseDrag(where, w.lastButton, mod)
+	} else {
+		w.mouseMove(where, mod)
+	}
+}
+
+func (w *Window) mouseEnterOrExit(entered bool, where Point, mod Modifiers) {
+	if w.recorder != nil {
+		w.recorder.mouseEnterOrExit(entered, where, mod)
+	}
+	if entered {
+		w.mouseEnter(where, mod)
+	} else {
+		w.mouseExit()
+	}
+}
+
 func (w *Window) mouseEnter(where Point, mod Modifiers) {
 	w.restoreHiddenCursor()
 	w.mouseExit()
@@ -1146,6 +1250,9 @@ func (w *Window) mouseExit() {
 }
 
 func (w *Window) mouseWheel(where, delta Point, mod Modifiers) {
+	if w.recorder != nil {
+		w.recorder.mouseWheel(where, delta, mod)
+	}
 	if w.MouseWheelCallback != nil {
 		stop := false
 		mylog.Call(func() { stop = w.MouseWheelCallback(where, delta, mod) })
@@ -1172,6 +1279,9 @@ func (w *Window) mouseWheel(where, delta Point, mod Modifiers) {
 }
 
 func (w *Window) keyDown(keyCode KeyCode, mod Modifiers, repeat bool) {
+	if w.recorder != nil {
+		w.recorder.key(keyCode, mod, true, repeat)
+	}
 	if w.root.preKeyDown(w, keyCode, mod, repeat) {
 		return
 	}
@@ -1209,6 +1319,9 @@ func (w *Window) keyDown(keyCode KeyCode, mod Modifiers, repeat bool) {
 }
 
 func (w *Window) keyUp(keyCode KeyCode, mod Modifiers) {
+	if w.recorder != nil {
+		w.recorder.key(keyCode, mod, false, false)
+	}
 	if w.root.preKeyUp(w, keyCode, mod) {
 		return
 	}
@@ -1225,6 +1338,9 @@ func (w *Window) keyUp(keyCode KeyCode, mod Modifiers) {
 }
 
 func (w *Window) runeTyped(ch rune) {
+	if w.recorder != nil {
+		w.recorder.runeTyped(ch)
+	}
 	if w.root.preRuneTyped(w, ch) {
 		return
 	}
@@ -1254,12 +1370,15 @@ func (w *Window) runeTyped(ch rune) {
 	}
 }
 
-func (w *Window) fileDrop(files []string) {
+func (w *Window) fileDrop(where Point, files []string) {
+	if w.recorder != nil {
+		w.recorder.fileDrop(where, files)
+	}
 	if w.FileDropCallback != nil {
 		mylog.Call(func() { w.FileDropCallback(files) })
 		return
 	}
-	panel := w.root.PanelAt(w.MouseLocation())
+	panel := w.root.PanelAt(where)
 	for panel != nil {
 		if panel.FileDropCallback != nil && panel.Enabled() {
 			mylog.Call(func() { panel.FileDropCallback(files) })
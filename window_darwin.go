@@ -15,6 +15,9 @@ import (
 )
 
 func (w *Window) frameRect() Rect {
+	if w.offscreen != nil {
+		return Rect{}
+	}
 	if w.IsValid() {
 		left, top, right, bottom := w.wnd.GetFrameSize()
 		return NewRect(float32(left), float32(top), float32(right-left), float32(bottom-top))
@@ -24,6 +27,9 @@ func (w *Window) frameRect() Rect {
 
 // ContentRect returns the boundaries in display coordinates of the window's content area.
 func (w *Window) ContentRect() Rect {
+	if w.offscreen != nil {
+		return w.offscreen.rect
+	}
 	if w.IsValid() {
 		x, y := w.wnd.GetPos()
 		width, height := w.wnd.GetSize()
@@ -35,6 +41,12 @@ func (w *Window) ContentRect() Rect {
 // SetContentRect sets the boundaries of the frame of this window by converting the content rect into a suitable frame
 // rect and then applying it to the window.
 func (w *Window) SetContentRect(rect Rect) {
+	if w.offscreen != nil {
+		if w.IsValid() {
+			w.offscreen.setContentRect(rect)
+		}
+		return
+	}
 	if w.IsValid() {
 		rect = w.adjustContentRectForMinMax(rect)
 		w.wnd.SetPos(int(rect.X), int(rect.Y))
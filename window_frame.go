@@ -0,0 +1,150 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/golibrary/mylog"
+	"github.com/ddkwork/unison/internal/glfw"
+)
+
+// FrameCallbackID identifies a callback registered via RequestAnimationFrame(). Zero is never a valid ID.
+type FrameCallbackID uint64
+
+type frameCallback struct {
+	panel    *Panel
+	callback func(frameTime time.Time)
+	id       FrameCallbackID
+}
+
+// RequestAnimationFrame arranges for the callback to be called on the UI thread just before the window draws its next
+// frame. The callback is passed the time the frame started, which is the same for every callback in that frame.
+// Callbacks are only called once; request another frame from within the callback to keep animating. Any number of
+// requests made before the next frame result in a single redraw, and frames are paced to the refresh rate of the
+// display the window is on. While the window is hidden or minimized, pending callbacks are held until it is shown
+// again.
+func (w *Window) RequestAnimationFrame(callback func(frameTime time.Time)) FrameCallbackID {
+	return w.requestAnimationFrame(nil, callback, true)
+}
+
+// RequestAnimationFrame arranges for the callback to be called just before the next frame of the window this panel
+// is in. See Window.RequestAnimationFrame() for details. If the panel has been removed from its window by the time the
+// frame is drawn, the callback is dropped. Returns 0 if the panel is not currently in a window.
+func (p *Panel) RequestAnimationFrame(callback func(frameTime time.Time)) FrameCallbackID {
+	if w := p.Window(); w != nil {
+		return w.requestAnimationFrame(p, callback, true)
+	}
+	return 0
+}
+
+// requestAnimationFrame registers the callback to be called just before the next frame. If redraw is false, the next
+// frame is not scheduled, so the callback waits for something else to cause the window to be redrawn.
+func (w *Window) requestAnimationFrame(panel *Panel, callback func(frameTime time.Time), redraw bool) FrameCallbackID {
+	if !w.IsValid() || callback == nil {
+		return 0
+	}
+	w.lastFrameCallbackID++
+	w.frameCallbacks = append(w.frameCallbacks, &frameCallback{
+		panel:    panel,
+		callback: callback,
+		id:       w.lastFrameCallbackID,
+	})
+	if redraw {
+		w.scheduleFrame()
+	}
+	return w.lastFrameCallbackID
+}
+
+// CancelAnimationFrame removes a pending callback previously registered via RequestAnimationFrame().
+func (w *Window) CancelAnimationFrame(id FrameCallbackID) {
+	for i, one := range w.frameCallbacks {
+		if one.id == id {
+			copy(w.frameCallbacks[i:], w.frameCallbacks[i+1:])
+			w.frameCallbacks[len(w.frameCallbacks)-1] = nil
+			w.frameCallbacks = w.frameCallbacks[:len(w.frameCallbacks)-1]
+			return
+		}
+	}
+}
+
+// IsMinimized returns true if the window is currently minimized.
+func (w *Window) IsMinimized() bool {
+	if w.hasNativeWindow() {
+		return w.wnd.GetAttrib(glfw.Iconified) == glfw.True
+	}
+	return false
+}
+
+// LastFrameTime returns the time at which the window's most recent frame started.
+func (w *Window) LastFrameTime() time.Time {
+	return w.lastFrameTime
+}
+
+// FrameInterval returns the amount of time between frames when animating, based on the refresh rate of the display the
+// window is on.
+func (w *Window) FrameInterval() time.Duration {
+	rate := DefaultAnimationFrameRate
+	if w.hasNativeWindow() {
+		if d := BestDisplayForRect(w.FrameRect()); d != nil && d.RefreshRate > 0 {
+			rate = d.RefreshRate
+		}
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// scheduleFrame marks the window for redraw at the next frame boundary, measured from the start of the previous frame
+// so that repeated requests don't drift.
+func (w *Window) scheduleFrame() {
+	if w.frameScheduled {
+		return
+	}
+	w.frameScheduled = true
+	delay := w.FrameInterval() - time.Since(w.lastFrameTime)
+	if delay <= 0 {
+		w.MarkForRedraw()
+		return
+	}
+	InvokeTaskAfter(func() {
+		if w.IsValid() && w.frameScheduled {
+			w.MarkForRedraw()
+		}
+	}, delay)
+}
+
+// runFrameCallbacks calls any pending frame callbacks, unless the window is hidden or minimized. Callbacks requested
+// while this is running are deferred to the next frame.
+func (w *Window) runFrameCallbacks() {
+	w.frameScheduled = false
+	w.lastFrameCallbacksDuration = 0
+	if len(w.frameCallbacks) == 0 {
+		return
+	}
+	if w.offscreen == nil && (!w.IsVisible() || w.IsMinimized()) {
+		// Leave them pending; the window will be redrawn when it is shown or restored, which will get them running
+		// again.
+		return
+	}
+	w.lastFrameTime = time.Now()
+	pending := w.frameCallbacks
+	w.frameCallbacks = nil
+	for _, one := range pending {
+		if one.panel == nil || one.panel.Window() == w {
+			mylog.Call(func() { one.callback(w.lastFrameTime) })
+		}
+	}
+	w.lastFrameCallbacksDuration = time.Since(w.lastFrameTime)
+}
+
+// LastFrameCallbacksDuration returns the amount of time spent in frame callbacks prior to the window's most recent
+// draw. This is not included in LastDrawDuration().
+func (w *Window) LastFrameCallbacksDuration() time.Duration {
+	return w.lastFrameCallbacksDuration
+}
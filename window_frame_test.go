@@ -0,0 +1,84 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ddkwork/toolbox/check"
+)
+
+func newFrameTestWindow(t *testing.T) (wnd *Window, child *Panel, draws *int) {
+	t.Helper()
+	var err error
+	wnd, err = NewWindow("", OffscreenWindowOption(Size{Width: 100, Height: 100}, 1))
+	check.NoError(t, err)
+	child = NewPanel()
+	wnd.Content().AddChild(child)
+	draws = new(int)
+	wnd.Content().DrawCallback = func(_ *Canvas, _ Rect) { *draws++ }
+	wnd.Show()
+	wnd.ValidateLayout()
+	return wnd, child, draws
+}
+
+func TestAnimationFrameRequestsCoalesce(t *testing.T) {
+	wnd, child, draws := newFrameTestWindow(t)
+	defer wnd.Dispose()
+	var times []time.Time
+	record := func(frameTime time.Time) { times = append(times, frameTime) }
+	wnd.RequestAnimationFrame(record)
+	wnd.RequestAnimationFrame(record)
+	check.True(t, child.RequestAnimationFrame(record) != 0)
+	canceled := wnd.RequestAnimationFrame(record)
+	wnd.CancelAnimationFrame(canceled)
+
+	drawMarkedWindows()
+	check.Equal(t, 1, *draws)
+	check.Equal(t, 3, len(times))
+	for _, one := range times {
+		check.Equal(t, wnd.LastFrameTime(), one, "every callback in a frame gets the same frame time")
+	}
+
+	drawMarkedWindows()
+	check.Equal(t, 1, *draws, "callbacks are only called once")
+	check.Equal(t, 3, len(times))
+}
+
+func TestAnimationFrameDroppedForRemovedPanel(t *testing.T) {
+	wnd, child, draws := newFrameTestWindow(t)
+	defer wnd.Dispose()
+	called := false
+	child.RequestAnimationFrame(func(_ time.Time) { called = true })
+	child.RemoveFromParent()
+	drawMarkedWindows()
+	check.Equal(t, 1, *draws)
+	check.False(t, called, "the panel is no longer in the window")
+	check.Equal(t, 0, len(wnd.frameCallbacks))
+	check.Equal(t, FrameCallbackID(0), child.RequestAnimationFrame(func(_ time.Time) { called = true }))
+}
+
+func TestAnimationFrameHeldWhileHidden(t *testing.T) {
+	wnd, _, draws := newFrameTestWindow(t)
+	defer wnd.Dispose()
+	wnd.Hide()
+	called := 0
+	wnd.RequestAnimationFrame(func(_ time.Time) { called++ })
+	drawMarkedWindows()
+	drawMarkedWindows()
+	check.Equal(t, 0, *draws)
+	check.Equal(t, 0, called)
+
+	wnd.Show()
+	drawMarkedWindows()
+	check.Equal(t, 1, *draws)
+	check.Equal(t, 1, called)
+}
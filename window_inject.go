@@ -0,0 +1,148 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+// The Inject* methods feed synthetic input into a window. The events are routed through the same modal, focus, and
+// panel dispatch logic as events received from the platform, so they can be used to drive a user interface from tests.
+// All locations are in window-local coordinates. These methods must be called on the UI thread.
+
+// InjectMouseDown simulates pressing a mouse button. Click counts are tracked exactly as they are for real events, so
+// calling this in quick succession at nearly the same location will produce double and triple clicks.
+func (w *Window) InjectMouseDown(where Point, button int, mod Modifiers) {
+	if w.IsValid() {
+		w.setInjectedMouseLocation(where)
+		w.mouseButton(where, button, true, mod)
+	}
+}
+
+// InjectMouseUp simulates releasing a mouse button.
+func (w *Window) InjectMouseUp(where Point, button int, mod Modifiers) {
+	if w.IsValid() {
+		w.setInjectedMouseLocation(where)
+		w.mouseButton(where, button, false, mod)
+	}
+}
+
+// InjectMouseMove simulates moving the mouse. If a mouse button is currently down, this results in a drag.
+func (w *Window) InjectMouseMove(where Point, mod Modifiers) {
+	if w.IsValid() {
+		w.setInjectedMouseLocation(where)
+		w.lastKeyModifiers = mod
+		w.mouseMoved(where, mod)
+	}
+}
+
+// InjectMouseEnter simulates the mouse entering the window.
+func (w *Window) InjectMouseEnter(where Point, mod Modifiers) {
+	if w.IsValid() {
+		w.setInjectedMouseLocation(where)
+		w.lastKeyModifiers = mod
+		w.mouseEnterOrExit(true, where, mod)
+	}
+}
+
+// InjectMouseExit simulates the mouse leaving the window.
+func (w *Window) InjectMouseExit() {
+	if w.IsValid() {
+		w.mouseEnterOrExit(false, Point{}, w.lastKeyModifiers)
+	}
+}
+
+// InjectMouseWheel simulates rotating the mouse wheel.
+func (w *Window) InjectMouseWheel(where, delta Point, mod Modifiers) {
+	if w.IsValid() {
+		w.setInjectedMouseLocation(where)
+		w.lastKeyModifiers = mod
+		w.mouseWheel(where, delta, mod)
+	}
+}
+
+// InjectClick simulates pressing and releasing a mouse button at the same location.
+func (w *Window) InjectClick(where Point, button int, mod Modifiers) {
+	w.InjectMouseDown(where, button, mod)
+	w.InjectMouseUp(where, button, mod)
+}
+
+// InjectDrag simulates pressing a mouse button at one location, moving to another location in the specified number of
+// intermediate steps, and then releasing the button.
+func (w *Window) InjectDrag(from, to Point, button, steps int, mod Modifiers) {
+	steps = max(steps, 1)
+	w.InjectMouseDown(from, button, mod)
+	for i := 1; i <= steps; i++ {
+		f := float32(i) / float32(steps)
+		w.InjectMouseMove(Point{X: from.X + (to.X-from.X)*f, Y: from.Y + (to.Y-from.Y)*f}, mod)
+	}
+	w.InjectMouseUp(to, button, mod)
+}
+
+// InjectKeyDown simulates pressing a key.
+func (w *Window) InjectKeyDown(keyCode KeyCode, mod Modifiers, repeat bool) {
+	if w.IsValid() && w.okToProcess() {
+		w.lastKeyModifiers = mod
+		w.keyDown(keyCode, mod, repeat)
+	}
+}
+
+// InjectKeyUp simulates releasing a key.
+func (w *Window) InjectKeyUp(keyCode KeyCode, mod Modifiers) {
+	if w.IsValid() && w.okToProcess() {
+		w.lastKeyModifiers = mod
+		w.keyUp(keyCode, mod)
+	}
+}
+
+// InjectKeyStroke simulates pressing and then releasing a key.
+func (w *Window) InjectKeyStroke(keyCode KeyCode, mod Modifiers) {
+	w.InjectKeyDown(keyCode, mod, false)
+	w.InjectKeyUp(keyCode, mod)
+}
+
+// InjectRuneTyped simulates typing a character.
+func (w *Window) InjectRuneTyped(ch rune) {
+	if w.IsValid() && w.okToProcess() {
+		w.runeTyped(ch)
+	}
+}
+
+// InjectText simulates typing each character of the text in turn.
+func (w *Window) InjectText(text string) {
+	for _, ch := range text {
+		w.InjectRuneTyped(ch)
+	}
+}
+
+// InjectFileDrop simulates files being dropped onto the window from the OS.
+func (w *Window) InjectFileDrop(where Point, files []string) {
+	if w.IsValid() && w.okToProcess() {
+		w.setInjectedMouseLocation(where)
+		w.fileDrop(where, files)
+	}
+}
+
+// InjectFocusChange simulates the window gaining or losing the keyboard focus.
+func (w *Window) InjectFocusChange(focused bool) {
+	if w.IsValid() {
+		if focused {
+			if w.okToProcess() {
+				w.gainedFocus()
+			} else {
+				modalStack[len(modalStack)-1].ToFront()
+			}
+		} else {
+			w.lostFocus()
+		}
+	}
+}
+
+func (w *Window) setInjectedMouseLocation(where Point) {
+	if w.offscreen != nil {
+		w.offscreen.mouseLocation = where
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func newInjectWindow(t *testing.T) (*unison.Window, *unison.Panel) {
+	t.Helper()
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 100, Height: 100}, 1))
+	check.NoError(t, err)
+	content := unison.NewPanel()
+	content.SetFocusable(true)
+	wnd.SetContent(content)
+	wnd.ToFront()
+	wnd.ValidateLayout()
+	return wnd, content
+}
+
+func TestInjectClickCounts(t *testing.T) {
+	wnd, content := newInjectWindow(t)
+	defer wnd.Dispose()
+	var counts []int
+	content.MouseDownCallback = func(_ unison.Point, _, clickCount int, _ unison.Modifiers) bool {
+		counts = append(counts, clickCount)
+		return true
+	}
+	where := unison.Point{X: 50, Y: 50}
+	wnd.InjectClick(where, unison.ButtonLeft, 0)
+	wnd.InjectClick(where, unison.ButtonLeft, 0)
+	wnd.InjectClick(unison.Point{X: 52, Y: 51}, unison.ButtonLeft, 0)
+	check.Equal(t, []int{1, 2, 3}, counts, "clicks in quick succession at nearly the same location are counted")
+
+	counts = nil
+	wnd.InjectClick(unison.Point{X: 70, Y: 50}, unison.ButtonLeft, 0)
+	wnd.InjectClick(unison.Point{X: 70, Y: 50}, unison.ButtonRight, 0)
+	check.Equal(t, []int{1, 1}, counts, "moving away or switching buttons starts a new count")
+}
+
+func TestInjectDrag(t *testing.T) {
+	wnd, content := newInjectWindow(t)
+	defer wnd.Dispose()
+	var gestures []bool
+	var upAt unison.Point
+	content.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool { return true }
+	content.MouseDragCallback = func(where unison.Point, _ int, _ unison.Modifiers) bool {
+		gestures = append(gestures, content.IsDragGesture(where))
+		return true
+	}
+	content.MouseUpCallback = func(where unison.Point, _ int, _ unison.Modifiers) bool {
+		upAt = where
+		return true
+	}
+	wnd.InjectMouseDown(unison.Point{X: 10, Y: 10}, unison.ButtonLeft, 0)
+	wnd.InjectMouseMove(unison.Point{X: 13, Y: 12}, 0)
+	wnd.InjectMouseMove(unison.Point{X: 20, Y: 10}, 0)
+	wnd.InjectMouseUp(unison.Point{X: 20, Y: 10}, unison.ButtonLeft, 0)
+	check.Equal(t, []bool{false, true}, gestures, "only movement beyond the drift threshold is a drag gesture")
+	check.Equal(t, unison.Point{X: 20, Y: 10}, upAt)
+
+	gestures = nil
+	wnd.InjectDrag(unison.Point{X: 10, Y: 10}, unison.Point{X: 50, Y: 90}, unison.ButtonLeft, 4, 0)
+	check.Equal(t, 4, len(gestures), "one drag event per step")
+	check.Equal(t, unison.Point{X: 50, Y: 90}, upAt)
+}
+
+func TestInjectWhileModal(t *testing.T) {
+	wnd, content := newInjectWindow(t)
+	defer wnd.Dispose()
+	var downs, keys int
+	content.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		downs++
+		return true
+	}
+	content.KeyDownCallback = func(_ unison.KeyCode, _ unison.Modifiers, _ bool) bool {
+		keys++
+		return true
+	}
+	content.RequestFocus()
+	modal, modalContent := newInjectWindow(t)
+	var modalDowns, modalUps int
+	modalContent.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		modalDowns++
+		return true
+	}
+	modalContent.MouseUpCallback = func(_ unison.Point, _ int, _ unison.Modifiers) bool {
+		modalUps++
+		return true
+	}
+	unison.InvokeTask(func() {
+		wnd.InjectClick(unison.Point{X: 50, Y: 50}, unison.ButtonLeft, 0)
+		wnd.InjectKeyStroke(unison.KeyA, 0)
+		modal.StopModal(unison.ModalResponseOK)
+	})
+	check.Equal(t, unison.ModalResponseOK, modal.RunModal())
+	check.Equal(t, 0, downs, "mouse buttons go to the modal window instead")
+	check.Equal(t, 0, keys, "keys are dropped while another window is modal")
+	check.Equal(t, 1, modalDowns)
+	check.Equal(t, 1, modalUps)
+
+	wnd.InjectClick(unison.Point{X: 50, Y: 50}, unison.ButtonLeft, 0)
+	wnd.InjectKeyStroke(unison.KeyA, 0)
+	check.Equal(t, 1, downs)
+	check.Equal(t, 1, keys)
+}
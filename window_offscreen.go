@@ -0,0 +1,104 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison
+
+import (
+	"time"
+
+	"github.com/ddkwork/toolbox/errs"
+	"github.com/ddkwork/unison/internal/skia"
+)
+
+type offscreenWindow struct {
+	window        *Window
+	rect          Rect
+	mouseLocation Point
+	scale         float32
+	visible       bool
+}
+
+// OffscreenWindowOption causes the window to be created without a platform window, rendering into a raster surface of
+// the given size and backing scale instead. Offscreen windows do not require a display, a GPU, or for Start() to have
+// been called, which makes them suitable for use in tests and other headless environments. They are never part of the
+// list returned by Windows() and are never considered the active window. Their size only changes when explicitly
+// requested via calls such as SetContentRect() or Pack().
+func OffscreenWindowOption(size Size, scale float32) WindowOption {
+	return func(w *Window) error {
+		if scale <= 0 {
+			return errs.New("invalid scale")
+		}
+		if size.Width < 1 || size.Height < 1 {
+			return errs.New("invalid size")
+		}
+		w.offscreen = &offscreenWindow{
+			window: w,
+			rect:   Rect{Size: size},
+			scale:  scale,
+		}
+		return nil
+	}
+}
+
+func (o *offscreenWindow) init() {
+	if skiaColorspace == nil {
+		skiaColorspace = skia.ColorSpaceNewSRGB()
+	}
+	o.window.lastContentRect = o.rect
+}
+
+func (o *offscreenWindow) setContentRect(rect Rect) {
+	rect = o.window.adjustContentRectForMinMax(rect)
+	rect.Width = max(rect.Width, 1)
+	rect.Height = max(rect.Height, 1)
+	moved := rect.Point != o.rect.Point
+	resized := rect.Size != o.rect.Size
+	o.rect = rect
+	if moved {
+		o.window.moved()
+	}
+	if resized {
+		o.window.resized()
+	}
+}
+
+// IsOffscreen returns true if this window was created with OffscreenWindowOption().
+func (w *Window) IsOffscreen() bool {
+	return w.offscreen != nil
+}
+
+// Snapshot lays out and draws the contents of an offscreen window, then returns the resulting framebuffer as an image.
+// The image will have pixel dimensions equal to the content size multiplied by the backing scale. Returns an error if
+// this is not a valid offscreen window.
+func (w *Window) Snapshot() (*Image, error) {
+	if !w.IsOffscreen() || !w.IsValid() {
+		return nil, errs.New("not a valid offscreen window")
+	}
+	RebuildDynamicColors()
+	delete(redrawSet, w)
+	w.runFrameCallbacks()
+	if err := w.drawOffscreen(); err != nil {
+		return nil, err
+	}
+	return imageFromSurface(w.surface.surface, 1/w.offscreen.scale)
+}
+
+func (w *Window) drawOffscreen() error {
+	c, err := w.surface.prepareRasterCanvas(w.offscreen.rect.Size, w.offscreen.scale, w.offscreen.scale)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	c.Save()
+	w.Draw(c)
+	c.Restore()
+	c.Flush()
+	w.lastDrawDuration = time.Since(start)
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright ©2021-2022 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package unison_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ddkwork/toolbox/check"
+	"github.com/ddkwork/unison"
+)
+
+func TestOffscreenWindowOption(t *testing.T) {
+	check.Error(t, unison.OffscreenWindowOption(unison.Size{Width: 10, Height: 10}, 0)(&unison.Window{}))
+	check.Error(t, unison.OffscreenWindowOption(unison.Size{Width: 0, Height: 10}, 1)(&unison.Window{}))
+
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 120, Height: 80}, 1))
+	check.NoError(t, err)
+	defer wnd.Dispose()
+	check.True(t, wnd.IsOffscreen())
+	check.Equal(t, unison.Size{Width: 120, Height: 80}, wnd.ContentRect().Size)
+	check.False(t, slices.Contains(unison.Windows(), wnd), "offscreen windows are not listed")
+	check.True(t, unison.ActiveWindow() != wnd, "offscreen windows are never active")
+}
+
+func TestOffscreenWindowSnapshot(t *testing.T) {
+	wnd, err := unison.NewWindow("", unison.OffscreenWindowOption(unison.Size{Width: 30, Height: 20}, 2))
+	check.NoError(t, err)
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlowLayout{})
+	var drawn []unison.Rect
+	content.DrawCallback = func(_ *unison.Canvas, dirty unison.Rect) { drawn = append(drawn, dirty) }
+	wnd.SetContent(content)
+
+	img, err := wnd.Snapshot()
+	check.NoError(t, err)
+	check.Equal(t, unison.Size{Width: 60, Height: 40}, img.Size(), "pixel size is the content size times the scale")
+	check.Equal(t, unison.Size{Width: 30, Height: 20}, img.LogicalSize())
+	check.Equal(t, unison.Size{Width: 30, Height: 20}, content.FrameRect().Size, "the content is laid out first")
+	check.Equal(t, 1, len(drawn))
+
+	wnd.SetContentRect(unison.Rect{Size: unison.Size{Width: 50, Height: 25}})
+	img, err = wnd.Snapshot()
+	check.NoError(t, err)
+	check.Equal(t, unison.Size{Width: 100, Height: 50}, img.Size(), "resizing the window resizes the snapshot")
+	check.Equal(t, unison.Size{Width: 50, Height: 25}, content.FrameRect().Size)
+
+	wnd.Dispose()
+	_, err = wnd.Snapshot()
+	check.Error(t, err, "disposed windows can't be snapshotted")
+}